@@ -0,0 +1,413 @@
+package catalog
+
+import (
+	"strings"
+
+	"github.com/AfterShip/clickhouse-sql-parser/parser"
+)
+
+func (c *Catalog) alterTable(stmt *parser.AlterTable) error {
+	table := c.lookup(stmt.TableIdentifier)
+	if table == nil {
+		return errorf(stmt.Pos(), "ALTER on an unknown table: %s", qualifiedName(c, stmt.TableIdentifier))
+	}
+	if table.Kind == KindDictionary {
+		return errorf(stmt.Pos(), "ALTER on a DICTIONARY: %s", table.qualifiedName())
+	}
+	// the clauses are applied to a copy, so that the table is left unchanged
+	// if one of them fails
+	altered := table.copy()
+	for _, clause := range stmt.AlterExprs {
+		if err := altered.alter(clause); err != nil {
+			return err
+		}
+	}
+	*table = *altered
+	return nil
+}
+
+// alter applies an ALTER clause to t. The clauses that only change the data,
+// such as DELETE or DROP PARTITION, leave t unchanged.
+func (t *Table) alter(clause parser.AlterTableClause) error {
+	switch clause := clause.(type) {
+	case *parser.AlterTableAddColumn:
+		if clause.IfNotExists && t.columnIndex(columnName(clause.Column.Name)) >= 0 {
+			return nil
+		}
+		return t.addColumn("ADD COLUMN", clause.Column, clause.After, clause.First)
+	case *parser.AlterTableDropColumn:
+		i, err := t.findColumn("DROP COLUMN", clause, clause.ColumnName, clause.IfExists)
+		if i < 0 {
+			return err
+		}
+		if t.keyColumns()[t.Columns[i].Name] {
+			return errorf(clause.Pos(), "DROP COLUMN on a key column: %s", t.Columns[i].Name)
+		}
+		t.Columns = append(t.Columns[:i:i], t.Columns[i+1:]...)
+	case *parser.AlterTableModifyColumn:
+		i, err := t.findColumn("MODIFY COLUMN", clause, clause.Column.Name, clause.IfExists)
+		if i < 0 {
+			return err
+		}
+		def := modifyColumn(t.Columns[i].Def, clause)
+		column, err := newColumn(def)
+		if err != nil {
+			return errorf(clause.Pos(), "MODIFY COLUMN with an %s", err)
+		}
+		t.Columns[i] = column
+	case *parser.AlterTableRenameColumn:
+		i, err := t.findColumn("RENAME COLUMN", clause, clause.OldColumnName, clause.IfExists)
+		if i < 0 {
+			return err
+		}
+		if t.keyColumns()[t.Columns[i].Name] {
+			return errorf(clause.Pos(), "RENAME COLUMN on a key column: %s", t.Columns[i].Name)
+		}
+		name := columnName(clause.NewColumnName)
+		if t.columnIndex(name) >= 0 {
+			return errorf(clause.Pos(), "RENAME COLUMN to a column that already exists: %s", name)
+		}
+		def := *t.Columns[i].Def
+		def.Name = clause.NewColumnName
+		column := *t.Columns[i]
+		column.Name = name
+		column.Def = &def
+		t.Columns[i] = &column
+	case *parser.AlterTableCommentColumn:
+		i, err := t.findColumn("COMMENT COLUMN", clause, clause.ColumnName, clause.IfExists)
+		if i < 0 {
+			return err
+		}
+		def := *t.Columns[i].Def
+		def.Comment = clause.Comment
+		column := *t.Columns[i]
+		column.Def = &def
+		t.Columns[i] = &column
+	case *parser.AlterTableClearColumn:
+		_, err := t.findColumn("CLEAR COLUMN", clause, clause.ColumnName, clause.IfExists)
+		return err
+
+	case *parser.AlterTableAddIndex:
+		if clause.IfNotExists && t.indexIndex(columnName(clause.Index.Name)) >= 0 {
+			return nil
+		}
+		return t.addIndex("ADD INDEX", clause.Index, clause.After)
+	case *parser.AlterTableDropIndex:
+		i, err := t.findIndex("DROP INDEX", clause, clause.IndexName, clause.IfExists)
+		if i >= 0 {
+			t.Indexes = append(t.Indexes[:i:i], t.Indexes[i+1:]...)
+		}
+		return err
+	case *parser.AlterTableClearIndex:
+		_, err := t.findIndex("CLEAR INDEX", clause, clause.IndexName, clause.IfExists)
+		return err
+	case *parser.AlterTableMaterializeIndex:
+		_, err := t.findIndex("MATERIALIZE INDEX", clause, clause.IndexName, clause.IfExists)
+		return err
+
+	case *parser.AlterTableAddProjection:
+		if clause.IfNotExists && t.projectionIndex(columnName(clause.TableProjection.Identifier)) >= 0 {
+			return nil
+		}
+		return t.addProjection("ADD PROJECTION", clause.TableProjection, clause.After)
+	case *parser.AlterTableDropProjection:
+		i, err := t.findProjection("DROP PROJECTION", clause, clause.ProjectionName, clause.IfExists)
+		if i >= 0 {
+			t.Projections = append(t.Projections[:i:i], t.Projections[i+1:]...)
+		}
+		return err
+	case *parser.AlterTableClearProjection:
+		_, err := t.findProjection("CLEAR PROJECTION", clause, clause.ProjectionName, clause.IfExists)
+		return err
+	case *parser.AlterTableMaterializeProjection:
+		_, err := t.findProjection("MATERIALIZE PROJECTION", clause, clause.ProjectionName, clause.IfExists)
+		return err
+
+	case *parser.AlterTableAddConstraint:
+		if clause.IfNotExists && t.constraintIndex(clause.Name.Name) >= 0 {
+			return nil
+		}
+		return t.addConstraint("ADD CONSTRAINT", &parser.ConstraintClause{
+			ConstraintPos: clause.AddPos,
+			Constraint:    clause.Name,
+			Assume:        clause.Assume,
+			Expr:          clause.Expr,
+		})
+	case *parser.AlterTableDropConstraint:
+		i := t.constraintIndex(clause.Name.Name)
+		if i < 0 {
+			if clause.IfExists {
+				return nil
+			}
+			return errorf(clause.Pos(), "DROP CONSTRAINT on an unknown constraint: %s", clause.Name.Name)
+		}
+		t.Constraints = append(t.Constraints[:i:i], t.Constraints[i+1:]...)
+
+	case *parser.AlterTableModifyOrderBy:
+		if t.Engine == nil {
+			return errorf(clause.Pos(), "MODIFY ORDER BY on a %s without an engine: %s", t.Kind, t.qualifiedName())
+		}
+		t.Engine.OrderBy = []parser.Expr{clause.OrderBy}
+	case *parser.AlterTableModifySampleBy:
+		if t.Engine == nil {
+			return errorf(clause.Pos(), "MODIFY SAMPLE BY on a %s without an engine: %s", t.Kind, t.qualifiedName())
+		}
+		t.Engine.SampleBy = clause.SampleBy
+	case *parser.AlterTableModifyTTL:
+		if t.Engine == nil {
+			return errorf(clause.Pos(), "MODIFY TTL on a %s without an engine: %s", t.Kind, t.qualifiedName())
+		}
+		t.Engine.TTL = clause.TTL.Items
+	case *parser.AlterTableRemoveTTL:
+		if t.Engine == nil || len(t.Engine.TTL) == 0 {
+			return errorf(clause.Pos(), "REMOVE TTL on a table without a TTL: %s", t.qualifiedName())
+		}
+		t.Engine.TTL = nil
+	case *parser.AlterTableModifySetting:
+		if t.Engine == nil {
+			return errorf(clause.Pos(), "MODIFY SETTING on a %s without an engine: %s", t.Kind, t.qualifiedName())
+		}
+		for _, setting := range clause.Settings {
+			t.Engine.Settings = setSetting(t.Engine.Settings, setting)
+		}
+	case *parser.AlterTableResetSetting:
+		if t.Engine == nil {
+			return errorf(clause.Pos(), "RESET SETTING on a %s without an engine: %s", t.Kind, t.qualifiedName())
+		}
+		for _, name := range clause.Settings {
+			t.Engine.Settings = resetSetting(t.Engine.Settings, name.Name)
+		}
+	case *parser.AlterTableModifyComment:
+		t.Comment = clause.Comment
+	case *parser.AlterTableModifyQuery:
+		if t.Kind != KindView && t.Kind != KindMaterializedView {
+			return errorf(clause.Pos(), "MODIFY QUERY on a %s: %s", t.Kind, t.qualifiedName())
+		}
+		t.Query = clause.SelectExpr
+	}
+	return nil
+}
+
+// columnIndex returns the index of the column called name in t.Columns, or
+// -1.
+func (t *Table) columnIndex(name string) int {
+	for i, column := range t.Columns {
+		if column.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Table) indexIndex(name string) int {
+	for i, index := range t.Indexes {
+		if index.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Table) projectionIndex(name string) int {
+	for i, projection := range t.Projections {
+		if projection.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Table) constraintIndex(name string) int {
+	for i, constraint := range t.Constraints {
+		if constraint.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// keyColumns returns the names of the columns the sorting, primary,
+// partition and sampling keys of t refer to, which ClickHouse does not let
+// ALTER drop or rename.
+func (t *Table) keyColumns() map[string]bool {
+	columns := map[string]bool{}
+	if t.Engine == nil {
+		return columns
+	}
+	functions := map[*parser.Ident]bool{}
+	keys := append([]parser.Expr{t.Engine.PartitionBy, t.Engine.PrimaryKey, t.Engine.SampleBy}, t.Engine.OrderBy...)
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		parser.Walk(key, func(node parser.Expr) bool {
+			switch node := node.(type) {
+			case *parser.FunctionExpr:
+				functions[node.Name] = true
+			case *parser.Ident:
+				if !functions[node] {
+					columns[node.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	return columns
+}
+
+// findColumn returns the index of the column a clause refers to, or -1 with
+// an error unless the clause has IF EXISTS.
+func (t *Table) findColumn(op string, clause parser.Expr, name *parser.NestedIdentifier, ifExists bool) (int, error) {
+	i := t.columnIndex(columnName(name))
+	if i < 0 && !ifExists {
+		return i, errorf(clause.Pos(), "%s on an unknown column: %s", op, columnName(name))
+	}
+	return i, nil
+}
+
+func (t *Table) findIndex(op string, clause parser.Expr, name *parser.NestedIdentifier, ifExists bool) (int, error) {
+	i := t.indexIndex(columnName(name))
+	if i < 0 && !ifExists {
+		return i, errorf(clause.Pos(), "%s on an unknown index: %s", op, columnName(name))
+	}
+	return i, nil
+}
+
+func (t *Table) findProjection(op string, clause parser.Expr, name *parser.NestedIdentifier, ifExists bool) (int, error) {
+	i := t.projectionIndex(columnName(name))
+	if i < 0 && !ifExists {
+		return i, errorf(clause.Pos(), "%s on an unknown projection: %s", op, columnName(name))
+	}
+	return i, nil
+}
+
+// insertAt returns the position to insert an element at: after the element
+// at index after, or at the end.
+func insertAt(op, kind string, pos parser.Pos, length int, after *parser.NestedIdentifier, index func(string) int) (int, error) {
+	if after == nil {
+		return length, nil
+	}
+	i := index(columnName(after))
+	if i < 0 {
+		return 0, errorf(pos, "%s after an unknown %s: %s", op, kind, columnName(after))
+	}
+	return i + 1, nil
+}
+
+func (t *Table) addColumn(op string, def *parser.ColumnDef, after *parser.NestedIdentifier, first bool) error {
+	name := columnName(def.Name)
+	if t.columnIndex(name) >= 0 {
+		return errorf(def.Pos(), "%s on a column that already exists: %s", op, name)
+	}
+	i, err := insertAt(op, "column", def.Pos(), len(t.Columns), after, t.columnIndex)
+	if err != nil {
+		return err
+	}
+	if first {
+		i = 0
+	}
+	column, err := newColumn(def)
+	if err != nil {
+		return errorf(def.Pos(), "%s with an %s", op, err)
+	}
+	t.Columns = append(t.Columns[:i:i], append([]*Column{column}, t.Columns[i:]...)...)
+	return nil
+}
+
+func (t *Table) addIndex(op string, def *parser.TableIndex, after *parser.NestedIdentifier) error {
+	name := columnName(def.Name)
+	if t.indexIndex(name) >= 0 {
+		return errorf(def.Pos(), "%s on an index that already exists: %s", op, name)
+	}
+	i, err := insertAt(op, "index", def.Pos(), len(t.Indexes), after, t.indexIndex)
+	if err != nil {
+		return err
+	}
+	index := &Index{Name: name, Def: def}
+	t.Indexes = append(t.Indexes[:i:i], append([]*Index{index}, t.Indexes[i:]...)...)
+	return nil
+}
+
+func (t *Table) addProjection(op string, def *parser.TableProjection, after *parser.NestedIdentifier) error {
+	name := columnName(def.Identifier)
+	if t.projectionIndex(name) >= 0 {
+		return errorf(def.Pos(), "%s on a projection that already exists: %s", op, name)
+	}
+	i, err := insertAt(op, "projection", def.Pos(), len(t.Projections), after, t.projectionIndex)
+	if err != nil {
+		return err
+	}
+	// the projections of CREATE TABLE are written with the keyword
+	projection := *def
+	projection.IncludeProjectionKeyword = true
+	t.Projections = append(t.Projections[:i:i],
+		append([]*Projection{{Name: name, Def: &projection}}, t.Projections[i:]...)...)
+	return nil
+}
+
+func (t *Table) addConstraint(op string, def *parser.ConstraintClause) error {
+	name := def.Constraint.Name
+	if t.constraintIndex(name) >= 0 {
+		return errorf(def.Pos(), "%s on a constraint that already exists: %s", op, name)
+	}
+	t.Constraints = append(t.Constraints, &Constraint{Name: name, Def: def})
+	return nil
+}
+
+// modifyColumn returns the definition of a column after MODIFY COLUMN. As in
+// ClickHouse, the properties the clause does not mention are kept: MODIFY
+// COLUMN c String keeps the default, codec, TTL and comment of c.
+func modifyColumn(old *parser.ColumnDef, clause *parser.AlterTableModifyColumn) *parser.ColumnDef {
+	def := *old
+	if remove := clause.RemovePropertyType; remove != nil {
+		if property, ok := remove.PropertyType.(*parser.PropertyType); ok {
+			switch strings.ToUpper(property.Name.Name) {
+			case "DEFAULT", "MATERIALIZED", "ALIAS":
+				def.DefaultExpr, def.MaterializedExpr, def.AliasExpr = nil, nil, nil
+			case "CODEC":
+				def.Codec, def.CompressionCodec = nil, nil
+			case "TTL":
+				def.TTL = nil
+			case "COMMENT":
+				def.Comment = nil
+			}
+		}
+		return &def
+	}
+	update := clause.Column
+	if update.Type != nil {
+		def.Type, def.NotNull, def.Nullable = update.Type, update.NotNull, update.Nullable
+	}
+	if update.DefaultExpr != nil || update.MaterializedExpr != nil || update.AliasExpr != nil {
+		def.DefaultExpr, def.MaterializedExpr, def.AliasExpr = update.DefaultExpr, update.MaterializedExpr, update.AliasExpr
+	}
+	if update.Codec != nil || update.CompressionCodec != nil {
+		def.Codec, def.CompressionCodec = update.Codec, update.CompressionCodec
+	}
+	if update.TTL != nil {
+		def.TTL = update.TTL
+	}
+	if update.Comment != nil {
+		def.Comment = update.Comment
+	}
+	return &def
+}
+
+func setSetting(settings []*parser.SettingExpr, setting *parser.SettingExpr) []*parser.SettingExpr {
+	for i, s := range settings {
+		if s.Name.Name == setting.Name.Name {
+			settings[i] = setting
+			return settings
+		}
+	}
+	return append(settings, setting)
+}
+
+func resetSetting(settings []*parser.SettingExpr, name string) []*parser.SettingExpr {
+	for i, s := range settings {
+		if s.Name.Name == name {
+			return append(settings[:i:i], settings[i+1:]...)
+		}
+	}
+	return settings
+}
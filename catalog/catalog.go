@@ -0,0 +1,709 @@
+// Package catalog models a ClickHouse schema in memory. A Catalog starts with
+// the default database and changes as DDL statements, such as the migrations
+// of a project, are applied to it in order: CREATE, ALTER, DROP and RENAME of
+// databases, tables, views and dictionaries. A statement ClickHouse would
+// reject, such as an ALTER of a table that does not exist, is reported as an
+// *Error and leaves the catalog unchanged. Statements exports the catalog back
+// to canonical CREATE statements.
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/AfterShip/clickhouse-sql-parser/parser"
+	"github.com/AfterShip/clickhouse-sql-parser/types"
+)
+
+// DefaultDatabase is the database of the tables whose names are not
+// qualified, until a USE statement changes it.
+const DefaultDatabase = "default"
+
+// Catalog is an in-memory model of the databases and tables of a schema.
+type Catalog struct {
+	current   string
+	databases []*Database
+	// tables are all the tables of the databases, in the order they were
+	// created, so that the statements exported recreate the tables before
+	// the views that read them.
+	tables []*Table
+}
+
+// Database is a database of a Catalog.
+type Database struct {
+	Name    string
+	Engine  *parser.EngineExpr
+	Comment *parser.StringLiteral
+
+	catalog *Catalog
+	ident   *parser.Ident
+	// implicit is set for the default database, which exists without being
+	// created.
+	implicit bool
+}
+
+// Kind is the kind of a Table.
+type Kind int
+
+const (
+	KindTable Kind = iota
+	KindView
+	KindMaterializedView
+	KindDictionary
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindTable:
+		return "TABLE"
+	case KindView:
+		return "VIEW"
+	case KindMaterializedView:
+		return "MATERIALIZED VIEW"
+	case KindDictionary:
+		return "DICTIONARY"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Table is a table, view, materialized view or dictionary of a Database.
+type Table struct {
+	Database string
+	Name     string
+	Kind     Kind
+
+	Columns     []*Column
+	Indexes     []*Index
+	Projections []*Projection
+	Constraints []*Constraint
+
+	// Engine is the engine of a table, or of a materialized view storing its
+	// rows itself, and nil for the other kinds.
+	Engine *Engine
+	// Query is the SELECT of a view or materialized view, or of a table
+	// created AS SELECT.
+	Query parser.Expr
+	// To is the table a materialized view writes its rows to, if any.
+	To      *parser.TableIdentifier
+	Comment *parser.StringLiteral
+
+	databaseIdent *parser.Ident
+	ident         *parser.Ident
+	// create is the statement that created the table, for the parts of it
+	// the model does not cover, such as the source and layout of a
+	// dictionary.
+	create parser.Expr
+}
+
+// Column is a column of a Table.
+type Column struct {
+	Name string
+	// Type is the normalized type of the column, or nil if it was declared
+	// without one.
+	Type *types.Type
+	// Def is the definition of the column as in CREATE TABLE, with the
+	// changes of the ALTER statements applied.
+	Def *parser.ColumnDef
+}
+
+// Index is a data skipping index of a Table.
+type Index struct {
+	Name string
+	Def  *parser.TableIndex
+}
+
+// Projection is a projection of a Table.
+type Projection struct {
+	Name string
+	Def  *parser.TableProjection
+}
+
+// Constraint is a CHECK or ASSUME constraint of a Table.
+type Constraint struct {
+	Name string
+	Def  *parser.ConstraintClause
+}
+
+// Engine is the engine of a Table with the clauses that come with it.
+type Engine struct {
+	Name   string
+	Params *parser.ParamExprList
+
+	// OrderBy are the expressions of the sorting key as written, usually a
+	// single column or tuple.
+	OrderBy     []parser.Expr
+	PartitionBy parser.Expr
+	PrimaryKey  parser.Expr
+	SampleBy    parser.Expr
+	TTL         []*parser.TTLExpr
+	Settings    []*parser.SettingExpr
+}
+
+// Error is a statement that cannot be applied to a Catalog.
+type Error struct {
+	// Pos is the position of the statement, or of the ALTER clause, that
+	// failed.
+	Pos parser.Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func errorf(pos parser.Pos, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// New returns a Catalog with only the default database.
+func New() *Catalog {
+	c := &Catalog{current: DefaultDatabase}
+	c.databases = []*Database{{
+		Name:     DefaultDatabase,
+		catalog:  c,
+		ident:    &parser.Ident{Name: DefaultDatabase},
+		implicit: true,
+	}}
+	return c
+}
+
+// Databases returns the databases of c in the order they were created.
+func (c *Catalog) Databases() []*Database {
+	return append([]*Database(nil), c.databases...)
+}
+
+// Database returns the database called name, or nil.
+func (c *Catalog) Database(name string) *Database {
+	for _, database := range c.databases {
+		if database.Name == name {
+			return database
+		}
+	}
+	return nil
+}
+
+// Table returns the table, view or dictionary called name in database, or in
+// the current database if database is empty, or nil.
+func (c *Catalog) Table(database, name string) *Table {
+	if database == "" {
+		database = c.current
+	}
+	for _, table := range c.tables {
+		if table.Database == database && table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Tables returns the tables, views and dictionaries of d in the order they
+// were created.
+func (d *Database) Tables() []*Table {
+	var tables []*Table
+	for _, table := range d.catalog.tables {
+		if table.Database == d.Name {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// Table returns the table, view or dictionary of d called name, or nil.
+func (d *Database) Table(name string) *Table {
+	return d.catalog.Table(d.Name, name)
+}
+
+// Column returns the column of t called name, or nil.
+func (t *Table) Column(name string) *Column {
+	if i := t.columnIndex(name); i >= 0 {
+		return t.Columns[i]
+	}
+	return nil
+}
+
+// Setting returns the value of the engine setting called name, or nil.
+func (e *Engine) Setting(name string) parser.Expr {
+	for _, setting := range e.Settings {
+		if setting.Name.Name == name {
+			return setting.Expr
+		}
+	}
+	return nil
+}
+
+// ApplySQL parses sql and applies its statements in order. It stops at the
+// first statement that fails; the positions of an *Error are offsets into
+// sql.
+func (c *Catalog) ApplySQL(sql string) error {
+	stmts, err := parser.NewParser(sql).ParseStmts()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := c.Apply(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply applies a statement to c. Statements that do not change the schema,
+// such as SELECT or INSERT, and the ones c does not model, such as CREATE
+// USER or CREATE TEMPORARY TABLE, are ignored. ON CLUSTER is ignored, as c
+// models a single server. A statement that fails leaves c unchanged.
+func (c *Catalog) Apply(stmt parser.Expr) error {
+	// the catalog keeps parts of the statement, which the caller may change
+	stmt = parser.Clone(stmt)
+	switch stmt := stmt.(type) {
+	case *parser.CreateDatabase:
+		return c.createDatabase(stmt)
+	case *parser.CreateTable:
+		return c.createTable(stmt)
+	case *parser.CreateView:
+		return c.createView(stmt)
+	case *parser.CreateMaterializedView:
+		return c.createMaterializedView(stmt)
+	case *parser.CreateDictionary:
+		return c.createDictionary(stmt)
+	case *parser.AlterTable:
+		return c.alterTable(stmt)
+	case *parser.DropDatabase:
+		return c.dropDatabase(stmt)
+	case *parser.DropStmt:
+		return c.dropTable(stmt)
+	case *parser.RenameStmt:
+		return c.rename(stmt)
+	case *parser.UseStmt:
+		if c.Database(stmt.Database.Name) == nil {
+			return errorf(stmt.Pos(), "USE of an unknown database: %s", stmt.Database.Name)
+		}
+		c.current = stmt.Database.Name
+	}
+	return nil
+}
+
+func (c *Catalog) createDatabase(stmt *parser.CreateDatabase) error {
+	ident, ok := stmt.Name.(*parser.Ident)
+	if !ok {
+		return errorf(stmt.Pos(), "CREATE DATABASE with an invalid name: %s", parser.Format(stmt.Name))
+	}
+	if c.Database(ident.Name) != nil {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return errorf(stmt.Pos(), "CREATE DATABASE on a database that already exists: %s", ident.Name)
+	}
+	c.databases = append(c.databases, &Database{
+		Name:    ident.Name,
+		Engine:  stmt.Engine,
+		Comment: stmt.Comment,
+		catalog: c,
+		ident:   ident,
+	})
+	return nil
+}
+
+// newTable returns a table called name, checking that its database exists and
+// that the name is free unless the statement replaces the table or does
+// nothing if it exists. It returns a nil table if there is nothing to do.
+func (c *Catalog) newTable(pos parser.Pos, op string, name *parser.TableIdentifier, ifNotExists, orReplace bool) (*Table, error) {
+	database := c.database(name)
+	if database == nil {
+		return nil, errorf(pos, "%s in an unknown database: %s", op, databaseName(c, name))
+	}
+	if existing := database.Table(name.Table.Name); existing != nil {
+		if ifNotExists {
+			return nil, nil
+		}
+		if !orReplace {
+			return nil, errorf(pos, "%s on a table that already exists: %s", op, existing.qualifiedName())
+		}
+	}
+	return &Table{
+		Database:      database.Name,
+		Name:          name.Table.Name,
+		databaseIdent: database.ident,
+		ident:         name.Table,
+	}, nil
+}
+
+// add adds table to c, replacing the table of the same name if any.
+func (c *Catalog) add(table *Table) {
+	c.remove(c.Table(table.Database, table.Name))
+	c.tables = append(c.tables, table)
+}
+
+func (c *Catalog) remove(table *Table) {
+	for i, t := range c.tables {
+		if t == table {
+			c.tables = append(c.tables[:i:i], c.tables[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Catalog) createTable(stmt *parser.CreateTable) error {
+	if stmt.HasTemporary {
+		return nil
+	}
+	table, err := c.newTable(stmt.Pos(), "CREATE TABLE", stmt.Name, stmt.IfNotExists, stmt.OrReplace)
+	if table == nil {
+		return err
+	}
+	table.Kind = KindTable
+	table.Comment = stmt.Comment
+	table.create = stmt
+	if schema := stmt.TableSchema; schema != nil {
+		if schema.AliasTable != nil {
+			source := c.lookup(schema.AliasTable)
+			if source == nil {
+				return errorf(stmt.Pos(), "CREATE TABLE AS an unknown table: %s", qualifiedName(c, schema.AliasTable))
+			}
+			copied := source.copy()
+			table.Columns = copied.Columns
+			table.Indexes = copied.Indexes
+			table.Projections = copied.Projections
+			table.Constraints = copied.Constraints
+			table.Engine = copied.Engine
+		}
+		if err := table.addElements("CREATE TABLE", schema.Columns); err != nil {
+			return err
+		}
+	}
+	if stmt.Engine != nil {
+		table.Engine = newEngine(stmt.Engine)
+	}
+	if stmt.SubQuery != nil {
+		table.Query = stmt.SubQuery.Select
+	}
+	c.add(table)
+	return nil
+}
+
+func (c *Catalog) createView(stmt *parser.CreateView) error {
+	table, err := c.newTable(stmt.Pos(), "CREATE VIEW", stmt.Name, stmt.IfNotExists, stmt.OrReplace)
+	if table == nil {
+		return err
+	}
+	table.Kind = KindView
+	table.Comment = stmt.Comment
+	table.create = stmt
+	if stmt.TableSchema != nil {
+		if err := table.addElements("CREATE VIEW", stmt.TableSchema.Columns); err != nil {
+			return err
+		}
+	}
+	if stmt.SubQuery != nil {
+		table.Query = stmt.SubQuery.Select
+	}
+	c.add(table)
+	return nil
+}
+
+func (c *Catalog) createMaterializedView(stmt *parser.CreateMaterializedView) error {
+	table, err := c.newTable(stmt.Pos(), "CREATE MATERIALIZED VIEW", stmt.Name, stmt.IfNotExists, false)
+	if table == nil {
+		return err
+	}
+	table.Kind = KindMaterializedView
+	table.Comment = stmt.Comment
+	table.create = stmt
+	schema := stmt.TableSchema
+	if stmt.Destination != nil {
+		to := stmt.Destination.TableIdentifier
+		if to.Database == nil {
+			// the exported statement is qualified, so must be its destination
+			database := c.database(to)
+			if database == nil {
+				return errorf(stmt.Pos(), "CREATE MATERIALIZED VIEW TO a table in an unknown database: %s", databaseName(c, to))
+			}
+			to = &parser.TableIdentifier{Database: database.ident, Table: to.Table}
+			stmt.Destination.TableIdentifier = to
+		}
+		table.To = to
+		schema = stmt.Destination.TableSchema
+	}
+	if schema != nil {
+		if err := table.addElements("CREATE MATERIALIZED VIEW", schema.Columns); err != nil {
+			return err
+		}
+	}
+	if stmt.Engine != nil {
+		table.Engine = newEngine(stmt.Engine)
+	}
+	if stmt.SubQuery != nil {
+		table.Query = stmt.SubQuery.Select
+	}
+	c.add(table)
+	return nil
+}
+
+func (c *Catalog) createDictionary(stmt *parser.CreateDictionary) error {
+	table, err := c.newTable(stmt.Pos(), "CREATE DICTIONARY", stmt.Name, stmt.IfNotExists, stmt.OrReplace)
+	if table == nil {
+		return err
+	}
+	table.Kind = KindDictionary
+	table.Comment = stmt.Comment
+	table.create = stmt
+	if stmt.Schema != nil {
+		for _, attribute := range stmt.Schema.Attributes {
+			def := &parser.ColumnDef{
+				NamePos:   attribute.NamePos,
+				ColumnEnd: attribute.AttrEnd,
+				Name:      &parser.NestedIdentifier{Ident: attribute.Name},
+				Type:      attribute.Type,
+			}
+			if err := table.addElements("CREATE DICTIONARY", []parser.Expr{def}); err != nil {
+				return err
+			}
+		}
+	}
+	c.add(table)
+	return nil
+}
+
+func (c *Catalog) dropDatabase(stmt *parser.DropDatabase) error {
+	database := c.Database(stmt.Name.Name)
+	if database == nil {
+		if stmt.IfExists {
+			return nil
+		}
+		return errorf(stmt.Pos(), "DROP DATABASE on an unknown database: %s", stmt.Name.Name)
+	}
+	for _, table := range database.Tables() {
+		c.remove(table)
+	}
+	for i, d := range c.databases {
+		if d == database {
+			c.databases = append(c.databases[:i:i], c.databases[i+1:]...)
+			break
+		}
+	}
+	if c.current == database.Name {
+		c.current = DefaultDatabase
+	}
+	return nil
+}
+
+func (c *Catalog) dropTable(stmt *parser.DropStmt) error {
+	if stmt.IsTemporary {
+		return nil
+	}
+	op := "DROP " + stmt.DropTarget
+	table := c.lookup(stmt.Name)
+	if table == nil {
+		if stmt.IfExists {
+			return nil
+		}
+		return errorf(stmt.Pos(), "%s on an unknown table: %s", op, qualifiedName(c, stmt.Name))
+	}
+	// DROP TABLE drops views and dictionaries too
+	switch {
+	case stmt.DropTarget == parser.KeywordView && table.Kind != KindView && table.Kind != KindMaterializedView:
+		return errorf(stmt.Pos(), "%s on a %s: %s", op, table.Kind, table.qualifiedName())
+	case stmt.DropTarget == parser.KeywordDictionary && table.Kind != KindDictionary:
+		return errorf(stmt.Pos(), "%s on a %s: %s", op, table.Kind, table.qualifiedName())
+	}
+	c.remove(table)
+	return nil
+}
+
+func (c *Catalog) rename(stmt *parser.RenameStmt) error {
+	op := "RENAME " + stmt.RenameTarget
+	if stmt.RenameTarget == parser.KeywordDatabase {
+		return c.renameDatabases(op, stmt)
+	}
+	// the pairs are renamed one after the other, all or none
+	tables := append([]*Table(nil), c.tables...)
+	find := func(database, name string) int {
+		for i, table := range tables {
+			if table.Database == database && table.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, pair := range stmt.TargetPairList {
+		i := find(databaseName(c, pair.Old), pair.Old.Table.Name)
+		if i < 0 {
+			return errorf(stmt.Pos(), "%s on an unknown table: %s", op, qualifiedName(c, pair.Old))
+		}
+		if stmt.RenameTarget == parser.KeywordDictionary && tables[i].Kind != KindDictionary {
+			return errorf(stmt.Pos(), "%s on a %s: %s", op, tables[i].Kind, tables[i].qualifiedName())
+		}
+		database := c.database(pair.New)
+		if database == nil {
+			return errorf(stmt.Pos(), "%s to an unknown database: %s", op, databaseName(c, pair.New))
+		}
+		if j := find(database.Name, pair.New.Table.Name); j >= 0 {
+			return errorf(stmt.Pos(), "%s to a table that already exists: %s", op, tables[j].qualifiedName())
+		}
+		renamed := tables[i].copy()
+		renamed.Database = database.Name
+		renamed.databaseIdent = database.ident
+		renamed.Name = pair.New.Table.Name
+		renamed.ident = pair.New.Table
+		tables[i] = renamed
+	}
+	for i, table := range tables {
+		if table != c.tables[i] {
+			*c.tables[i] = *table
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) renameDatabases(op string, stmt *parser.RenameStmt) error {
+	names := map[*Database]*parser.Ident{}
+	name := func(database *Database) string {
+		if ident, ok := names[database]; ok {
+			return ident.Name
+		}
+		return database.Name
+	}
+	find := func(n string) *Database {
+		for _, database := range c.databases {
+			if name(database) == n {
+				return database
+			}
+		}
+		return nil
+	}
+	for _, pair := range stmt.TargetPairList {
+		database := find(pair.Old.Table.Name)
+		if database == nil {
+			return errorf(stmt.Pos(), "%s on an unknown database: %s", op, pair.Old.Table.Name)
+		}
+		if find(pair.New.Table.Name) != nil {
+			return errorf(stmt.Pos(), "%s to a database that already exists: %s", op, pair.New.Table.Name)
+		}
+		names[database] = pair.New.Table
+	}
+	for database, ident := range names {
+		for _, table := range database.Tables() {
+			table.Database = ident.Name
+			table.databaseIdent = ident
+		}
+		if c.current == database.Name {
+			c.current = ident.Name
+		}
+		database.Name = ident.Name
+		database.ident = ident
+		database.implicit = false
+	}
+	return nil
+}
+
+// database returns the database of a table name, or nil.
+func (c *Catalog) database(name *parser.TableIdentifier) *Database {
+	return c.Database(databaseName(c, name))
+}
+
+// lookup returns the table called name, or nil.
+func (c *Catalog) lookup(name *parser.TableIdentifier) *Table {
+	return c.Table(databaseName(c, name), name.Table.Name)
+}
+
+func databaseName(c *Catalog, name *parser.TableIdentifier) string {
+	if name.Database != nil {
+		return name.Database.Name
+	}
+	return c.current
+}
+
+func qualifiedName(c *Catalog, name *parser.TableIdentifier) string {
+	return databaseName(c, name) + "." + name.Table.Name
+}
+
+func (t *Table) qualifiedName() string {
+	return t.Database + "." + t.Name
+}
+
+// copy returns a copy of t whose slices can be changed without changing t.
+// The AST nodes are shared, as they are replaced rather than changed.
+func (t *Table) copy() *Table {
+	copied := *t
+	copied.Columns = append([]*Column(nil), t.Columns...)
+	copied.Indexes = append([]*Index(nil), t.Indexes...)
+	copied.Projections = append([]*Projection(nil), t.Projections...)
+	copied.Constraints = append([]*Constraint(nil), t.Constraints...)
+	if t.Engine != nil {
+		engine := *t.Engine
+		engine.OrderBy = append([]parser.Expr(nil), engine.OrderBy...)
+		engine.TTL = append([]*parser.TTLExpr(nil), engine.TTL...)
+		engine.Settings = append([]*parser.SettingExpr(nil), engine.Settings...)
+		copied.Engine = &engine
+	}
+	return &copied
+}
+
+// addElements adds the columns, indexes, projections and constraints of a
+// table schema to t.
+func (t *Table) addElements(op string, elements []parser.Expr) error {
+	for _, element := range elements {
+		var err error
+		switch element := element.(type) {
+		case *parser.ColumnDef:
+			err = t.addColumn(op, element, nil, false)
+		case *parser.TableIndex:
+			err = t.addIndex(op, element, nil)
+		case *parser.TableProjection:
+			err = t.addProjection(op, element, nil)
+		case *parser.ConstraintClause:
+			err = t.addConstraint(op, element)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newEngine(expr *parser.EngineExpr) *Engine {
+	engine := &Engine{Name: expr.Name, Params: expr.Params}
+	if expr.OrderBy != nil {
+		for _, item := range expr.OrderBy.Items {
+			if order, ok := item.(*parser.OrderExpr); ok && order.Direction == parser.OrderDirectionNone &&
+				order.Alias == nil && order.Fill == nil {
+				item = order.Expr
+			}
+			engine.OrderBy = append(engine.OrderBy, item)
+		}
+	}
+	if expr.PartitionBy != nil {
+		engine.PartitionBy = expr.PartitionBy.Expr
+	}
+	if expr.PrimaryKey != nil {
+		engine.PrimaryKey = expr.PrimaryKey.Expr
+	}
+	if expr.SampleBy != nil {
+		engine.SampleBy = expr.SampleBy.Expr
+	}
+	if expr.TTL != nil {
+		engine.TTL = expr.TTL.Items
+	}
+	if expr.Settings != nil {
+		engine.Settings = expr.Settings.Items
+	}
+	return engine
+}
+
+// newColumn returns the column def declares, normalizing its type.
+func newColumn(def *parser.ColumnDef) (*Column, error) {
+	column := &Column{Name: columnName(def.Name), Def: def}
+	if def.Type != nil {
+		t, err := types.FromColumnType(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		column.Type = t
+	}
+	return column, nil
+}
+
+func columnName(name *parser.NestedIdentifier) string {
+	if name.DotIdent != nil {
+		return name.Ident.Name + "." + name.DotIdent.Name
+	}
+	return name.Ident.Name
+}
@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AfterShip/clickhouse-sql-parser/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func export(c *Catalog) string {
+	var stmts []string
+	for _, stmt := range c.Statements() {
+		stmts = append(stmts, parser.Format(stmt))
+	}
+	return strings.Join(stmts, ";\n")
+}
+
+func TestReplay(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL(`
+CREATE DATABASE IF NOT EXISTS analytics ON CLUSTER main;
+USE analytics;
+CREATE TABLE IF NOT EXISTS events ON CLUSTER main
+(
+    id UInt64,
+    ts DateTime,
+    name TEXT DEFAULT '',
+    url String,
+    INDEX name_idx name TYPE bloom_filter GRANULARITY 4,
+    CONSTRAINT id_positive CHECK id > 0
+)
+ENGINE = MergeTree
+PARTITION BY toYYYYMM(ts)
+ORDER BY (id, ts)
+TTL ts + INTERVAL 1 YEAR
+SETTINGS index_granularity = 8192;
+CREATE TABLE events_by_name (name String, total UInt64) ENGINE = SummingMergeTree ORDER BY name;
+CREATE MATERIALIZED VIEW events_mv TO events_by_name AS SELECT name, count() AS total FROM events GROUP BY name;
+INSERT INTO events (id) VALUES (1);
+ALTER TABLE events
+    ADD COLUMN user_id UInt64 AFTER id,
+    ADD COLUMN IF NOT EXISTS name String,
+    MODIFY COLUMN name LowCardinality(String),
+    RENAME COLUMN url TO link,
+    COMMENT COLUMN user_id 'the user',
+    DROP INDEX name_idx,
+    ADD INDEX user_idx user_id TYPE minmax GRANULARITY 1,
+    ADD PROJECTION by_user (SELECT * ORDER BY user_id),
+    DELETE WHERE id = 0,
+    MODIFY TTL ts + INTERVAL 2 YEAR;
+ALTER TABLE events MODIFY SETTING merge_with_ttl_timeout = 3600;
+ALTER TABLE events RESET SETTING index_granularity;
+RENAME TABLE events TO events_v2;
+CREATE VIEW default.recent AS SELECT * FROM analytics.events_v2 WHERE ts > now() - INTERVAL 1 DAY;
+DROP VIEW IF EXISTS default.missing;
+`))
+
+	events := c.Table("analytics", "events_v2")
+	require.NotNil(t, events)
+	require.Nil(t, c.Table("analytics", "events"))
+	require.Equal(t, KindTable, events.Kind)
+
+	names := make([]string, 0, len(events.Columns))
+	for _, column := range events.Columns {
+		names = append(names, column.Name)
+	}
+	require.Equal(t, []string{"id", "user_id", "ts", "name", "link"}, names)
+	name := events.Column("name")
+	require.Equal(t, "LowCardinality(String)", name.Type.String())
+	require.Equal(t, "''", parser.Format(name.Def.DefaultExpr))
+	require.Equal(t, "3600", parser.Format(events.Engine.Setting("merge_with_ttl_timeout")))
+	require.Nil(t, events.Engine.Setting("index_granularity"))
+	require.Len(t, events.Engine.OrderBy, 1)
+	require.Equal(t, "(id, ts)", parser.Format(events.Engine.OrderBy[0]))
+
+	require.Equal(t, `CREATE DATABASE analytics;
+CREATE TABLE analytics.events_v2 (id UInt64, user_id UInt64 COMMENT 'the user', ts DateTime, name LowCardinality(String) DEFAULT '', link String, INDEX user_idx user_id TYPE minmax GRANULARITY 1, PROJECTION by_user (SELECT * ORDER BY user_id), CONSTRAINT id_positive CHECK id > 0) ENGINE = MergeTree ORDER BY (id, ts) PARTITION BY toYYYYMM(ts) TTL ts + INTERVAL 2 YEAR SETTINGS merge_with_ttl_timeout=3600;
+CREATE TABLE analytics.events_by_name (name String, total UInt64) ENGINE = SummingMergeTree ORDER BY name;
+CREATE MATERIALIZED VIEW analytics.events_mv TO analytics.events_by_name AS SELECT name, count() AS total FROM events GROUP BY name;
+CREATE VIEW default.recent AS SELECT * FROM analytics.events_v2 WHERE ts > now() - INTERVAL 1 DAY`, export(c))
+
+	// the exported statements replay to the same catalog
+	replayed := New()
+	require.NoError(t, replayed.ApplySQL(export(c)))
+	require.Equal(t, export(c), export(replayed))
+}
+
+func TestReplayErrors(t *testing.T) {
+	for _, test := range []struct {
+		sql      string
+		expected string
+	}{
+		{"ALTER TABLE x ADD COLUMN a UInt8", "ALTER on an unknown table: default.x"},
+		{"CREATE TABLE t (a UInt8) ENGINE = Memory", "CREATE TABLE on a table that already exists: default.t"},
+		{"CREATE TABLE db.x (a UInt8) ENGINE = Memory", "CREATE TABLE in an unknown database: db"},
+		{"ALTER TABLE t ADD COLUMN a String", "ADD COLUMN on a column that already exists: a"},
+		{"ALTER TABLE t ADD COLUMN c String AFTER x", "ADD COLUMN after an unknown column: x"},
+		{"ALTER TABLE t ADD COLUMN c Decimal(99, 2)", "ADD COLUMN with an invalid type Decimal(99, 2): Decimal precision 99 out of range [1, 76]"},
+		{"ALTER TABLE t DROP COLUMN x", "DROP COLUMN on an unknown column: x"},
+		{"ALTER TABLE t MODIFY COLUMN x String", "MODIFY COLUMN on an unknown column: x"},
+		{"ALTER TABLE t RENAME COLUMN a TO b", "RENAME COLUMN to a column that already exists: b"},
+		{"ALTER TABLE m DROP COLUMN a", "DROP COLUMN on a key column: a"},
+		{"ALTER TABLE m RENAME COLUMN p TO q", "RENAME COLUMN on a key column: p"},
+		{"ALTER TABLE t DROP INDEX i", "DROP INDEX on an unknown index: i"},
+		{"ALTER TABLE t MATERIALIZE PROJECTION p", "MATERIALIZE PROJECTION on an unknown projection: p"},
+		{"ALTER TABLE t REMOVE TTL", "REMOVE TTL on a table without a TTL: default.t"},
+		{"ALTER TABLE t MODIFY QUERY SELECT 1", "MODIFY QUERY on a TABLE: default.t"},
+		{"ALTER TABLE v MODIFY ORDER BY a", "MODIFY ORDER BY on a VIEW without an engine: default.v"},
+		{"DROP TABLE x", "DROP TABLE on an unknown table: default.x"},
+		{"DROP VIEW t", "DROP VIEW on a TABLE: default.t"},
+		{"DROP DATABASE db", "DROP DATABASE on an unknown database: db"},
+		{"RENAME TABLE t TO v", "RENAME TABLE to a table that already exists: default.v"},
+		{"RENAME TABLE t TO db.t", "RENAME TABLE to an unknown database: db"},
+		{"USE db", "USE of an unknown database: db"},
+	} {
+		c := New()
+		require.NoError(t, c.ApplySQL("CREATE TABLE t (a UInt8, b String) ENGINE = Memory; CREATE VIEW v AS SELECT a FROM t; "+
+			"CREATE TABLE m (a UInt8, p Date) ENGINE = MergeTree PARTITION BY toYYYYMM(p) ORDER BY (a)"))
+		before := export(c)
+
+		err := c.ApplySQL(test.sql)
+		var catalogErr *Error
+		require.True(t, errors.As(err, &catalogErr), test.sql)
+		require.Equal(t, test.expected, err.Error())
+		require.Equal(t, before, export(c), test.sql)
+	}
+}
+
+func TestCurrentDatabaseDropped(t *testing.T) {
+	c := New()
+	err := c.ApplySQL("DROP DATABASE default; CREATE DATABASE db2; CREATE MATERIALIZED VIEW db2.mv TO t AS SELECT 1")
+	var catalogErr *Error
+	require.True(t, errors.As(err, &catalogErr))
+	require.Equal(t, "CREATE MATERIALIZED VIEW TO a table in an unknown database: default", err.Error())
+	require.Nil(t, c.Table("db2", "mv"))
+}
+
+func TestAlterIsAtomic(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL("CREATE TABLE t (a UInt8) ENGINE = Memory"))
+	err := c.ApplySQL("ALTER TABLE t ADD COLUMN b UInt8, DROP COLUMN x")
+	require.EqualError(t, err, "DROP COLUMN on an unknown column: x")
+	require.Equal(t, parser.Pos(strings.Index("ALTER TABLE t ADD COLUMN b UInt8, DROP COLUMN x", "DROP")), err.(*Error).Pos)
+	require.Len(t, c.Table("", "t").Columns, 1)
+}
+
+func TestAddColumnFirst(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL(`
+CREATE TABLE t (a UInt8) ENGINE = Memory;
+ALTER TABLE t ADD COLUMN id UInt64 FIRST;
+`))
+	require.Equal(t, "CREATE TABLE default.t (id UInt64, a UInt8) ENGINE = Memory", parser.Format(c.Table("", "t").Statement()))
+}
+
+func TestModifyColumn(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL(`
+CREATE TABLE t (a UInt8 DEFAULT 1 COMMENT 'a' CODEC(ZSTD)) ENGINE = Memory;
+ALTER TABLE t MODIFY COLUMN a UInt16;
+`))
+	require.Equal(t, "a UInt16 DEFAULT 1 COMMENT 'a' CODEC(ZSTD)", parser.Format(c.Table("", "t").Column("a").Def))
+
+	require.NoError(t, c.ApplySQL("ALTER TABLE t MODIFY COLUMN a REMOVE COMMENT, MODIFY COLUMN a UInt32 MATERIALIZED 2"))
+	require.Equal(t, "a UInt32 MATERIALIZED 2 CODEC(ZSTD)", parser.Format(c.Table("", "t").Column("a").Def))
+}
+
+func TestRenameAndDrop(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL(`
+CREATE DATABASE db;
+CREATE TABLE db.a (x UInt8) ENGINE = Memory;
+CREATE TABLE db.b (y UInt8) ENGINE = Memory;
+RENAME TABLE db.a TO db.tmp, db.b TO db.a, db.tmp TO db.b;
+`))
+	require.Equal(t, "y", c.Table("db", "a").Columns[0].Name)
+	require.Equal(t, "x", c.Table("db", "b").Columns[0].Name)
+
+	require.NoError(t, c.ApplySQL("RENAME DATABASE db TO db2"))
+	require.Nil(t, c.Database("db"))
+	require.Len(t, c.Database("db2").Tables(), 2)
+	require.Equal(t, "CREATE TABLE db2.a (y UInt8) ENGINE = Memory", parser.Format(c.Table("db2", "a").Statement()))
+
+	require.NoError(t, c.ApplySQL("DROP TABLE db2.a; DROP DATABASE db2"))
+	require.Len(t, c.Databases(), 1)
+	require.Empty(t, c.Statements())
+}
+
+func TestDictionary(t *testing.T) {
+	c := New()
+	require.NoError(t, c.ApplySQL(`
+CREATE DICTIONARY IF NOT EXISTS default.users (id UInt64, name String DEFAULT '')
+PRIMARY KEY id
+SOURCE(CLICKHOUSE(TABLE 'users'))
+LIFETIME(MIN 0 MAX 300)
+LAYOUT(HASHED())`))
+	users := c.Table("default", "users")
+	require.Equal(t, KindDictionary, users.Kind)
+	require.Equal(t, "String", users.Column("name").Type.String())
+	require.NotContains(t, parser.Format(users.Statement()), "IF NOT EXISTS")
+
+	err := c.ApplySQL("ALTER TABLE users ADD COLUMN x UInt8")
+	require.EqualError(t, err, "ALTER on a DICTIONARY: default.users")
+	err = c.ApplySQL("DROP DICTIONARY IF EXISTS users")
+	require.NoError(t, err)
+	require.Nil(t, c.Table("", "users"))
+}
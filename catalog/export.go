@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"github.com/AfterShip/clickhouse-sql-parser/parser"
+)
+
+// Statements returns CREATE statements that recreate c: a CREATE DATABASE
+// for each database but the default one, then a CREATE statement for each
+// table, view and dictionary in the order they were created. The statements
+// are canonical: the names are qualified with the database, and IF NOT
+// EXISTS, OR REPLACE, ON CLUSTER, UUID and POPULATE are left out.
+func (c *Catalog) Statements() []parser.Expr {
+	var stmts []parser.Expr
+	for _, database := range c.databases {
+		if !database.implicit {
+			stmts = append(stmts, database.Statement())
+		}
+	}
+	for _, table := range c.tables {
+		stmts = append(stmts, table.Statement())
+	}
+	return stmts
+}
+
+// Statement returns the CREATE DATABASE statement of d.
+func (d *Database) Statement() *parser.CreateDatabase {
+	return parser.Clone(&parser.CreateDatabase{
+		Name:    d.ident,
+		Engine:  d.Engine,
+		Comment: d.Comment,
+	}).(*parser.CreateDatabase)
+}
+
+// Statement returns the CREATE statement of t: a *parser.CreateTable,
+// *parser.CreateView, *parser.CreateMaterializedView or
+// *parser.CreateDictionary. The positions of its nodes are meaningless, as
+// they come from the different statements that shaped t.
+func (t *Table) Statement() parser.Expr {
+	name := &parser.TableIdentifier{Database: t.databaseIdent, Table: t.ident}
+	var stmt parser.Expr
+	switch t.Kind {
+	case KindTable:
+		create := t.create.(*parser.CreateTable)
+		table := &parser.CreateTable{
+			Name:          name,
+			TableSchema:   t.schema(),
+			Engine:        t.Engine.expr(),
+			TableFunction: create.TableFunction,
+			Comment:       t.Comment,
+		}
+		if create.TableSchema != nil && create.TableSchema.TableFunction != nil {
+			if table.TableSchema == nil {
+				table.TableSchema = &parser.TableSchemaClause{}
+			}
+			table.TableSchema.TableFunction = create.TableSchema.TableFunction
+		}
+		if t.Query != nil {
+			table.SubQuery = &parser.SubQuery{Select: t.Query}
+		}
+		stmt = table
+	case KindView:
+		view := &parser.CreateView{
+			Name:        name,
+			TableSchema: t.schema(),
+			Comment:     t.Comment,
+		}
+		if t.Query != nil {
+			view.SubQuery = &parser.SubQuery{Select: t.Query}
+		}
+		stmt = view
+	case KindMaterializedView:
+		view := *t.create.(*parser.CreateMaterializedView)
+		view.Name = name
+		view.IfNotExists = false
+		view.OnCluster = nil
+		view.Populate = false
+		view.Comment = t.Comment
+		view.Engine = t.Engine.expr()
+		view.TableSchema = nil
+		if view.Destination != nil {
+			destination := *view.Destination
+			destination.TableSchema = t.schema()
+			view.Destination = &destination
+		} else if view.Engine != nil {
+			view.TableSchema = t.schema()
+		}
+		if t.Query != nil {
+			view.SubQuery = &parser.SubQuery{Select: t.Query}
+		}
+		stmt = &view
+	case KindDictionary:
+		dictionary := *t.create.(*parser.CreateDictionary)
+		dictionary.Name = name
+		dictionary.OrReplace = false
+		dictionary.IfNotExists = false
+		dictionary.UUID = nil
+		dictionary.OnCluster = nil
+		dictionary.Comment = t.Comment
+		stmt = &dictionary
+	}
+	return parser.Clone(stmt)
+}
+
+// schema returns the table schema clause of t, or nil if t has no columns.
+func (t *Table) schema() *parser.TableSchemaClause {
+	var elements []parser.Expr
+	for _, column := range t.Columns {
+		elements = append(elements, column.Def)
+	}
+	for _, index := range t.Indexes {
+		elements = append(elements, index.Def)
+	}
+	for _, projection := range t.Projections {
+		elements = append(elements, projection.Def)
+	}
+	for _, constraint := range t.Constraints {
+		elements = append(elements, constraint.Def)
+	}
+	if len(elements) == 0 {
+		return nil
+	}
+	return &parser.TableSchemaClause{Columns: elements}
+}
+
+// expr returns the ENGINE clause of e, or nil if e is nil.
+func (e *Engine) expr() *parser.EngineExpr {
+	if e == nil {
+		return nil
+	}
+	expr := &parser.EngineExpr{Name: e.Name, Params: e.Params}
+	if len(e.OrderBy) > 0 {
+		expr.OrderBy = &parser.OrderByClause{Items: e.OrderBy}
+	}
+	if e.PartitionBy != nil {
+		expr.PartitionBy = &parser.PartitionByClause{Expr: e.PartitionBy}
+	}
+	if e.PrimaryKey != nil {
+		expr.PrimaryKey = &parser.PrimaryKeyClause{Expr: e.PrimaryKey}
+	}
+	if e.SampleBy != nil {
+		expr.SampleBy = &parser.SampleByClause{Expr: e.SampleBy}
+	}
+	if len(e.TTL) > 0 {
+		expr.TTL = &parser.TTLClause{Items: e.TTL}
+	}
+	if len(e.Settings) > 0 {
+		expr.Settings = &parser.SettingsClause{Items: e.Settings}
+	}
+	return expr
+}
@@ -97,24 +97,37 @@ func main() {
 		inputBytes = []byte(os.Args[len(os.Args)-1])
 	}
 	parser := clickhouse.NewParser(string(inputBytes))
+	if options.format || options.beautify {
+		// keep the comments so that formatting does not drop them
+		parser.WithComments()
+	}
 	stmts, err := parser.ParseStmts()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "parse statements error: %s\n", err.Error())
 		os.Exit(1)
 	}
 	if !options.format && !options.beautify { // print AST
-		bytes, _ := json.MarshalIndent(stmts, "", "  ") // nolint
+		// Each statement is encoded with node type tags so the output can
+		// be decoded back with clickhouse.UnmarshalJSON.
+		docs := make([]json.RawMessage, 0, len(stmts))
+		for _, stmt := range stmts {
+			doc, err := clickhouse.MarshalJSON(stmt)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "encode AST error: %s\n", err.Error())
+				os.Exit(1)
+			}
+			docs = append(docs, doc)
+		}
+		bytes, _ := json.MarshalIndent(docs, "", "  ") // nolint
 		fmt.Println(string(bytes))
 	} else { // format SQL
 		for _, stmt := range stmts {
+			formatter := clickhouse.NewFormatter().WithComments(parser.CommentMap())
 			if options.beautify {
-				formatter := clickhouse.NewFormatter()
 				formatter.WithBeautify()
-				formatter.WriteExpr(stmt)
-				fmt.Println(formatter.String())
-			} else {
-				fmt.Println(clickhouse.Format(stmt))
 			}
+			formatter.WriteExpr(stmt)
+			fmt.Println(formatter.String())
 		}
 	}
 }
@@ -0,0 +1,1065 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ApplyFunc is called by Apply for every node. The Cursor describes the node
+// and where it is held, and can be used to modify the tree. The return value
+// controls the traversal, see Apply.
+type ApplyFunc func(c *Cursor) bool
+
+// Apply traverses the tree rooted at root in the same depth-first order as
+// Walk, calling pre before a node's children are visited and post after.
+// Either may be nil. Nil fields and slice elements are skipped.
+//
+// If pre returns false, the node's children are skipped and post is not
+// called for it. If post returns false, the traversal stops and Apply
+// returns immediately.
+//
+// pre and post may modify the tree through the Cursor. If pre replaces or
+// deletes the current node, the node's children (old or new) are not
+// traversed; post is still called for a replacement. Nodes inserted into a
+// slice are not traversed either.
+//
+// Apply returns the possibly replaced root.
+func Apply(root Expr, pre, post ApplyFunc) (result Expr) {
+	holder := &struct{ Node Expr }{root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+		result = holder.Node
+	}()
+	a := &applier{pre: pre, post: post}
+	a.applyValue(nil, "", fieldOf(holder, "Node"), nil)
+	return holder.Node
+}
+
+// abort is the sentinel panic value Apply uses to unwind when post stops the
+// traversal.
+var abort = new(int)
+
+// RewriteFunc is called by Rewrite for every node. It returns the node to
+// install in place of the one it received: the node itself to keep it, a
+// different node to replace it, or nil to remove it.
+type RewriteFunc func(node Expr) Expr
+
+// Rewrite is a simplified form of Apply that lets pre and post replace the
+// nodes they visit by returning a different node. Either may be nil.
+//
+// A replacement is written back into the field or slice element of the parent
+// that held the original node, so it must be assignable to that field's type
+// (e.g. an *Ident for TableIdentifier.Table); Rewrite panics otherwise.
+// Returning nil clears the field, or drops the element when the node is held
+// in a slice. A node returned by pre in place of the one it received is
+// installed as is: its children are not rewritten, but post is still called
+// on it.
+//
+// Rewrite returns the possibly replaced root.
+func Rewrite(root Expr, pre, post RewriteFunc) Expr {
+	return Apply(root, rewriteWith(pre), rewriteWith(post))
+}
+
+func rewriteWith(fn RewriteFunc) ApplyFunc {
+	if fn == nil {
+		return nil
+	}
+	return func(c *Cursor) bool {
+		node := c.Node()
+		replacement := fn(node)
+		switch {
+		case replacement == node:
+		case isNilExpr(replacement) && c.Index() >= 0:
+			c.Delete()
+		default:
+			c.Replace(replacement)
+		}
+		return true
+	}
+}
+
+// A Cursor describes a node encountered during Apply. Information about the
+// node and its parent is available from the Node, Parent, Name and Index
+// methods.
+//
+// Most nodes are held directly in a field of their parent node. A few are
+// held in helper structs that are not nodes themselves: the key/value pairs
+// of a MapLiteral, the definitions of a WindowClause and the options of a
+// JSONType. For those, Parent is the enclosing node and Name is the field of
+// the helper struct.
+type Cursor struct {
+	parent Expr
+	name   string
+	field  reflect.Value // the field of parent (or of a helper struct inside it) holding node
+	iter   *iterator     // set when field is a slice
+	node   Expr
+}
+
+// iterator tracks the position within a slice field while its elements are
+// visited, so that edits through the Cursor do not skip or repeat elements.
+type iterator struct {
+	index, step int
+}
+
+// Node returns the current node. It returns nil once the node was deleted.
+func (c *Cursor) Node() Expr { return c.node }
+
+// Parent returns the parent of the current node, or nil for the root.
+func (c *Cursor) Parent() Expr { return c.parent }
+
+// Name returns the name of the parent field that holds the current node, e.g.
+// "SelectItems" for a SelectItem of a SelectQuery. It is empty for the root.
+func (c *Cursor) Name() string { return c.name }
+
+// Index reports the index of the current node within the slice field that
+// holds it, or a value < 0 if the field is not a slice.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+// Replace replaces the current node with n. n must be assignable to the
+// parent field that holds the current node; Replace panics otherwise. A nil n
+// clears the field. The replacement node is not traversed by Apply.
+func (c *Cursor) Replace(n Expr) {
+	target := c.field
+	if i := c.Index(); i >= 0 {
+		target = target.Index(i)
+	}
+	target.Set(c.valueFor(target.Type(), n))
+	if isNilExpr(n) {
+		n = nil
+	}
+	c.node = n
+}
+
+// Delete deletes the current node from its containing slice. It panics if
+// the current node is not part of a slice.
+func (c *Cursor) Delete() {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Sprintf("parser: cannot delete from %T.%s: not a slice element", c.parent, c.name))
+	}
+	v := c.field
+	l := v.Len()
+	reflect.Copy(v.Slice(i, l), v.Slice(i+1, l))
+	v.Index(l - 1).Set(reflect.Zero(v.Type().Elem()))
+	v.SetLen(l - 1)
+	c.iter.step--
+	c.node = nil
+}
+
+// InsertAfter inserts n after the current node in its containing slice. It
+// panics if the current node is not part of a slice or n does not fit the
+// slice's element type. Apply does not traverse n.
+func (c *Cursor) InsertAfter(n Expr) {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Sprintf("parser: cannot insert into %T.%s: not a slice element", c.parent, c.name))
+	}
+	v := c.field
+	value := c.valueFor(v.Type().Elem(), n)
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+2, l), v.Slice(i+1, l))
+	v.Index(i + 1).Set(value)
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current node in its containing slice. It
+// panics if the current node is not part of a slice or n does not fit the
+// slice's element type. Apply does not traverse n.
+func (c *Cursor) InsertBefore(n Expr) {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Sprintf("parser: cannot insert into %T.%s: not a slice element", c.parent, c.name))
+	}
+	v := c.field
+	value := c.valueFor(v.Type().Elem(), n)
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+1, l), v.Slice(i, l))
+	v.Index(i).Set(value)
+	c.iter.index++
+}
+
+// valueFor converts n into a value that can be stored in a field of type typ.
+func (c *Cursor) valueFor(typ reflect.Type, n Expr) reflect.Value {
+	if isNilExpr(n) {
+		return reflect.Zero(typ)
+	}
+	v := reflect.ValueOf(n)
+	switch {
+	case v.Type().AssignableTo(typ):
+		return v
+	case typ.Kind() == reflect.Struct && v.Type() == reflect.PointerTo(typ):
+		// Some nodes are held by value, e.g. EnumType.Values.
+		return v.Elem()
+	}
+	if c.parent == nil {
+		panic(fmt.Sprintf("parser: cannot replace root %T with %T", c.node, n))
+	}
+	panic(fmt.Sprintf("parser: cannot use %T as %s in %T.%s", n, typ, c.parent, c.name))
+}
+
+func isNilExpr(n Expr) bool {
+	return n == nil || reflect.ValueOf(n).IsNil()
+}
+
+// nodeOf returns the node held in v, or nil if there is none.
+func nodeOf(v reflect.Value) Expr {
+	switch v.Kind() {
+	case reflect.Struct:
+		node, _ := v.Addr().Interface().(Expr)
+		return node
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		node, _ := v.Interface().(Expr)
+		if isNilExpr(node) {
+			return nil
+		}
+		return node
+	}
+	return nil
+}
+
+// fieldOf returns the named field of the struct holder points to.
+func fieldOf(holder any, name string) reflect.Value {
+	v := reflect.ValueOf(holder).Elem().FieldByName(name)
+	if !v.IsValid() {
+		panic(fmt.Sprintf("parser: %T has no field %s", holder, name))
+	}
+	return v
+}
+
+type applier struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+// apply visits the node held in the named field of parent.
+func (a *applier) apply(parent Expr, name string) {
+	a.applyHeld(parent, parent, name)
+}
+
+// applyList visits the nodes held in the named slice field of parent.
+func (a *applier) applyList(parent Expr, name string) {
+	a.applyHeldList(parent, parent, name)
+}
+
+// applyHeld visits the node held in the named field of holder, a helper
+// struct inside parent that is not a node itself, such as a MapLiteral
+// key/value pair.
+func (a *applier) applyHeld(parent Expr, holder any, name string) {
+	a.applyValue(parent, name, fieldOf(holder, name), nil)
+}
+
+// applyHeldList visits the nodes held in the named slice field of holder.
+func (a *applier) applyHeldList(parent Expr, holder any, name string) {
+	field := fieldOf(holder, name)
+	saved := a.iter
+	a.iter.index = 0
+	for a.iter.index < field.Len() {
+		a.iter.step = 1
+		a.applyValue(parent, name, field, &a.iter)
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
+
+func (a *applier) applyValue(parent Expr, name string, field reflect.Value, iter *iterator) {
+	elem := field
+	if iter != nil {
+		elem = field.Index(iter.index)
+	}
+	node := nodeOf(elem)
+	if node == nil {
+		return
+	}
+
+	saved := a.cursor
+	a.cursor = Cursor{parent: parent, name: name, field: field, iter: iter, node: node}
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+	// Only descend when pre kept the node: a replacement is installed as is.
+	if a.cursor.node == node {
+		a.applyChildren(node)
+	}
+	if a.post != nil && a.cursor.node != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+	a.cursor = saved
+}
+
+// applyChildren visits the children of node. It must list the same child
+// fields as Walk; TestTraversalEnginesVisitSameFields enforces this.
+func (a *applier) applyChildren(node Expr) {
+	switch n := node.(type) {
+	case *SelectQuery:
+		a.apply(n, "With")
+		a.apply(n, "DistinctOn")
+		a.apply(n, "Top")
+		a.applyList(n, "SelectItems")
+		a.apply(n, "From")
+		a.apply(n, "Window")
+		a.apply(n, "Prewhere")
+		a.apply(n, "Where")
+		a.apply(n, "GroupBy")
+		a.apply(n, "Having")
+		a.apply(n, "OrderBy")
+		a.apply(n, "LimitBy")
+		a.apply(n, "Limit")
+		a.apply(n, "Settings")
+		a.apply(n, "Format")
+	case *SetOperationExpr:
+		a.apply(n, "Left")
+		a.apply(n, "Right")
+	case *SubQuery:
+		a.apply(n, "Select")
+	case *SelectItem:
+		a.apply(n, "Expr")
+		a.applyList(n, "Modifiers")
+		a.apply(n, "Alias")
+	case *TableExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Alias")
+	case *AliasExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Alias")
+	case *FunctionExpr:
+		a.apply(n, "Name")
+		a.apply(n, "Params")
+	case *TableIdentifier:
+		a.apply(n, "Database")
+		a.apply(n, "Table")
+	case *Ident:
+		// Leaf node
+	case *NumberLiteral:
+		// Leaf node
+	case *StringLiteral:
+		// Leaf node
+	case *BoolLiteral:
+		// Leaf node
+	case *NullLiteral:
+		// Leaf node
+	case *NotNullLiteral:
+		a.apply(n, "NullLiteral")
+	case *ColumnExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Alias")
+	case *BinaryOperation:
+		a.apply(n, "LeftExpr")
+		a.apply(n, "RightExpr")
+	case *WhenClause:
+		a.apply(n, "When")
+		a.apply(n, "Then")
+		a.apply(n, "Else")
+	case *CaseExpr:
+		a.apply(n, "Expr")
+		a.applyList(n, "Whens")
+		a.apply(n, "Else")
+	case *CastExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "AsType")
+	case *Path:
+		a.applyList(n, "Fields")
+	case *WithClause:
+		a.applyList(n, "CTEs")
+	case *CTEStmt:
+		a.apply(n, "Expr")
+		a.apply(n, "Alias")
+	case *FromClause:
+		a.apply(n, "Expr")
+	case *JoinExpr:
+		a.apply(n, "Left")
+		a.apply(n, "Right")
+		a.apply(n, "Constraints")
+	case *JoinTableExpr:
+		a.apply(n, "Table")
+		a.apply(n, "SampleRatio")
+	case *OnClause:
+		a.apply(n, "On")
+	case *UsingClause:
+		a.apply(n, "Using")
+	case *WhereClause:
+		a.apply(n, "Expr")
+	case *PrewhereClause:
+		a.apply(n, "Expr")
+	case *GroupByClause:
+		a.apply(n, "Expr")
+	case *HavingClause:
+		a.apply(n, "Expr")
+	case *OrderByClause:
+		a.applyList(n, "Items")
+		a.apply(n, "Interpolate")
+	case *OrderExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Alias")
+		a.apply(n, "Fill")
+	case *Fill:
+		a.apply(n, "From")
+		a.apply(n, "To")
+		a.apply(n, "Step")
+		a.apply(n, "Staleness")
+	case *InterpolateClause:
+		a.applyList(n, "Items")
+	case *InterpolateItem:
+		a.apply(n, "Column")
+		a.apply(n, "Expr")
+	case *LimitClause:
+		a.apply(n, "Limit")
+		a.apply(n, "Offset")
+	case *LimitByClause:
+		a.apply(n, "Limit")
+		a.apply(n, "ByExpr")
+	case *SettingsClause:
+		a.applyList(n, "Items")
+	case *SettingExpr:
+		a.apply(n, "Name")
+		a.apply(n, "Expr")
+	case *FormatClause:
+		a.apply(n, "Format")
+	case *InsertStmt:
+		a.apply(n, "Table")
+		a.apply(n, "ColumnNames")
+		a.apply(n, "AllColumns")
+		a.apply(n, "InFile")
+		a.apply(n, "Compression")
+		a.apply(n, "Settings")
+		a.apply(n, "Format")
+		a.applyList(n, "Values")
+		a.apply(n, "SelectExpr")
+		a.apply(n, "Data")
+	case *ColumnNamesExpr:
+		a.applyList(n, "ColumnNames")
+	case *InsertAllColumnsExpr:
+		a.apply(n, "Except")
+	case *AssignmentValues:
+		a.applyList(n, "Values")
+	case *TableFunctionExpr:
+		a.apply(n, "Name")
+		a.apply(n, "Args")
+	case *TableArgListExpr:
+		a.applyList(n, "Args")
+	case *NestedIdentifier:
+		a.apply(n, "Ident")
+		a.apply(n, "DotIdent")
+	case *ArrayParamList:
+		a.apply(n, "Items")
+	case *ColumnExprList:
+		a.applyList(n, "Items")
+	case *ParamExprList:
+		a.apply(n, "Items")
+		a.apply(n, "ColumnArgList")
+	case *ColumnArgList:
+		a.applyList(n, "Items")
+	case *WindowClause:
+		for _, window := range n.Windows {
+			if window == nil {
+				continue
+			}
+			a.applyHeld(n, window, "Name")
+			a.applyHeld(n, window, "Expr")
+		}
+	case *WindowExpr:
+		a.apply(n, "WindowName")
+		a.apply(n, "PartitionBy")
+		a.apply(n, "OrderBy")
+		a.apply(n, "Frame")
+	case *PartitionByClause:
+		a.apply(n, "Expr")
+	case *WindowFrameClause:
+		a.apply(n, "Extend")
+	case *WindowFrameExtendExpr:
+		a.apply(n, "Expr")
+	case *BetweenClause:
+		a.apply(n, "Expr")
+		a.apply(n, "Between")
+		a.apply(n, "And")
+	case *WindowFrameCurrentRow:
+		// Leaf node
+	case *WindowFrameUnbounded:
+		// Leaf node
+	case *WindowFrameNumber:
+		a.apply(n, "Number")
+	case *WindowFrameParam:
+		a.apply(n, "Param")
+	case *TopClause:
+		a.apply(n, "Number")
+	case *SampleClause:
+		a.apply(n, "Ratio")
+		a.apply(n, "Offset")
+	case *RatioExpr:
+		a.apply(n, "Numerator")
+		a.apply(n, "Denominator")
+	case *IntervalExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Unit")
+	case *DropStmt:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+	case *DropDatabase:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+	case *DropUserOrRole:
+		a.applyList(n, "Names")
+		a.apply(n, "From")
+	case *TruncateTable:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+	case *DataPayload:
+		// Leaf node
+	case *BadStmt:
+		// Leaf node
+	case *CheckStmt:
+		a.apply(n, "Table")
+		a.apply(n, "Partition")
+	case *OptimizeStmt:
+		a.apply(n, "Table")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Partition")
+		a.apply(n, "Deduplicate")
+	case *DeduplicateClause:
+		a.apply(n, "By")
+		a.apply(n, "Except")
+	case *SystemStmt:
+		a.apply(n, "Expr")
+	case *SystemFlushExpr:
+		a.apply(n, "Distributed")
+	case *SystemReloadExpr:
+		a.apply(n, "OnCluster")
+		a.apply(n, "Dictionary")
+	case *SystemSyncExpr:
+		a.apply(n, "Cluster")
+	case *SystemCtrlExpr:
+		a.apply(n, "Cluster")
+	case *SystemDropExpr:
+		// Leaf node
+	case *UseStmt:
+		a.apply(n, "Database")
+	case *SetStmt:
+		a.apply(n, "Settings")
+	case *ExplainStmt:
+		a.apply(n, "Statement")
+	case *GrantPrivilegeStmt:
+		a.applyList(n, "Privileges")
+		a.apply(n, "On")
+		a.applyList(n, "To")
+		a.apply(n, "OnCluster")
+	case *RevokeStmt:
+		a.apply(n, "OnCluster")
+		a.applyList(n, "Privileges")
+		a.apply(n, "On")
+		a.applyList(n, "Roles")
+		a.applyList(n, "From")
+		a.applyList(n, "Except")
+	case *PrivilegeClause:
+		a.apply(n, "Params")
+	case *RenameStmt:
+		a.applyList(n, "TargetPairList")
+		a.apply(n, "OnCluster")
+	case *DeleteClause:
+		a.apply(n, "Table")
+		a.apply(n, "OnCluster")
+		a.apply(n, "WhereExpr")
+	case *CreateDatabase:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Engine")
+		a.apply(n, "Comment")
+	case *CreateTable:
+		a.apply(n, "Name")
+		a.apply(n, "UUID")
+		a.apply(n, "OnCluster")
+		a.apply(n, "TableSchema")
+		a.apply(n, "Engine")
+		a.apply(n, "SubQuery")
+		a.apply(n, "TableFunction")
+		a.apply(n, "Comment")
+	case *CreateView:
+		a.apply(n, "Name")
+		a.apply(n, "UUID")
+		a.apply(n, "OnCluster")
+		a.apply(n, "TableSchema")
+		a.apply(n, "Comment")
+		a.apply(n, "SubQuery")
+	case *CreateMaterializedView:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Refresh")
+		a.apply(n, "RandomizeFor")
+		a.applyList(n, "DependsOn")
+		a.apply(n, "Settings")
+		a.apply(n, "TableSchema")
+		a.apply(n, "Engine")
+		a.apply(n, "Destination")
+		a.apply(n, "SubQuery")
+		a.apply(n, "Comment")
+		a.apply(n, "Definer")
+	case *CreateLiveView:
+		a.apply(n, "Name")
+		a.apply(n, "UUID")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Destination")
+		a.apply(n, "TableSchema")
+		a.apply(n, "WithTimeout")
+		a.apply(n, "SubQuery")
+	case *CreateDictionary:
+		a.apply(n, "Name")
+		a.apply(n, "UUID")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Schema")
+		a.apply(n, "Engine")
+		a.apply(n, "Comment")
+	case *CreateFunction:
+		a.apply(n, "FunctionName")
+		a.apply(n, "OnCluster")
+		a.apply(n, "Params")
+		a.apply(n, "Expr")
+	case *CreateNamedCollection:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+		a.applyList(n, "Params")
+	case *NamedCollectionParam:
+		a.apply(n, "Name")
+		a.apply(n, "Value")
+	case *CreateRole:
+		a.applyList(n, "RoleNames")
+		a.apply(n, "AccessStorageType")
+		a.applyList(n, "Settings")
+	case *CreateUser:
+		a.applyList(n, "UserNames")
+		a.apply(n, "Authentication")
+		a.apply(n, "ValidUntil")
+		a.applyList(n, "Hosts")
+		a.apply(n, "DefaultRole")
+		a.apply(n, "DefaultDatabase")
+		a.apply(n, "Grantees")
+		a.applyList(n, "Settings")
+	case *AlterTable:
+		a.apply(n, "TableIdentifier")
+		a.apply(n, "OnCluster")
+		a.applyList(n, "AlterExprs")
+	case *AlterTableAttachPartition:
+		a.apply(n, "Partition")
+		a.apply(n, "From")
+	case *AlterTableDetachPartition:
+		a.apply(n, "Partition")
+		a.apply(n, "Settings")
+	case *AlterTableDropPartition:
+		a.apply(n, "Partition")
+		a.apply(n, "Settings")
+	case *AlterTableMaterializeProjection:
+		a.apply(n, "ProjectionName")
+		a.apply(n, "Partition")
+	case *AlterTableMaterializeIndex:
+		a.apply(n, "IndexName")
+		a.apply(n, "Partition")
+	case *AlterTableFreezePartition:
+		a.apply(n, "Partition")
+	case *AlterTableAddColumn:
+		a.apply(n, "Column")
+		a.apply(n, "After")
+		a.apply(n, "Settings")
+	case *AlterTableAddIndex:
+		a.apply(n, "Index")
+		a.apply(n, "After")
+	case *AlterTableAddProjection:
+		a.apply(n, "TableProjection")
+		a.apply(n, "After")
+	case *AlterTableDropColumn:
+		a.apply(n, "ColumnName")
+	case *AlterTableDropIndex:
+		a.apply(n, "IndexName")
+	case *AlterTableDropProjection:
+		a.apply(n, "ProjectionName")
+	case *AlterTableRemoveTTL:
+		// Leaf node
+	case *AlterTableClearColumn:
+		a.apply(n, "ColumnName")
+		a.apply(n, "PartitionExpr")
+	case *AlterTableClearIndex:
+		a.apply(n, "IndexName")
+		a.apply(n, "PartitionExpr")
+	case *AlterTableClearProjection:
+		a.apply(n, "ProjectionName")
+		a.apply(n, "PartitionExpr")
+	case *AlterTableRenameColumn:
+		a.apply(n, "OldColumnName")
+		a.apply(n, "NewColumnName")
+	case *AlterTableModifyQuery:
+		a.apply(n, "SelectExpr")
+	case *AlterTableModifyOrderBy:
+		a.apply(n, "OrderBy")
+	case *AlterTableModifyTTL:
+		a.apply(n, "TTL")
+	case *AlterTableModifyColumn:
+		a.apply(n, "Column")
+		a.apply(n, "RemovePropertyType")
+	case *AlterTableModifySetting:
+		a.applyList(n, "Settings")
+	case *AlterTableResetSetting:
+		a.applyList(n, "Settings")
+	case *AlterTableReplacePartition:
+		a.apply(n, "Partition")
+		a.apply(n, "Table")
+	case *AlterTableDelete:
+		a.apply(n, "InPartition")
+		a.apply(n, "WhereClause")
+	case *AlterTableUpdate:
+		a.applyList(n, "Assignments")
+		a.apply(n, "InPartition")
+		a.apply(n, "WhereClause")
+	case *AlterTableMovePartition:
+		a.apply(n, "Partition")
+		a.apply(n, "ToDisk")
+		a.apply(n, "ToVolume")
+		a.apply(n, "ToTable")
+	case *AlterTableFetchPartition:
+		a.apply(n, "Partition")
+		a.apply(n, "From")
+	case *AlterTableAddConstraint:
+		a.apply(n, "Name")
+		a.apply(n, "Expr")
+	case *AlterTableDropConstraint:
+		a.apply(n, "Name")
+	case *AlterTableCommentColumn:
+		a.apply(n, "ColumnName")
+		a.apply(n, "Comment")
+	case *AlterTableModifyComment:
+		a.apply(n, "Comment")
+	case *AlterTableModifySampleBy:
+		a.apply(n, "SampleBy")
+	case *AlterTableApplyDeletedMask:
+		a.apply(n, "InPartition")
+	case *UpdateAssignment:
+		a.apply(n, "Column")
+		a.apply(n, "Expr")
+	case *AlterRole:
+		a.applyList(n, "RoleRenamePairs")
+		a.applyList(n, "Settings")
+	case *RoleRenamePair:
+		a.apply(n, "RoleName")
+		a.apply(n, "NewName")
+	case *AlterUser:
+		a.applyList(n, "UserRenamePairs")
+		a.apply(n, "Authentication")
+		a.apply(n, "ValidUntil")
+		a.applyList(n, "Hosts")
+		a.applyList(n, "AddHosts")
+		a.applyList(n, "DropHosts")
+		a.apply(n, "DefaultRole")
+		a.apply(n, "DefaultDatabase")
+		a.apply(n, "Grantees")
+		a.applyList(n, "Settings")
+	case *ToRolesClause:
+		a.applyList(n, "Roles")
+		a.applyList(n, "Except")
+	case *QuotaLimit:
+		a.apply(n, "Interval")
+		a.apply(n, "Unit")
+		a.applyList(n, "Limits")
+	case *CreateQuota:
+		a.applyList(n, "Names")
+		a.apply(n, "AccessStorageType")
+		a.applyList(n, "KeyedBy")
+		a.applyList(n, "Limits")
+		a.apply(n, "To")
+	case *AlterQuota:
+		a.applyList(n, "RenamePairs")
+		a.applyList(n, "KeyedBy")
+		a.applyList(n, "Limits")
+		a.apply(n, "To")
+	case *RowPolicyName:
+		a.apply(n, "Name")
+		a.apply(n, "OnCluster")
+		a.apply(n, "On")
+		a.apply(n, "RenameTo")
+	case *CreateRowPolicy:
+		a.applyList(n, "Policies")
+		a.apply(n, "AccessStorageType")
+		a.apply(n, "Using")
+		a.apply(n, "To")
+	case *AlterRowPolicy:
+		a.applyList(n, "Policies")
+		a.apply(n, "Using")
+		a.apply(n, "To")
+	case *DropRowPolicy:
+		a.applyList(n, "Policies")
+		a.apply(n, "OnCluster")
+		a.apply(n, "From")
+	case *CreateSettingsProfile:
+		a.applyList(n, "Names")
+		a.apply(n, "AccessStorageType")
+		a.applyList(n, "Settings")
+		a.apply(n, "To")
+	case *AlterSettingsProfile:
+		a.applyList(n, "RenamePairs")
+		a.applyList(n, "Settings")
+		a.apply(n, "To")
+	case *TableSchemaClause:
+		a.applyList(n, "Columns")
+		a.apply(n, "AliasTable")
+		a.apply(n, "TableFunction")
+	case *ColumnDef:
+		a.apply(n, "Name")
+		a.apply(n, "Type")
+		a.apply(n, "NotNull")
+		a.apply(n, "Nullable")
+		a.apply(n, "DefaultExpr")
+		a.apply(n, "MaterializedExpr")
+		a.apply(n, "AliasExpr")
+		a.apply(n, "Codec")
+		a.apply(n, "TTL")
+		a.apply(n, "Comment")
+		a.apply(n, "CompressionCodec")
+	case *ScalarType:
+		a.apply(n, "Name")
+	case *JSONType:
+		a.apply(n, "Name")
+		a.applyJSONOptions(n, n.Options)
+	case *PropertyType:
+		a.apply(n, "Name")
+	case *TypeWithParams:
+		a.apply(n, "Name")
+		a.applyList(n, "Params")
+	case *ComplexType:
+		a.apply(n, "Name")
+		a.applyList(n, "Params")
+	case *NestedType:
+		a.apply(n, "Name")
+		a.applyList(n, "Columns")
+	case *CompressionCodec:
+		a.apply(n, "Type")
+		a.apply(n, "TypeLevel")
+		a.apply(n, "Name")
+		a.apply(n, "Level")
+	case *EngineExpr:
+		a.apply(n, "Params")
+		a.apply(n, "PrimaryKey")
+		a.apply(n, "PartitionBy")
+		a.apply(n, "SampleBy")
+		a.apply(n, "TTL")
+		a.apply(n, "Settings")
+		a.apply(n, "OrderBy")
+	case *PrimaryKeyClause:
+		a.apply(n, "Expr")
+	case *SampleByClause:
+		a.apply(n, "Expr")
+	case *TTLClause:
+		a.applyList(n, "Items")
+	case *TTLExpr:
+		a.apply(n, "Expr")
+		a.apply(n, "Policy")
+	case *TTLPolicy:
+		a.apply(n, "Item")
+		a.apply(n, "Where")
+		a.apply(n, "GroupBy")
+	case *TTLPolicyRule:
+		a.apply(n, "ToVolume")
+		a.apply(n, "ToDisk")
+		a.apply(n, "Action")
+	case *TTLPolicyRuleAction:
+		a.apply(n, "Codec")
+	case *RefreshExpr:
+		a.apply(n, "Interval")
+		a.apply(n, "Offset")
+	case *DestinationClause:
+		a.apply(n, "TableIdentifier")
+		a.apply(n, "TableSchema")
+	case *ConstraintClause:
+		a.apply(n, "Constraint")
+		a.apply(n, "Expr")
+	case *RoleName:
+		a.apply(n, "Name")
+		a.apply(n, "Scope")
+		a.apply(n, "OnCluster")
+	case *SettingPair:
+		a.apply(n, "Name")
+		a.apply(n, "Value")
+	case *RoleSetting:
+		a.applyList(n, "SettingPairs")
+		a.apply(n, "Modifier")
+	case *AuthenticationClause:
+		a.apply(n, "AuthValue")
+		a.apply(n, "LdapServer")
+		a.apply(n, "KerberosRealm")
+	case *HostClause:
+		a.apply(n, "HostValue")
+	case *DefaultRoleClause:
+		a.applyList(n, "Roles")
+	case *GranteesClause:
+		a.applyList(n, "Grantees")
+		a.applyList(n, "ExceptUsers")
+	case *WithTimeoutClause:
+		a.apply(n, "Expr")
+		a.apply(n, "Number")
+	case *DictionarySchemaClause:
+		a.applyList(n, "Attributes")
+	case *DictionaryAttribute:
+		a.apply(n, "Name")
+		a.apply(n, "Type")
+		a.apply(n, "Default")
+		a.apply(n, "Expression")
+	case *DictionaryEngineClause:
+		a.apply(n, "PrimaryKey")
+		a.apply(n, "Source")
+		a.apply(n, "Lifetime")
+		a.apply(n, "Layout")
+		a.apply(n, "Range")
+		a.apply(n, "Settings")
+	case *DictionaryPrimaryKeyClause:
+		a.apply(n, "Keys")
+	case *DictionarySourceClause:
+		a.apply(n, "Source")
+		a.applyList(n, "Args")
+	case *DictionaryArgExpr:
+		a.apply(n, "Name")
+		a.apply(n, "Value")
+		a.applyList(n, "Args")
+	case *DictionaryLifetimeClause:
+		a.apply(n, "Value")
+		a.apply(n, "Min")
+		a.apply(n, "Max")
+	case *DictionaryLayoutClause:
+		a.apply(n, "Layout")
+		a.applyList(n, "Args")
+	case *DictionaryRangeClause:
+		a.apply(n, "Min")
+		a.apply(n, "Max")
+	case *PlaceHolder:
+		// Leaf node
+	case *TypedPlaceholder:
+		a.apply(n, "Name")
+		a.apply(n, "Type")
+	case *QueryParam:
+		a.apply(n, "Name")
+		a.apply(n, "Type")
+	case *MapLiteral:
+		for i := range n.KeyValues {
+			a.applyHeld(n, &n.KeyValues[i], "Key")
+			a.applyHeld(n, &n.KeyValues[i], "Value")
+		}
+	case *NamedParameterExpr:
+		a.apply(n, "Name")
+		a.apply(n, "Value")
+	case *ObjectParams:
+		a.apply(n, "Object")
+		a.apply(n, "Params")
+	case *WindowFunctionExpr:
+		a.apply(n, "Function")
+		a.apply(n, "OverExpr")
+	case *NotExpr:
+		a.apply(n, "Expr")
+	case *NegateExpr:
+		a.apply(n, "Expr")
+	case *GlobalInOperation:
+		a.apply(n, "Expr")
+	case *ExtractExpr:
+		a.applyList(n, "Parameters")
+	case *IntervalFrom:
+		a.apply(n, "Interval")
+		a.apply(n, "FromExpr")
+	case *IsNullExpr:
+		a.apply(n, "Expr")
+	case *IsNotNullExpr:
+		a.apply(n, "Expr")
+	case *TernaryOperation:
+		a.apply(n, "Condition")
+		a.apply(n, "TrueExpr")
+		a.apply(n, "FalseExpr")
+	case *IndexOperation:
+		a.apply(n, "Object")
+		a.apply(n, "Index")
+	case *OperationExpr:
+		// Leaf node
+	case *TableIndex:
+		a.apply(n, "Name")
+		a.apply(n, "ColumnExpr")
+		a.apply(n, "ColumnType")
+		a.apply(n, "Granularity")
+	case *ProjectionOrderByClause:
+		a.apply(n, "Columns")
+	case *ProjectionSelectStmt:
+		a.apply(n, "With")
+		a.apply(n, "SelectColumns")
+		a.apply(n, "GroupBy")
+		a.apply(n, "OrderBy")
+	case *TableProjection:
+		a.apply(n, "Identifier")
+		a.apply(n, "Select")
+	case *RemovePropertyType:
+		a.apply(n, "PropertyType")
+	case *EnumType:
+		a.apply(n, "Name")
+		a.applyList(n, "Values")
+	case *EnumValue:
+		a.apply(n, "Name")
+		a.apply(n, "Value")
+	case *ClusterClause:
+		a.apply(n, "Expr")
+	case *PartitionClause:
+		a.apply(n, "Expr")
+		a.apply(n, "ID")
+	case *UUID:
+		a.apply(n, "Value")
+	case *ColumnTypeExpr:
+		a.apply(n, "Name")
+	case *UnaryExpr:
+		a.apply(n, "Expr")
+	case *JoinConstraintClause:
+		a.apply(n, "On")
+		a.apply(n, "Using")
+	case *TargetPair:
+		a.apply(n, "Old")
+		a.apply(n, "New")
+	case *ShowStmt:
+		a.apply(n, "Target")
+		a.apply(n, "Database")
+		a.applyList(n, "Users")
+		a.apply(n, "LikePattern")
+		a.apply(n, "Limit")
+		a.apply(n, "OutFile")
+		a.apply(n, "Format")
+	case *DescribeStmt:
+		a.apply(n, "Target")
+	case *DistinctOn:
+		a.applyList(n, "Idents")
+	}
+
+}
+
+func (a *applier) applyJSONOptions(parent Expr, options *JSONOptions) {
+	if options == nil {
+		return
+	}
+	for _, option := range options.Items {
+		if option == nil {
+			continue
+		}
+		a.applyJSONPath(parent, option.SkipPath)
+		a.applyHeld(parent, option, "SkipRegex")
+		a.applyHeld(parent, option, "MaxDynamicPaths")
+		a.applyHeld(parent, option, "MaxDynamicTypes")
+		if option.Column != nil {
+			a.applyJSONPath(parent, option.Column.Path)
+			a.applyHeld(parent, option.Column, "Type")
+		}
+	}
+}
+
+func (a *applier) applyJSONPath(parent Expr, path *JSONPath) {
+	if path == nil {
+		return
+	}
+	a.applyHeldList(parent, path, "Idents")
+}
@@ -0,0 +1,358 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseSingleStmt(t *testing.T, sql string) Expr {
+	t.Helper()
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	return stmts[0]
+}
+
+func TestTransform_RenameTables(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM db.events AS e JOIN users ON e.uid = users.id WHERE e.id IN (SELECT id FROM events)`)
+
+	result := Transform(stmt, func(node Expr) Expr {
+		if table, ok := node.(*TableIdentifier); ok && table.Table.Name == "events" {
+			return &TableIdentifier{
+				Database: &Ident{Name: "archive"},
+				Table:    &Ident{Name: "events_v2"},
+			}
+		}
+		return node
+	})
+
+	require.Same(t, stmt, result)
+	require.Equal(t,
+		"SELECT a FROM archive.events_v2 AS e JOIN users ON e.uid = users.id WHERE e.id IN (SELECT id FROM archive.events_v2)",
+		Format(result))
+}
+
+func TestTransform_MaskLiterals(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT * FROM t WHERE name = 'alice' AND age > 30 AND id IN (1, 2) LIMIT 10`)
+
+	result := Transform(stmt, func(node Expr) Expr {
+		switch node.(type) {
+		case *StringLiteral, *NumberLiteral:
+			return &PlaceHolder{Type: "?"}
+		}
+		return node
+	})
+
+	require.Equal(t, "SELECT * FROM t WHERE name = ? AND age > ? AND id IN (?, ?) LIMIT ?", Format(result))
+}
+
+func TestTransform_ReplaceRoot(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT 1`)
+	replacement := &UseStmt{Database: &Ident{Name: "db"}}
+
+	result := Transform(stmt, func(node Expr) Expr {
+		if _, ok := node.(*SelectQuery); ok {
+			return replacement
+		}
+		return node
+	})
+	require.Same(t, replacement, result)
+}
+
+func TestTransform_ValueHeldNodes(t *testing.T) {
+	stmt := parseSingleStmt(t, `CREATE TABLE t (e Enum('a' = 1, 'b' = 2)) ENGINE = Memory`)
+
+	Transform(stmt, func(node Expr) Expr {
+		if value, ok := node.(*EnumValue); ok && value.Name.Literal == "b" {
+			return &EnumValue{
+				Name:  &StringLiteral{Literal: "c"},
+				Value: &NumberLiteral{Literal: "3"},
+			}
+		}
+		return node
+	})
+	require.Equal(t, "CREATE TABLE t (e Enum('a'=1, 'c'=3)) ENGINE = Memory", Format(stmt))
+}
+
+func TestRewrite_RemoveNodes(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a, secret, b, secret FROM t WHERE x = 1`)
+
+	Rewrite(stmt, func(node Expr) Expr {
+		switch n := node.(type) {
+		case *SelectItem:
+			if ident, ok := n.Expr.(*Ident); ok && ident.Name == "secret" {
+				return nil
+			}
+		case *WhereClause:
+			return nil
+		}
+		return node
+	}, nil)
+
+	require.Equal(t, "SELECT a, b FROM t", Format(stmt))
+}
+
+func TestRewrite_PreReplacementIsNotDescended(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM t WHERE x = 1 AND y = 2`)
+
+	var visited []Expr
+	Rewrite(stmt, func(node Expr) Expr {
+		if _, ok := node.(*WhereClause); ok {
+			return &WhereClause{Expr: &BoolLiteral{Literal: "true"}}
+		}
+		return node
+	}, func(node Expr) Expr {
+		visited = append(visited, node)
+		return node
+	})
+
+	for _, node := range visited {
+		_, isBinary := node.(*BinaryOperation)
+		require.False(t, isBinary, "the replaced WHERE condition must not be visited")
+	}
+	require.Equal(t, "SELECT a FROM t WHERE true", Format(stmt))
+}
+
+func TestRewrite_PostOrder(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT f(g(1))`)
+
+	var names []string
+	Rewrite(stmt, nil, func(node Expr) Expr {
+		if fn, ok := node.(*FunctionExpr); ok {
+			names = append(names, fn.Name.Name)
+		}
+		return node
+	})
+	require.Equal(t, []string{"g", "f"}, names)
+}
+
+func TestRewrite_IncompatibleReplacementPanics(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM t`)
+
+	require.PanicsWithValue(t, "parser: cannot use *parser.NumberLiteral as *parser.Ident in *parser.TableIdentifier.Table", func() {
+		Transform(stmt, func(node Expr) Expr {
+			if ident, ok := node.(*Ident); ok && ident.Name == "t" {
+				return &NumberLiteral{Literal: "1"}
+			}
+			return node
+		})
+	})
+}
+
+// TestRewrite_VisitsSameNodesAsWalk runs an identity rewrite over every test
+// SQL file and checks it reaches the same nodes, in the same order, as Walk.
+// Walk steps over RenameStmt's TargetPair nodes straight into their tables,
+// which Rewrite visits so that pairs can be replaced or removed.
+func TestRewrite_VisitsSameNodesAsWalk(t *testing.T) {
+	for _, dir := range []string{"./testdata/dml", "./testdata/ddl", "./testdata/query", "./testdata/basic"} {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			t.Run(entry.Name(), func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				stmts, err := NewParser(string(fileBytes)).ParseStmts()
+				require.NoError(t, err)
+				for _, stmt := range stmts {
+					var walked, rewritten []Expr
+					Walk(stmt, func(node Expr) bool {
+						walked = append(walked, node)
+						return true
+					})
+					formatted := Format(stmt)
+					result := Rewrite(stmt, func(node Expr) Expr {
+						if _, ok := node.(*TargetPair); !ok {
+							rewritten = append(rewritten, node)
+						}
+						return node
+					}, nil)
+					require.Same(t, stmt, result)
+					require.Equal(t, walked, rewritten)
+					require.Equal(t, formatted, Format(result))
+				}
+			})
+		}
+	}
+}
+
+func TestApply_CursorPosition(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a, b FROM t`)
+
+	var seen []string
+	Apply(stmt, func(c *Cursor) bool {
+		if ident, ok := c.Node().(*Ident); ok {
+			seen = append(seen, fmt.Sprintf("%s %T.%s[%d]", ident.Name, c.Parent(), c.Name(), c.Index()))
+		}
+		if c.Parent() == nil {
+			require.Same(t, stmt, c.Node())
+			require.Equal(t, "", c.Name())
+			require.Equal(t, -1, c.Index())
+		}
+		return true
+	}, nil)
+
+	require.Equal(t, []string{
+		"a *parser.SelectItem.Expr[-1]",
+		"b *parser.SelectItem.Expr[-1]",
+		"t *parser.TableIdentifier.Table[-1]",
+	}, seen)
+
+	var items []int
+	Apply(stmt, func(c *Cursor) bool {
+		if _, ok := c.Node().(*SelectItem); ok {
+			require.Equal(t, "SelectItems", c.Name())
+			items = append(items, c.Index())
+		}
+		return true
+	}, nil)
+	require.Equal(t, []int{0, 1}, items)
+}
+
+func TestApply_DeleteSelectItems(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a, _internal_x, _internal_y, b FROM t`)
+
+	Apply(stmt, func(c *Cursor) bool {
+		if item, ok := c.Node().(*SelectItem); ok && strings.HasPrefix(Format(item), "_internal") {
+			c.Delete()
+		}
+		return true
+	}, nil)
+	require.Equal(t, "SELECT a, b FROM t", Format(stmt))
+}
+
+func TestApply_DeleteColumnExprListItems(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM t GROUP BY a, b, c`)
+
+	Apply(stmt, func(c *Cursor) bool {
+		if _, ok := c.Parent().(*ColumnExprList); ok && Format(c.Node()) == "b" {
+			require.Equal(t, "Items", c.Name())
+			c.Delete()
+		}
+		return true
+	}, nil)
+	require.Equal(t, "SELECT a FROM t GROUP BY a, c", Format(stmt))
+}
+
+func TestApply_InsertAlterClauses(t *testing.T) {
+	stmt := parseSingleStmt(t, `ALTER TABLE t DROP COLUMN a`)
+
+	var inserted []Expr
+	Apply(stmt, func(c *Cursor) bool {
+		inserted = append(inserted, c.Node())
+		if drop, ok := c.Node().(*AlterTableDropColumn); ok && c.Name() == "AlterExprs" {
+			if drop.ColumnName.Ident.Name == "a" {
+				c.InsertAfter(&AlterTableDropColumn{
+					ColumnName: &NestedIdentifier{Ident: &Ident{Name: "c"}},
+				})
+				c.InsertBefore(&AlterTableDropColumn{
+					ColumnName: &NestedIdentifier{Ident: &Ident{Name: "b"}},
+				})
+			}
+		}
+		return true
+	}, nil)
+	require.Equal(t, "ALTER TABLE t DROP COLUMN b, DROP COLUMN a, DROP COLUMN c", Format(stmt))
+
+	// Inserted nodes are not traversed.
+	var dropped []string
+	for _, node := range inserted {
+		if drop, ok := node.(*AlterTableDropColumn); ok {
+			dropped = append(dropped, drop.ColumnName.Ident.Name)
+		}
+	}
+	require.Equal(t, []string{"a"}, dropped)
+}
+
+func TestApply_InsertTableColumns(t *testing.T) {
+	stmt := parseSingleStmt(t, `CREATE TABLE t (id UInt64) ENGINE = Memory`)
+
+	Apply(stmt, func(c *Cursor) bool {
+		if _, ok := c.Parent().(*TableSchemaClause); ok && c.Name() == "Columns" {
+			c.InsertAfter(&ColumnDef{
+				Name: &NestedIdentifier{Ident: &Ident{Name: "tenant"}},
+				Type: &ScalarType{Name: &Ident{Name: "String"}},
+			})
+			return false
+		}
+		return true
+	}, nil)
+	require.Equal(t, "CREATE TABLE t (id UInt64, tenant String) ENGINE = Memory", Format(stmt))
+}
+
+func TestApply_PreFalseSkipsChildrenAndPost(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM t WHERE x = 1`)
+
+	var post []Expr
+	Apply(stmt, func(c *Cursor) bool {
+		_, isWhere := c.Node().(*WhereClause)
+		return !isWhere
+	}, func(c *Cursor) bool {
+		post = append(post, c.Node())
+		return true
+	})
+	for _, node := range post {
+		require.NotContains(t, []string{"*parser.WhereClause", "*parser.BinaryOperation"}, fmt.Sprintf("%T", node))
+	}
+	require.NotEmpty(t, post)
+}
+
+func TestApply_PostFalseStops(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a, b, c FROM t`)
+
+	var idents []string
+	result := Apply(stmt, nil, func(c *Cursor) bool {
+		if ident, ok := c.Node().(*Ident); ok {
+			idents = append(idents, ident.Name)
+			return ident.Name != "b"
+		}
+		return true
+	})
+	require.Same(t, stmt, result)
+	require.Equal(t, []string{"a", "b"}, idents)
+}
+
+func TestApply_HelperStructFields(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT {'k': 1}`)
+
+	var names []string
+	Apply(stmt, func(c *Cursor) bool {
+		if _, ok := c.Parent().(*MapLiteral); ok {
+			names = append(names, c.Name())
+			if _, ok := c.Node().(*NumberLiteral); ok {
+				c.Replace(&NumberLiteral{Literal: "2"})
+			}
+		}
+		return true
+	}, nil)
+	require.Equal(t, []string{"Key", "Value"}, names)
+	require.Equal(t, "SELECT {'k': 2}", Format(stmt))
+}
+
+func TestApply_InvalidEditsPanic(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM t`)
+
+	require.PanicsWithValue(t, "parser: cannot delete from *parser.SelectQuery.From: not a slice element", func() {
+		Apply(stmt, func(c *Cursor) bool {
+			if _, ok := c.Node().(*FromClause); ok {
+				c.Delete()
+			}
+			return true
+		}, nil)
+	})
+	require.PanicsWithValue(t, "parser: cannot use *parser.Ident as *parser.SelectItem in *parser.SelectQuery.SelectItems", func() {
+		Apply(stmt, func(c *Cursor) bool {
+			if _, ok := c.Node().(*SelectItem); ok {
+				c.InsertAfter(&Ident{Name: "b"})
+			}
+			return true
+		}, nil)
+	})
+}
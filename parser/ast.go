@@ -444,6 +444,7 @@ type AlterTableAddColumn struct {
 	Column      *ColumnDef
 	IfNotExists bool
 	After       *NestedIdentifier
+	First       bool // FIRST: the column goes before all the others
 	Settings    *SettingsClause
 }
 
@@ -888,7 +889,7 @@ func (a *AlterTableRenameColumn) Accept(visitor ASTVisitor) error {
 type AlterTableModifyQuery struct {
 	ModifyPos    Pos
 	StatementEnd Pos
-	SelectExpr   *SelectQuery
+	SelectExpr   Expr // *SelectQuery or *SetOperationExpr
 }
 
 func (a *AlterTableModifyQuery) Pos() Pos {
@@ -1092,6 +1093,7 @@ func (a *AlterTableReplacePartition) Accept(visitor ASTVisitor) error {
 type AlterTableDelete struct {
 	DeletePos    Pos
 	StatementEnd Pos
+	InPartition  *PartitionClause
 	WhereClause  Expr
 }
 
@@ -1110,6 +1112,11 @@ func (a *AlterTableDelete) AlterType() string {
 func (a *AlterTableDelete) Accept(visitor ASTVisitor) error {
 	visitor.Enter(a)
 	defer visitor.Leave(a)
+	if a.InPartition != nil {
+		if err := a.InPartition.Accept(visitor); err != nil {
+			return err
+		}
+	}
 	if err := a.WhereClause.Accept(visitor); err != nil {
 		return err
 	}
@@ -1155,6 +1162,278 @@ func (a *AlterTableUpdate) Accept(visitor ASTVisitor) error {
 	return visitor.VisitAlterTableUpdate(a)
 }
 
+type AlterTableMovePartition struct {
+	MovePos      Pos
+	StatementEnd Pos
+	Partition    *PartitionClause
+	ToDisk       *StringLiteral
+	ToVolume     *StringLiteral
+	ToTable      *TableIdentifier
+}
+
+func (a *AlterTableMovePartition) Pos() Pos {
+	return a.MovePos
+}
+
+func (a *AlterTableMovePartition) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableMovePartition) AlterType() string {
+	return "MOVE_PARTITION"
+}
+
+func (a *AlterTableMovePartition) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.Partition != nil {
+		if err := a.Partition.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.ToDisk != nil {
+		if err := a.ToDisk.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.ToVolume != nil {
+		if err := a.ToVolume.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.ToTable != nil {
+		if err := a.ToTable.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableMovePartition(a)
+}
+
+type AlterTableFetchPartition struct {
+	FetchPos     Pos
+	StatementEnd Pos
+	Partition    *PartitionClause
+	From         *StringLiteral
+}
+
+func (a *AlterTableFetchPartition) Pos() Pos {
+	return a.FetchPos
+}
+
+func (a *AlterTableFetchPartition) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableFetchPartition) AlterType() string {
+	return "FETCH_PARTITION"
+}
+
+func (a *AlterTableFetchPartition) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.Partition != nil {
+		if err := a.Partition.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.From != nil {
+		if err := a.From.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableFetchPartition(a)
+}
+
+type AlterTableAddConstraint struct {
+	AddPos       Pos
+	StatementEnd Pos
+	IfNotExists  bool
+	Name         *Ident
+	Assume       bool // ASSUME instead of CHECK
+	Expr         Expr
+}
+
+func (a *AlterTableAddConstraint) Pos() Pos {
+	return a.AddPos
+}
+
+func (a *AlterTableAddConstraint) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableAddConstraint) AlterType() string {
+	return "ADD_CONSTRAINT"
+}
+
+func (a *AlterTableAddConstraint) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.Name != nil {
+		if err := a.Name.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.Expr != nil {
+		if err := a.Expr.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableAddConstraint(a)
+}
+
+type AlterTableDropConstraint struct {
+	DropPos      Pos
+	StatementEnd Pos
+	IfExists     bool
+	Name         *Ident
+}
+
+func (a *AlterTableDropConstraint) Pos() Pos {
+	return a.DropPos
+}
+
+func (a *AlterTableDropConstraint) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableDropConstraint) AlterType() string {
+	return "DROP_CONSTRAINT"
+}
+
+func (a *AlterTableDropConstraint) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.Name != nil {
+		if err := a.Name.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableDropConstraint(a)
+}
+
+type AlterTableCommentColumn struct {
+	CommentPos   Pos
+	StatementEnd Pos
+	IfExists     bool
+	ColumnName   *NestedIdentifier
+	Comment      *StringLiteral
+}
+
+func (a *AlterTableCommentColumn) Pos() Pos {
+	return a.CommentPos
+}
+
+func (a *AlterTableCommentColumn) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableCommentColumn) AlterType() string {
+	return "COMMENT_COLUMN"
+}
+
+func (a *AlterTableCommentColumn) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.ColumnName != nil {
+		if err := a.ColumnName.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.Comment != nil {
+		if err := a.Comment.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableCommentColumn(a)
+}
+
+type AlterTableModifyComment struct {
+	ModifyPos    Pos
+	StatementEnd Pos
+	Comment      *StringLiteral
+}
+
+func (a *AlterTableModifyComment) Pos() Pos {
+	return a.ModifyPos
+}
+
+func (a *AlterTableModifyComment) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableModifyComment) AlterType() string {
+	return "MODIFY_COMMENT"
+}
+
+func (a *AlterTableModifyComment) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.Comment != nil {
+		if err := a.Comment.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableModifyComment(a)
+}
+
+type AlterTableModifySampleBy struct {
+	ModifyPos    Pos
+	StatementEnd Pos
+	SampleBy     Expr
+}
+
+func (a *AlterTableModifySampleBy) Pos() Pos {
+	return a.ModifyPos
+}
+
+func (a *AlterTableModifySampleBy) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableModifySampleBy) AlterType() string {
+	return "MODIFY_SAMPLE_BY"
+}
+
+func (a *AlterTableModifySampleBy) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.SampleBy != nil {
+		if err := a.SampleBy.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableModifySampleBy(a)
+}
+
+type AlterTableApplyDeletedMask struct {
+	ApplyPos     Pos
+	StatementEnd Pos
+	InPartition  *PartitionClause
+}
+
+func (a *AlterTableApplyDeletedMask) Pos() Pos {
+	return a.ApplyPos
+}
+
+func (a *AlterTableApplyDeletedMask) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterTableApplyDeletedMask) AlterType() string {
+	return "APPLY_DELETED_MASK"
+}
+
+func (a *AlterTableApplyDeletedMask) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	if a.InPartition != nil {
+		if err := a.InPartition.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterTableApplyDeletedMask(a)
+}
+
 type UpdateAssignment struct {
 	AssignmentPos Pos
 	Column        *NestedIdentifier
@@ -1891,89 +2170,661 @@ type CreateUser struct {
 	Settings        []*RoleSetting
 }
 
-func (c *CreateUser) Pos() Pos {
+func (c *CreateUser) Pos() Pos {
+	return c.CreatePos
+}
+
+func (c *CreateUser) End() Pos {
+	return c.StatementEnd
+}
+
+func (c *CreateUser) Type() string {
+	return "USER"
+}
+
+func (c *CreateUser) Accept(visitor ASTVisitor) error {
+	visitor.Enter(c)
+	defer visitor.Leave(c)
+	for _, userName := range c.UserNames {
+		if err := userName.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.Authentication != nil {
+		if err := c.Authentication.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.ValidUntil != nil {
+		if err := c.ValidUntil.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, host := range c.Hosts {
+		if err := host.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.DefaultRole != nil {
+		if err := c.DefaultRole.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.DefaultDatabase != nil {
+		if err := c.DefaultDatabase.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.Grantees != nil {
+		if err := c.Grantees.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, setting := range c.Settings {
+		if err := setting.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitCreateUser(c)
+}
+
+type AlterRole struct {
+	AlterPos        Pos
+	StatementEnd    Pos
+	IfExists        bool
+	RoleRenamePairs []*RoleRenamePair
+	Settings        []*RoleSetting
+}
+
+func (a *AlterRole) Pos() Pos {
+	return a.AlterPos
+}
+
+func (a *AlterRole) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterRole) Type() string {
+	return "ROLE"
+}
+
+func (a *AlterRole) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	for _, roleRenamePair := range a.RoleRenamePairs {
+		if err := roleRenamePair.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, setting := range a.Settings {
+		if err := setting.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterRole(a)
+}
+
+type RoleRenamePair struct {
+	RoleName     *RoleName
+	NewName      Expr
+	StatementEnd Pos
+}
+
+func (r *RoleRenamePair) Pos() Pos {
+	return r.RoleName.Pos()
+}
+
+func (r *RoleRenamePair) End() Pos {
+	return r.StatementEnd
+}
+
+func (r *RoleRenamePair) Accept(visitor ASTVisitor) error {
+	visitor.Enter(r)
+	defer visitor.Leave(r)
+	if err := r.RoleName.Accept(visitor); err != nil {
+		return err
+	}
+	if r.NewName != nil {
+		if err := r.NewName.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitRoleRenamePair(r)
+}
+
+type AlterUser struct {
+	AlterPos        Pos
+	StatementEnd    Pos
+	IfExists        bool
+	UserRenamePairs []*RoleRenamePair
+	Authentication  *AuthenticationClause
+	ValidUntil      *StringLiteral
+	Hosts           []*HostClause
+	AddHosts        []*HostClause
+	DropHosts       []*HostClause
+	DefaultRole     *DefaultRoleClause
+	DefaultDatabase *Ident
+	DefaultDbNone   bool
+	Grantees        *GranteesClause
+	Settings        []*RoleSetting
+}
+
+func (a *AlterUser) Pos() Pos {
+	return a.AlterPos
+}
+
+func (a *AlterUser) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterUser) Type() string {
+	return "USER"
+}
+
+func (a *AlterUser) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	for _, pair := range a.UserRenamePairs {
+		if err := pair.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.Authentication != nil {
+		if err := a.Authentication.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.ValidUntil != nil {
+		if err := a.ValidUntil.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, host := range a.Hosts {
+		if err := host.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, host := range a.AddHosts {
+		if err := host.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, host := range a.DropHosts {
+		if err := host.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.DefaultRole != nil {
+		if err := a.DefaultRole.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.DefaultDatabase != nil {
+		if err := a.DefaultDatabase.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.Grantees != nil {
+		if err := a.Grantees.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, setting := range a.Settings {
+		if err := setting.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterUser(a)
+}
+
+// ToRolesClause is the TO clause assigning a quota, row policy or settings
+// profile to roles and users.
+type ToRolesClause struct {
+	ToPos  Pos
+	ToEnd  Pos
+	Roles  []*RoleName
+	All    bool
+	None   bool
+	Except []*RoleName
+}
+
+func (t *ToRolesClause) Pos() Pos {
+	return t.ToPos
+}
+
+func (t *ToRolesClause) End() Pos {
+	return t.ToEnd
+}
+
+func (t *ToRolesClause) Accept(visitor ASTVisitor) error {
+	visitor.Enter(t)
+	defer visitor.Leave(t)
+	for _, role := range t.Roles {
+		if err := role.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, except := range t.Except {
+		if err := except.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitToRolesClause(t)
+}
+
+// QuotaLimit is a FOR [RANDOMIZED] INTERVAL clause of a quota with either the
+// MAX limits of the interval, NO LIMITS or TRACKING ONLY.
+type QuotaLimit struct {
+	ForPos       Pos
+	LimitEnd     Pos
+	Randomized   bool
+	Interval     *NumberLiteral
+	Unit         *Ident
+	Limits       []*SettingPair
+	NoLimits     bool
+	TrackingOnly bool
+}
+
+func (q *QuotaLimit) Pos() Pos {
+	return q.ForPos
+}
+
+func (q *QuotaLimit) End() Pos {
+	return q.LimitEnd
+}
+
+func (q *QuotaLimit) Accept(visitor ASTVisitor) error {
+	visitor.Enter(q)
+	defer visitor.Leave(q)
+	if q.Interval != nil {
+		if err := q.Interval.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if q.Unit != nil {
+		if err := q.Unit.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, limit := range q.Limits {
+		if err := limit.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitQuotaLimit(q)
+}
+
+type CreateQuota struct {
+	CreatePos         Pos
+	StatementEnd      Pos
+	IfNotExists       bool
+	OrReplace         bool
+	Names             []*RoleName
+	AccessStorageType *Ident
+	KeyedBy           []*Ident
+	NotKeyed          bool
+	Limits            []*QuotaLimit
+	To                *ToRolesClause
+}
+
+func (c *CreateQuota) Pos() Pos {
+	return c.CreatePos
+}
+
+func (c *CreateQuota) End() Pos {
+	return c.StatementEnd
+}
+
+func (c *CreateQuota) Type() string {
+	return "QUOTA"
+}
+
+func (c *CreateQuota) Accept(visitor ASTVisitor) error {
+	visitor.Enter(c)
+	defer visitor.Leave(c)
+	for _, name := range c.Names {
+		if err := name.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.AccessStorageType != nil {
+		if err := c.AccessStorageType.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, key := range c.KeyedBy {
+		if err := key.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, limit := range c.Limits {
+		if err := limit.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.To != nil {
+		if err := c.To.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitCreateQuota(c)
+}
+
+type AlterQuota struct {
+	AlterPos     Pos
+	StatementEnd Pos
+	IfExists     bool
+	RenamePairs  []*RoleRenamePair
+	KeyedBy      []*Ident
+	NotKeyed     bool
+	Limits       []*QuotaLimit
+	To           *ToRolesClause
+}
+
+func (a *AlterQuota) Pos() Pos {
+	return a.AlterPos
+}
+
+func (a *AlterQuota) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterQuota) Type() string {
+	return "QUOTA"
+}
+
+func (a *AlterQuota) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	for _, pair := range a.RenamePairs {
+		if err := pair.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, key := range a.KeyedBy {
+		if err := key.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, limit := range a.Limits {
+		if err := limit.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.To != nil {
+		if err := a.To.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterQuota(a)
+}
+
+// RowPolicyName names a row policy by its name and table, e.g.
+// `filter ON CLUSTER c ON db.table`. On is nil when the name shares the table
+// of the next one, as in `DROP ROW POLICY a, b ON t`. RenameTo is only set by
+// ALTER ROW POLICY.
+type RowPolicyName struct {
+	Name      *Ident
+	OnCluster *ClusterClause
+	On        *TableIdentifier
+	RenameTo  *Ident
+}
+
+func (r *RowPolicyName) Pos() Pos {
+	return r.Name.NamePos
+}
+
+func (r *RowPolicyName) End() Pos {
+	switch {
+	case r.RenameTo != nil:
+		return r.RenameTo.NameEnd
+	case r.On != nil:
+		return r.On.End()
+	case r.OnCluster != nil:
+		return r.OnCluster.End()
+	}
+	return r.Name.NameEnd
+}
+
+func (r *RowPolicyName) Accept(visitor ASTVisitor) error {
+	visitor.Enter(r)
+	defer visitor.Leave(r)
+	if r.Name != nil {
+		if err := r.Name.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if r.OnCluster != nil {
+		if err := r.OnCluster.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if r.On != nil {
+		if err := r.On.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if r.RenameTo != nil {
+		if err := r.RenameTo.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitRowPolicyName(r)
+}
+
+type CreateRowPolicy struct {
+	CreatePos         Pos
+	StatementEnd      Pos
+	IfNotExists       bool
+	OrReplace         bool
+	Policies          []*RowPolicyName
+	AccessStorageType *Ident
+	ForSelect         bool
+	Using             Expr
+	As                string // "PERMISSIVE" or "RESTRICTIVE"
+	To                *ToRolesClause
+}
+
+func (c *CreateRowPolicy) Pos() Pos {
+	return c.CreatePos
+}
+
+func (c *CreateRowPolicy) End() Pos {
+	return c.StatementEnd
+}
+
+func (c *CreateRowPolicy) Type() string {
+	return "ROW POLICY"
+}
+
+func (c *CreateRowPolicy) Accept(visitor ASTVisitor) error {
+	visitor.Enter(c)
+	defer visitor.Leave(c)
+	for _, policy := range c.Policies {
+		if err := policy.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.AccessStorageType != nil {
+		if err := c.AccessStorageType.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.Using != nil {
+		if err := c.Using.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if c.To != nil {
+		if err := c.To.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitCreateRowPolicy(c)
+}
+
+type AlterRowPolicy struct {
+	AlterPos     Pos
+	StatementEnd Pos
+	IfExists     bool
+	Policies     []*RowPolicyName
+	As           string // "PERMISSIVE" or "RESTRICTIVE"
+	ForSelect    bool
+	Using        Expr
+	UsingNone    bool
+	To           *ToRolesClause
+}
+
+func (a *AlterRowPolicy) Pos() Pos {
+	return a.AlterPos
+}
+
+func (a *AlterRowPolicy) End() Pos {
+	return a.StatementEnd
+}
+
+func (a *AlterRowPolicy) Type() string {
+	return "ROW POLICY"
+}
+
+func (a *AlterRowPolicy) Accept(visitor ASTVisitor) error {
+	visitor.Enter(a)
+	defer visitor.Leave(a)
+	for _, policy := range a.Policies {
+		if err := policy.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.Using != nil {
+		if err := a.Using.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if a.To != nil {
+		if err := a.To.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitAlterRowPolicy(a)
+}
+
+type DropRowPolicy struct {
+	DropPos      Pos
+	StatementEnd Pos
+	IfExists     bool
+	Policies     []*RowPolicyName
+	OnCluster    *ClusterClause
+	From         *Ident
+}
+
+func (d *DropRowPolicy) Pos() Pos {
+	return d.DropPos
+}
+
+func (d *DropRowPolicy) End() Pos {
+	return d.StatementEnd
+}
+
+func (d *DropRowPolicy) Type() string {
+	return "ROW POLICY"
+}
+
+func (d *DropRowPolicy) Accept(visitor ASTVisitor) error {
+	visitor.Enter(d)
+	defer visitor.Leave(d)
+	for _, policy := range d.Policies {
+		if err := policy.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if d.OnCluster != nil {
+		if err := d.OnCluster.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if d.From != nil {
+		if err := d.From.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitDropRowPolicy(d)
+}
+
+type CreateSettingsProfile struct {
+	CreatePos         Pos
+	StatementEnd      Pos
+	IfNotExists       bool
+	OrReplace         bool
+	Names             []*RoleName
+	AccessStorageType *Ident
+	Settings          []*RoleSetting
+	To                *ToRolesClause
+}
+
+func (c *CreateSettingsProfile) Pos() Pos {
 	return c.CreatePos
 }
 
-func (c *CreateUser) End() Pos {
+func (c *CreateSettingsProfile) End() Pos {
 	return c.StatementEnd
 }
 
-func (c *CreateUser) Type() string {
-	return "USER"
+func (c *CreateSettingsProfile) Type() string {
+	return "SETTINGS PROFILE"
 }
 
-func (c *CreateUser) Accept(visitor ASTVisitor) error {
+func (c *CreateSettingsProfile) Accept(visitor ASTVisitor) error {
 	visitor.Enter(c)
 	defer visitor.Leave(c)
-	for _, userName := range c.UserNames {
-		if err := userName.Accept(visitor); err != nil {
-			return err
-		}
-	}
-	if c.Authentication != nil {
-		if err := c.Authentication.Accept(visitor); err != nil {
-			return err
-		}
-	}
-	if c.ValidUntil != nil {
-		if err := c.ValidUntil.Accept(visitor); err != nil {
-			return err
-		}
-	}
-	for _, host := range c.Hosts {
-		if err := host.Accept(visitor); err != nil {
-			return err
-		}
-	}
-	if c.DefaultRole != nil {
-		if err := c.DefaultRole.Accept(visitor); err != nil {
+	for _, name := range c.Names {
+		if err := name.Accept(visitor); err != nil {
 			return err
 		}
 	}
-	if c.DefaultDatabase != nil {
-		if err := c.DefaultDatabase.Accept(visitor); err != nil {
+	if c.AccessStorageType != nil {
+		if err := c.AccessStorageType.Accept(visitor); err != nil {
 			return err
 		}
 	}
-	if c.Grantees != nil {
-		if err := c.Grantees.Accept(visitor); err != nil {
+	for _, setting := range c.Settings {
+		if err := setting.Accept(visitor); err != nil {
 			return err
 		}
 	}
-	for _, setting := range c.Settings {
-		if err := setting.Accept(visitor); err != nil {
+	if c.To != nil {
+		if err := c.To.Accept(visitor); err != nil {
 			return err
 		}
 	}
-	return visitor.VisitCreateUser(c)
+	return visitor.VisitCreateSettingsProfile(c)
 }
 
-type AlterRole struct {
-	AlterPos        Pos
-	StatementEnd    Pos
-	IfExists        bool
-	RoleRenamePairs []*RoleRenamePair
-	Settings        []*RoleSetting
+type AlterSettingsProfile struct {
+	AlterPos     Pos
+	StatementEnd Pos
+	IfExists     bool
+	RenamePairs  []*RoleRenamePair
+	Settings     []*RoleSetting
+	To           *ToRolesClause
 }
 
-func (a *AlterRole) Pos() Pos {
+func (a *AlterSettingsProfile) Pos() Pos {
 	return a.AlterPos
 }
 
-func (a *AlterRole) End() Pos {
+func (a *AlterSettingsProfile) End() Pos {
 	return a.StatementEnd
 }
 
-func (a *AlterRole) Type() string {
-	return "ROLE"
+func (a *AlterSettingsProfile) Type() string {
+	return "SETTINGS PROFILE"
 }
 
-func (a *AlterRole) Accept(visitor ASTVisitor) error {
+func (a *AlterSettingsProfile) Accept(visitor ASTVisitor) error {
 	visitor.Enter(a)
 	defer visitor.Leave(a)
-	for _, roleRenamePair := range a.RoleRenamePairs {
-		if err := roleRenamePair.Accept(visitor); err != nil {
+	for _, pair := range a.RenamePairs {
+		if err := pair.Accept(visitor); err != nil {
 			return err
 		}
 	}
@@ -1982,35 +2833,12 @@ func (a *AlterRole) Accept(visitor ASTVisitor) error {
 			return err
 		}
 	}
-	return visitor.VisitAlterRole(a)
-}
-
-type RoleRenamePair struct {
-	RoleName     *RoleName
-	NewName      Expr
-	StatementEnd Pos
-}
-
-func (r *RoleRenamePair) Pos() Pos {
-	return r.RoleName.Pos()
-}
-
-func (r *RoleRenamePair) End() Pos {
-	return r.StatementEnd
-}
-
-func (r *RoleRenamePair) Accept(visitor ASTVisitor) error {
-	visitor.Enter(r)
-	defer visitor.Leave(r)
-	if err := r.RoleName.Accept(visitor); err != nil {
-		return err
-	}
-	if r.NewName != nil {
-		if err := r.NewName.Accept(visitor); err != nil {
+	if a.To != nil {
+		if err := a.To.Accept(visitor); err != nil {
 			return err
 		}
 	}
-	return visitor.VisitRoleRenamePair(r)
+	return visitor.VisitAlterSettingsProfile(a)
 }
 
 type DestinationClause struct {
@@ -2047,6 +2875,7 @@ func (d *DestinationClause) Accept(visitor ASTVisitor) error {
 type ConstraintClause struct {
 	ConstraintPos Pos
 	Constraint    *Ident
+	Assume        bool // ASSUME instead of CHECK
 	Expr          Expr
 }
 
@@ -4660,12 +5489,70 @@ func (u *UsingClause) Accept(visitor ASTVisitor) error {
 	return visitor.VisitUsingExpr(u)
 }
 
+// JoinLocality is the GLOBAL or LOCAL distribution of a join's right-hand table.
+type JoinLocality string
+
+const (
+	JoinLocalityNone   JoinLocality = ""
+	JoinLocalityGlobal JoinLocality = "GLOBAL"
+	JoinLocalityLocal  JoinLocality = "LOCAL"
+)
+
+// JoinStrictness says how many matching rows of the right-hand table a row joins with.
+type JoinStrictness string
+
+const (
+	JoinStrictnessNone JoinStrictness = ""
+	JoinStrictnessAll  JoinStrictness = "ALL"
+	JoinStrictnessAny  JoinStrictness = "ANY"
+	JoinStrictnessAsof JoinStrictness = "ASOF"
+	JoinStrictnessSemi JoinStrictness = "SEMI"
+	JoinStrictnessAnti JoinStrictness = "ANTI"
+)
+
+// JoinKind is the kind of a join. JoinKindNone is a bare JOIN, which
+// ClickHouse treats as INNER; JoinKindComma marks a table listed after a comma.
+type JoinKind string
+
+const (
+	JoinKindNone  JoinKind = ""
+	JoinKindInner JoinKind = "INNER"
+	JoinKindLeft  JoinKind = "LEFT"
+	JoinKindRight JoinKind = "RIGHT"
+	JoinKindFull  JoinKind = "FULL"
+	JoinKindCross JoinKind = "CROSS"
+	JoinKindPaste JoinKind = "PASTE"
+	JoinKindComma JoinKind = ","
+)
+
+// JoinConstraintType says whether a join matches rows with an ON condition
+// or a USING column list. CROSS, PASTE and comma joins have none.
+type JoinConstraintType string
+
+const (
+	JoinConstraintNone  JoinConstraintType = ""
+	JoinConstraintOn    JoinConstraintType = "ON"
+	JoinConstraintUsing JoinConstraintType = "USING"
+)
+
+// JoinExpr joins its Left table to the tables before it and chains the rest
+// of the FROM clause in Right: `a JOIN b JOIN c` is a JoinExpr with Left a,
+// whose Right is the JOIN of b, whose Right is the JOIN of c.
+// Modifiers keeps the keywords before and including JOIN in their source
+// spelling; Locality, Strictness, Kind, HasOuter and IsArray are the same
+// keywords typed, and are what the formatter writes.
 type JoinExpr struct {
-	JoinPos     Pos
-	Left        Expr
-	Right       Expr
-	Modifiers   []string
-	Constraints Expr
+	JoinPos        Pos
+	Left           Expr
+	Right          Expr
+	Modifiers      []string
+	Locality       JoinLocality
+	Strictness     JoinStrictness
+	Kind           JoinKind
+	HasOuter       bool
+	IsArray        bool
+	ConstraintType JoinConstraintType
+	Constraints    Expr
 }
 
 func (j *JoinExpr) Pos() Pos {
@@ -5267,29 +6154,25 @@ func (f *WindowFrameParam) Accept(visitor ASTVisitor) error {
 }
 
 type SelectQuery struct {
-	SelectPos     Pos
-	StatementEnd  Pos
-	With          *WithClause
-	Top           *TopClause
-	HasDistinct   bool
-	DistinctOn    *DistinctOn
-	SelectItems   []*SelectItem
-	From          *FromClause
-	Window        *WindowClause
-	Prewhere      *PrewhereClause
-	Where         *WhereClause
-	GroupBy       *GroupByClause
-	WithTotal     bool
-	Having        *HavingClause
-	OrderBy       *OrderByClause
-	LimitBy       *LimitByClause
-	Limit         *LimitClause
-	Settings      *SettingsClause
-	Format        *FormatClause
-	UnionAll      *SelectQuery
-	UnionDistinct *SelectQuery
-	Except        *SelectQuery
-	Intersect     *SelectQuery
+	SelectPos    Pos
+	StatementEnd Pos
+	With         *WithClause
+	Top          *TopClause
+	HasDistinct  bool
+	DistinctOn   *DistinctOn
+	SelectItems  []*SelectItem
+	From         *FromClause
+	Window       *WindowClause
+	Prewhere     *PrewhereClause
+	Where        *WhereClause
+	GroupBy      *GroupByClause
+	WithTotal    bool
+	Having       *HavingClause
+	OrderBy      *OrderByClause
+	LimitBy      *LimitByClause
+	Limit        *LimitClause
+	Settings     *SettingsClause
+	Format       *FormatClause
 }
 
 func (s *SelectQuery) Pos() Pos {
@@ -5380,27 +6263,67 @@ func (s *SelectQuery) Accept(visitor ASTVisitor) error {
 			return err
 		}
 	}
-	if s.UnionAll != nil {
-		if err := s.UnionAll.Accept(visitor); err != nil {
-			return err
-		}
-	}
-	if s.UnionDistinct != nil {
-		if err := s.UnionDistinct.Accept(visitor); err != nil {
-			return err
-		}
+	return visitor.VisitSelectQuery(s)
+}
+
+type SetOperator string
+
+const (
+	SetOperatorUnion     SetOperator = "UNION"
+	SetOperatorExcept    SetOperator = "EXCEPT"
+	SetOperatorIntersect SetOperator = "INTERSECT"
+)
+
+// SetModifier is the ALL or DISTINCT following a set operator.
+type SetModifier string
+
+const (
+	SetModifierNone     SetModifier = ""
+	SetModifierAll      SetModifier = "ALL"
+	SetModifierDistinct SetModifier = "DISTINCT"
+)
+
+// SetOperationExpr combines the results of two queries with UNION, EXCEPT or
+// INTERSECT. Each operand is a *SelectQuery, a nested *SetOperationExpr or a
+// parenthesized *SubQuery, which may carry its own ORDER BY and LIMIT.
+// INTERSECT binds tighter than UNION and EXCEPT, and operators of the same
+// precedence associate to the left, so `a UNION ALL b INTERSECT c UNION ALL d`
+// is `(a UNION ALL (b INTERSECT c)) UNION ALL d`.
+type SetOperationExpr struct {
+	Left        Expr
+	OperatorPos Pos
+	Operator    SetOperator
+	Modifier    SetModifier
+	Right       Expr
+}
+
+func (s *SetOperationExpr) Pos() Pos {
+	return s.Left.Pos()
+}
+
+func (s *SetOperationExpr) End() Pos {
+	return s.Right.End()
+}
+
+// Precedence returns the binding strength of the operator: INTERSECT binds
+// tighter than UNION and EXCEPT.
+func (s *SetOperationExpr) Precedence() int {
+	if s.Operator == SetOperatorIntersect {
+		return 2
 	}
-	if s.Except != nil {
-		if err := s.Except.Accept(visitor); err != nil {
-			return err
-		}
+	return 1
+}
+
+func (s *SetOperationExpr) Accept(visitor ASTVisitor) error {
+	visitor.Enter(s)
+	defer visitor.Leave(s)
+	if err := s.Left.Accept(visitor); err != nil {
+		return err
 	}
-	if s.Intersect != nil {
-		if err := s.Intersect.Accept(visitor); err != nil {
-			return err
-		}
+	if err := s.Right.Accept(visitor); err != nil {
+		return err
 	}
-	return visitor.VisitSelectQuery(s)
+	return visitor.VisitSetOperationExpr(s)
 }
 
 type DistinctOn struct {
@@ -5430,7 +6353,7 @@ func (s *DistinctOn) Accept(visitor ASTVisitor) error {
 
 type SubQuery struct {
 	HasParen bool
-	Select   *SelectQuery
+	Select   Expr // *SelectQuery or *SetOperationExpr
 }
 
 func (s *SubQuery) Pos() Pos {
@@ -6127,6 +7050,33 @@ func (c *ColumnNamesExpr) Accept(visitor ASTVisitor) error {
 	return visitor.VisitColumnNamesExpr(c)
 }
 
+// InsertAllColumnsExpr is the `(*)` or `(* EXCEPT (c1, ...))` column list of an
+// INSERT, naming every column of the table but the excepted ones.
+type InsertAllColumnsExpr struct {
+	LeftParenPos  Pos
+	RightParenPos Pos
+	Except        *ColumnNamesExpr
+}
+
+func (i *InsertAllColumnsExpr) Pos() Pos {
+	return i.LeftParenPos
+}
+
+func (i *InsertAllColumnsExpr) End() Pos {
+	return i.RightParenPos
+}
+
+func (i *InsertAllColumnsExpr) Accept(visitor ASTVisitor) error {
+	visitor.Enter(i)
+	defer visitor.Leave(i)
+	if i.Except != nil {
+		if err := i.Except.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitInsertAllColumnsExpr(i)
+}
+
 type AssignmentValues struct {
 	LeftParenPos  Pos
 	RightParenPos Pos
@@ -6158,8 +7108,13 @@ type InsertStmt struct {
 	HasTableKeyword bool
 	Table           Expr
 	ColumnNames     *ColumnNamesExpr
+	AllColumns      *InsertAllColumnsExpr // `(* EXCEPT (...))` in place of ColumnNames
+	InFile          *StringLiteral        // FROM INFILE file
+	Compression     *StringLiteral        // COMPRESSION of InFile
+	Settings        *SettingsClause
 	Values          []*AssignmentValues
-	SelectExpr      *SelectQuery
+	SelectExpr      Expr         // *SelectQuery or *SetOperationExpr
+	Data            *DataPayload // inline data after FORMAT
 }
 
 func (i *InsertStmt) Pos() Pos {
@@ -6170,6 +7125,9 @@ func (i *InsertStmt) End() Pos {
 	if i.SelectExpr != nil {
 		return i.SelectExpr.End()
 	}
+	if i.Data != nil {
+		return i.Data.End()
+	}
 	if len(i.Values) > 0 {
 		return i.Values[len(i.Values)-1].End()
 	}
@@ -6178,6 +7136,18 @@ func (i *InsertStmt) End() Pos {
 	if i.Format != nil {
 		return i.Format.End()
 	}
+	if i.Settings != nil {
+		return i.Settings.End()
+	}
+	if i.Compression != nil {
+		return i.Compression.End()
+	}
+	if i.InFile != nil {
+		return i.InFile.End()
+	}
+	if i.AllColumns != nil {
+		return i.AllColumns.End()
+	}
 	if i.ColumnNames != nil {
 		return i.ColumnNames.End()
 	}
@@ -6195,6 +7165,26 @@ func (i *InsertStmt) Accept(visitor ASTVisitor) error {
 			return err
 		}
 	}
+	if i.AllColumns != nil {
+		if err := i.AllColumns.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if i.InFile != nil {
+		if err := i.InFile.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if i.Compression != nil {
+		if err := i.Compression.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if i.Settings != nil {
+		if err := i.Settings.Accept(visitor); err != nil {
+			return err
+		}
+	}
 	if i.Format != nil {
 		if err := i.Format.Accept(visitor); err != nil {
 			return err
@@ -6210,9 +7200,61 @@ func (i *InsertStmt) Accept(visitor ASTVisitor) error {
 			return err
 		}
 	}
+	if i.Data != nil {
+		if err := i.Data.Accept(visitor); err != nil {
+			return err
+		}
+	}
 	return visitor.VisitInsertExpr(i)
 }
 
+// DataPayload is the inline data following `INSERT ... FORMAT <name>`. The
+// parser does not interpret it: Data holds the raw text in the named format,
+// and DecodeRows turns the common text formats into Go values.
+type DataPayload struct {
+	DataPos Pos
+	DataEnd Pos
+	Format  string
+	Data    string
+}
+
+func (d *DataPayload) Pos() Pos {
+	return d.DataPos
+}
+
+func (d *DataPayload) End() Pos {
+	return d.DataEnd
+}
+
+func (d *DataPayload) Accept(visitor ASTVisitor) error {
+	visitor.Enter(d)
+	defer visitor.Leave(d)
+	return visitor.VisitDataPayload(d)
+}
+
+// BadStmt is a placeholder for a statement that failed to parse, produced by
+// ParseStmtsWithRecovery. It covers the text that was skipped, up to but not
+// including the `;` that ended it.
+type BadStmt struct {
+	From Pos
+	To   Pos
+	Text string
+}
+
+func (b *BadStmt) Pos() Pos {
+	return b.From
+}
+
+func (b *BadStmt) End() Pos {
+	return b.To
+}
+
+func (b *BadStmt) Accept(visitor ASTVisitor) error {
+	visitor.Enter(b)
+	defer visitor.Leave(b)
+	return visitor.VisitBadStmt(b)
+}
+
 type CheckStmt struct {
 	CheckPos  Pos
 	Table     *TableIdentifier
@@ -6424,13 +7466,102 @@ func (g *GrantPrivilegeStmt) Accept(visitor ASTVisitor) error {
 	return visitor.VisitGrantPrivilegeExpr(g)
 }
 
+// RevokeStmt is a REVOKE statement. It revokes either Privileges on a table
+// or Roles, from the users and roles in From, or from all of them (FromAll)
+// except those in Except.
+type RevokeStmt struct {
+	RevokePos      Pos
+	StatementEnd   Pos
+	OnCluster      *ClusterClause
+	GrantOptionFor bool // REVOKE GRANT OPTION FOR <privileges>
+	AdminOptionFor bool // REVOKE ADMIN OPTION FOR <roles>
+	Privileges     []*PrivilegeClause
+	On             *TableIdentifier
+	Roles          []*Ident
+	From           []*Ident
+	FromAll        bool
+	Except         []*Ident
+}
+
+func (r *RevokeStmt) Pos() Pos {
+	return r.RevokePos
+}
+
+func (r *RevokeStmt) End() Pos {
+	return r.StatementEnd
+}
+
+func (r *RevokeStmt) Type() string {
+	return "REVOKE"
+}
+
+func (r *RevokeStmt) Accept(visitor ASTVisitor) error {
+	visitor.Enter(r)
+	defer visitor.Leave(r)
+	if r.OnCluster != nil {
+		if err := r.OnCluster.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, privilege := range r.Privileges {
+		if err := privilege.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	if r.On != nil {
+		if err := r.On.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, role := range r.Roles {
+		if err := role.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, from := range r.From {
+		if err := from.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, except := range r.Except {
+		if err := except.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	return visitor.VisitRevokeStmt(r)
+}
+
+// ShowType is the kind of object a SHOW statement lists or prints.
+type ShowType string
+
+const (
+	ShowTypeCreateTable      ShowType = "CREATE TABLE"
+	ShowTypeCreateView       ShowType = "CREATE VIEW"
+	ShowTypeCreateDictionary ShowType = "CREATE DICTIONARY"
+	ShowTypeCreateDatabase   ShowType = "CREATE DATABASE"
+	ShowTypeCreateUser       ShowType = "CREATE USER"
+	ShowTypeCreateRole       ShowType = "CREATE ROLE"
+	ShowTypeDatabases        ShowType = "DATABASES"
+	ShowTypeTables           ShowType = "TABLES"
+	ShowTypeColumns          ShowType = "COLUMNS"
+	ShowTypeDictionaries     ShowType = "DICTIONARIES"
+	ShowTypeProcessList      ShowType = "PROCESSLIST"
+	ShowTypeGrants           ShowType = "GRANTS"
+	ShowTypeSettings         ShowType = "SETTINGS"
+	ShowTypeFunctions        ShowType = "FUNCTIONS"
+	ShowTypeIndex            ShowType = "INDEX"
+	ShowTypeEngines          ShowType = "ENGINES"
+)
+
 type ShowStmt struct {
 	ShowPos      Pos
 	StatementEnd Pos
-	ShowType     string           // e.g., "CREATE TABLE", "DATABASES", "TABLES"
-	Target       *TableIdentifier // for SHOW CREATE TABLE table_name
+	ShowType     ShowType
+	Target       *TableIdentifier // for SHOW CREATE TABLE|VIEW|DICTIONARY and the table of SHOW COLUMNS|INDEX
+	Database     *Ident           // for SHOW CREATE DATABASE db and SHOW TABLES|DICTIONARIES FROM db
+	Users        []*RoleName      // for SHOW CREATE USER|ROLE and SHOW GRANTS FOR
 
-	// Optional clauses for SHOW DATABASES
+	// Optional clauses shared by the SHOW statements
 	NotLike     bool           // true if NOT LIKE/ILIKE
 	LikeType    string         // "LIKE" or "ILIKE", empty if not used
 	LikePattern Expr           // pattern expression for LIKE/ILIKE
@@ -6457,6 +7588,12 @@ func (s *ShowStmt) End() Pos {
 	if s.LikePattern != nil {
 		return s.LikePattern.End()
 	}
+	if s.Database != nil {
+		return s.Database.End()
+	}
+	if len(s.Users) > 0 {
+		return s.Users[len(s.Users)-1].End()
+	}
 	if s.Target != nil {
 		return s.Target.End()
 	}
@@ -6471,6 +7608,16 @@ func (s *ShowStmt) Accept(visitor ASTVisitor) error {
 			return err
 		}
 	}
+	if s.Database != nil {
+		if err := s.Database.Accept(visitor); err != nil {
+			return err
+		}
+	}
+	for _, user := range s.Users {
+		if err := user.Accept(visitor); err != nil {
+			return err
+		}
+	}
 	if s.LikePattern != nil {
 		if err := s.LikePattern.Accept(visitor); err != nil {
 			return err
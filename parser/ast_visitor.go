@@ -35,6 +35,14 @@ type ASTVisitor interface {
 	VisitAlterTableReplacePartition(expr *AlterTableReplacePartition) error
 	VisitAlterTableDelete(expr *AlterTableDelete) error
 	VisitAlterTableUpdate(expr *AlterTableUpdate) error
+	VisitAlterTableMovePartition(expr *AlterTableMovePartition) error
+	VisitAlterTableFetchPartition(expr *AlterTableFetchPartition) error
+	VisitAlterTableAddConstraint(expr *AlterTableAddConstraint) error
+	VisitAlterTableDropConstraint(expr *AlterTableDropConstraint) error
+	VisitAlterTableCommentColumn(expr *AlterTableCommentColumn) error
+	VisitAlterTableModifyComment(expr *AlterTableModifyComment) error
+	VisitAlterTableModifySampleBy(expr *AlterTableModifySampleBy) error
+	VisitAlterTableApplyDeletedMask(expr *AlterTableApplyDeletedMask) error
 	VisitUpdateAssignment(expr *UpdateAssignment) error
 	VisitRemovePropertyType(expr *RemovePropertyType) error
 	VisitTableIndex(expr *TableIndex) error
@@ -55,6 +63,17 @@ type ASTVisitor interface {
 	VisitDefaultRoleClause(expr *DefaultRoleClause) error
 	VisitGranteesClause(expr *GranteesClause) error
 	VisitAlterRole(expr *AlterRole) error
+	VisitAlterUser(expr *AlterUser) error
+	VisitToRolesClause(expr *ToRolesClause) error
+	VisitQuotaLimit(expr *QuotaLimit) error
+	VisitCreateQuota(expr *CreateQuota) error
+	VisitAlterQuota(expr *AlterQuota) error
+	VisitRowPolicyName(expr *RowPolicyName) error
+	VisitCreateRowPolicy(expr *CreateRowPolicy) error
+	VisitAlterRowPolicy(expr *AlterRowPolicy) error
+	VisitDropRowPolicy(expr *DropRowPolicy) error
+	VisitCreateSettingsProfile(expr *CreateSettingsProfile) error
+	VisitAlterSettingsProfile(expr *AlterSettingsProfile) error
 	VisitRoleRenamePair(expr *RoleRenamePair) error
 	VisitDestinationExpr(expr *DestinationClause) error
 	VisitConstraintExpr(expr *ConstraintClause) error
@@ -157,6 +176,7 @@ type ASTVisitor interface {
 	VisitWindowFrameNumber(expr *WindowFrameNumber) error
 	VisitWindowFrameParam(expr *WindowFrameParam) error
 	VisitSelectQuery(expr *SelectQuery) error
+	VisitSetOperationExpr(expr *SetOperationExpr) error
 	VisitSubQueryExpr(expr *SubQuery) error
 	VisitNotExpr(expr *NotExpr) error
 	VisitNegateExpr(expr *NegateExpr) error
@@ -183,14 +203,18 @@ type ASTVisitor interface {
 	VisitPlaceHolderExpr(expr *PlaceHolder) error
 	VisitDeleteFromExpr(expr *DeleteClause) error
 	VisitColumnNamesExpr(expr *ColumnNamesExpr) error
+	VisitInsertAllColumnsExpr(expr *InsertAllColumnsExpr) error
 	VisitValuesExpr(expr *AssignmentValues) error
 	VisitInsertExpr(expr *InsertStmt) error
+	VisitDataPayload(expr *DataPayload) error
+	VisitBadStmt(expr *BadStmt) error
 	VisitCheckExpr(expr *CheckStmt) error
 	VisitUnaryExpr(expr *UnaryExpr) error
 	VisitRenameStmt(expr *RenameStmt) error
 	VisitExplainExpr(expr *ExplainStmt) error
 	VisitPrivilegeExpr(expr *PrivilegeClause) error
 	VisitGrantPrivilegeExpr(expr *GrantPrivilegeStmt) error
+	VisitRevokeStmt(expr *RevokeStmt) error
 	VisitShowExpr(expr *ShowStmt) error
 	VisitDescribeExpr(expr *DescribeStmt) error
 	VisitSelectItem(expr *SelectItem) error
@@ -453,6 +477,62 @@ func (v *DefaultASTVisitor) VisitAlterTableUpdate(expr *AlterTableUpdate) error
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitAlterTableMovePartition(expr *AlterTableMovePartition) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableFetchPartition(expr *AlterTableFetchPartition) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableAddConstraint(expr *AlterTableAddConstraint) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableDropConstraint(expr *AlterTableDropConstraint) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableCommentColumn(expr *AlterTableCommentColumn) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableModifyComment(expr *AlterTableModifyComment) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableModifySampleBy(expr *AlterTableModifySampleBy) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterTableApplyDeletedMask(expr *AlterTableApplyDeletedMask) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitUpdateAssignment(expr *UpdateAssignment) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -593,6 +673,83 @@ func (v *DefaultASTVisitor) VisitAlterRole(expr *AlterRole) error {
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitAlterUser(expr *AlterUser) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitToRolesClause(expr *ToRolesClause) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitQuotaLimit(expr *QuotaLimit) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitCreateQuota(expr *CreateQuota) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterQuota(expr *AlterQuota) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitRowPolicyName(expr *RowPolicyName) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitCreateRowPolicy(expr *CreateRowPolicy) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterRowPolicy(expr *AlterRowPolicy) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitDropRowPolicy(expr *DropRowPolicy) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitCreateSettingsProfile(expr *CreateSettingsProfile) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitAlterSettingsProfile(expr *AlterSettingsProfile) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitRoleRenamePair(expr *RoleRenamePair) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -1300,6 +1457,13 @@ func (v *DefaultASTVisitor) VisitSelectQuery(expr *SelectQuery) error {
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitSetOperationExpr(expr *SetOperationExpr) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitSubQueryExpr(expr *SubQuery) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -1482,6 +1646,13 @@ func (v *DefaultASTVisitor) VisitColumnNamesExpr(expr *ColumnNamesExpr) error {
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitInsertAllColumnsExpr(expr *InsertAllColumnsExpr) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitValuesExpr(expr *AssignmentValues) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -1496,6 +1667,20 @@ func (v *DefaultASTVisitor) VisitInsertExpr(expr *InsertStmt) error {
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitDataPayload(expr *DataPayload) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
+func (v *DefaultASTVisitor) VisitBadStmt(expr *BadStmt) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitCheckExpr(expr *CheckStmt) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -1538,6 +1723,13 @@ func (v *DefaultASTVisitor) VisitGrantPrivilegeExpr(expr *GrantPrivilegeStmt) er
 	return nil
 }
 
+func (v *DefaultASTVisitor) VisitRevokeStmt(expr *RevokeStmt) error {
+	if v.Visit != nil {
+		return v.Visit(expr)
+	}
+	return nil
+}
+
 func (v *DefaultASTVisitor) VisitShowExpr(expr *ShowStmt) error {
 	if v.Visit != nil {
 		return v.Visit(expr)
@@ -0,0 +1,63 @@
+package parser
+
+import "reflect"
+
+// Clone returns a deep copy of expr. Every node reachable from expr is copied
+// along with its positions and slices, so the copy can be modified, e.g. by
+// Apply or Transform, without affecting the original. A node referenced from
+// several places in the tree is copied once and stays shared in the copy.
+func Clone(expr Expr) Expr {
+	if isNilExpr(expr) {
+		return expr
+	}
+	c := cloner{copies: map[uintptr]reflect.Value{}}
+	return c.clone(reflect.ValueOf(expr)).Interface().(Expr)
+}
+
+type cloner struct {
+	// copies maps the address of every node already copied to its copy.
+	copies map[uintptr]reflect.Value
+}
+
+func (c *cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if cp, ok := c.copies[v.Pointer()]; ok && cp.Type() == v.Type() {
+			return cp
+		}
+		cp := reflect.New(v.Type().Elem())
+		c.copies[v.Pointer()] = cp
+		cp.Elem().Set(c.clone(v.Elem()))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(c.clone(v.Elem()))
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := cp.Field(i); field.CanSet() {
+				field.Set(c.clone(v.Field(i)))
+			}
+		}
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return cp
+	default:
+		return v
+	}
+}
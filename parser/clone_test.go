@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// astNodeTypes returns the type of every AST node, read off the parameters of
+// the ASTVisitor Visit methods. TestTraversalEnginesCoverSameNodeTypes keeps
+// that interface in sync with the types that have an Accept method.
+func astNodeTypes() []reflect.Type {
+	visitor := reflect.TypeOf((*ASTVisitor)(nil)).Elem()
+	exprType := reflect.TypeOf((*Expr)(nil)).Elem()
+	var types []reflect.Type
+	for i := 0; i < visitor.NumMethod(); i++ {
+		method := visitor.Method(i).Type
+		if method.NumIn() != 1 || method.In(0) == exprType {
+			continue
+		}
+		types = append(types, method.In(0))
+	}
+	return types
+}
+
+// fillNode populates every field of a node with a non-zero value, choosing
+// the first node type that fits for interface fields. Nested nodes are
+// filled up to depth levels deep and left nil below that.
+func fillNode(t *testing.T, v reflect.Value, nodeTypes []reflect.Type, depth int) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fillNode(t, v.Elem(), nodeTypes, depth-1)
+	case reflect.Interface:
+		if depth == 0 {
+			return
+		}
+		for _, typ := range nodeTypes {
+			if typ.Implements(v.Type()) {
+				node := reflect.New(typ.Elem())
+				fillNode(t, node.Elem(), nodeTypes, depth-1)
+				v.Set(node)
+				return
+			}
+		}
+		t.Fatalf("no node type implements %s", v.Type())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			require.True(t, v.Field(i).CanSet(), "%s.%s is unexported", v.Type(), v.Type().Field(i).Name)
+			fillNode(t, v.Field(i), nodeTypes, depth)
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			fillNode(t, v.Index(i), nodeTypes, depth)
+		}
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	default:
+		t.Fatalf("unsupported field kind %s in %s", v.Kind(), v.Type())
+	}
+}
+
+// requireNoSharedMemory asserts that no pointer or slice backing array of
+// the original is reachable from the copy.
+func requireNoSharedMemory(t *testing.T, original, copied reflect.Value, path string) {
+	switch original.Kind() {
+	case reflect.Pointer:
+		if original.IsNil() {
+			return
+		}
+		require.NotEqual(t, original.Pointer(), copied.Pointer(), "%s is shared", path)
+		requireNoSharedMemory(t, original.Elem(), copied.Elem(), path)
+	case reflect.Interface:
+		if original.IsNil() {
+			return
+		}
+		requireNoSharedMemory(t, original.Elem(), copied.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < original.NumField(); i++ {
+			requireNoSharedMemory(t, original.Field(i), copied.Field(i), path+"."+original.Type().Field(i).Name)
+		}
+	case reflect.Slice:
+		if original.Len() == 0 {
+			return
+		}
+		require.NotEqual(t, original.Pointer(), copied.Pointer(), "%s is shared", path)
+		for i := 0; i < original.Len(); i++ {
+			requireNoSharedMemory(t, original.Index(i), copied.Index(i), path+"[]")
+		}
+	}
+}
+
+// TestCloneCoversAllNodeTypes is the Clone counterpart of the traversal drift
+// tests: it populates every field of every AST node type and asserts that
+// Clone reproduces it without sharing any memory with the original, so a new
+// node type or field kind that Clone cannot copy fails here.
+func TestCloneCoversAllNodeTypes(t *testing.T) {
+	nodeTypes := astNodeTypes()
+	require.NotEmpty(t, nodeTypes)
+	for _, typ := range nodeTypes {
+		t.Run(typ.Elem().Name(), func(t *testing.T) {
+			node := reflect.New(typ.Elem())
+			fillNode(t, node.Elem(), nodeTypes, 3)
+			original := node.Interface().(Expr)
+
+			copied := Clone(original)
+			require.IsType(t, original, copied)
+			require.Equal(t, original, copied)
+			requireNoSharedMemory(t, reflect.ValueOf(original), reflect.ValueOf(copied), typ.Elem().Name())
+		})
+	}
+}
+
+func TestCloneTestdata(t *testing.T) {
+	for _, dir := range []string{"./testdata/dml", "./testdata/ddl", "./testdata/query", "./testdata/basic"} {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			t.Run(entry.Name(), func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				stmts, err := NewParser(string(fileBytes)).ParseStmts()
+				require.NoError(t, err)
+				for _, stmt := range stmts {
+					copied := Clone(stmt)
+					require.Equal(t, stmt, copied)
+					require.Equal(t, Format(stmt), Format(copied))
+					requireNoSharedMemory(t, reflect.ValueOf(stmt), reflect.ValueOf(copied), "stmt")
+				}
+			})
+		}
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	stmt := parseSingleStmt(t, `SELECT a FROM events WHERE tenant = 'x' LIMIT 10`)
+	copied := Clone(stmt).(*SelectQuery)
+
+	copied.Limit.Limit = &NumberLiteral{Literal: "100"}
+	Transform(copied, func(node Expr) Expr {
+		if lit, ok := node.(*StringLiteral); ok {
+			return &StringLiteral{Literal: lit.Literal + "_tenant"}
+		}
+		return node
+	})
+
+	require.Equal(t, "SELECT a FROM events WHERE tenant = 'x' LIMIT 10", Format(stmt))
+	require.Equal(t, "SELECT a FROM events WHERE tenant = 'x_tenant' LIMIT 100", Format(copied))
+}
+
+func TestCloneNil(t *testing.T) {
+	require.Nil(t, Clone(nil))
+	var table *TableIdentifier
+	require.Equal(t, Expr(table), Clone(table))
+}
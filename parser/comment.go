@@ -0,0 +1,300 @@
+package parser
+
+import "strings"
+
+// Comment is a -- , # or /* */ comment recorded by a parser created with
+// WithComments.
+type Comment struct {
+	CommentPos Pos
+	CommentEnd Pos
+	// Text is the comment as written, including its markers but not the
+	// line terminator of a line comment, e.g. "-- +goose Up".
+	Text string
+}
+
+func (c *Comment) Pos() Pos {
+	return c.CommentPos
+}
+
+func (c *Comment) End() Pos {
+	return c.CommentEnd
+}
+
+// IsLineComment reports whether c runs to the end of its line, so that
+// nothing may follow it on the same line.
+func (c *Comment) IsLineComment() bool {
+	return strings.HasPrefix(c.Text, "--") || strings.HasPrefix(c.Text, "#")
+}
+
+// NodeComments holds the comments attached to a node.
+type NodeComments struct {
+	// Leading comments precede the node, e.g. a comment on the line above.
+	Leading []*Comment
+	// Trailing comments follow the node on the same line, or follow the last
+	// statement of the input.
+	Trailing []*Comment
+}
+
+// CommentMap maps nodes to the comments attached to them. It is keyed by
+// node pointer, so nodes copied by Clone or created by Transform carry no
+// comments; the Formatter re-emits the comments of the nodes it finds.
+type CommentMap map[Expr]*NodeComments
+
+// WithComments makes the parser record the comments it skips. After
+// ParseStmts, Comments returns them in source order and CommentMap attaches
+// them to the parsed nodes.
+func (p *Parser) WithComments() *Parser {
+	p.lexer.keepComments = true
+	return p
+}
+
+// Comments returns every comment recorded so far, in source order.
+func (p *Parser) Comments() []*Comment {
+	return p.lexer.comments
+}
+
+// CommentMap returns the comments of the last ParseStmts call attached to the
+// statements and nodes they belong to. It is empty unless WithComments was
+// called before parsing.
+func (p *Parser) CommentMap() CommentMap {
+	return p.commentMap
+}
+
+func (m CommentMap) add(node Expr, comment *Comment, trailing bool) {
+	comments := m[node]
+	if comments == nil {
+		comments = &NodeComments{}
+		m[node] = comments
+	}
+	if trailing {
+		comments.Trailing = append(comments.Trailing, comment)
+	} else {
+		comments.Leading = append(comments.Leading, comment)
+	}
+}
+
+// attachComments attaches every comment to the nearest node:
+//   - a comment between statements leads the next statement, unless it
+//     follows the previous one on the same line or no statement follows;
+//   - a comment inside a statement trails the outermost node ending before it
+//     on the same line, and otherwise leads the outermost node starting after
+//     it.
+func attachComments(input string, stmts []Expr, comments []*Comment) CommentMap {
+	cmap := CommentMap{}
+	if len(stmts) == 0 {
+		return cmap
+	}
+	nodes := make([][]Expr, len(stmts))
+	for _, comment := range comments {
+		ownLine := startsLine(input, int(comment.Pos()))
+		next := len(stmts)
+		for i, stmt := range stmts {
+			if stmt.Pos() >= comment.End() {
+				next = i
+				break
+			}
+		}
+		if next > 0 && comment.End() <= stmts[next-1].End() {
+			i := next - 1
+			if nodes[i] == nil {
+				nodes[i] = collectNodes(stmts[i])
+			}
+			node, trailing := nearestNode(input, stmts[i], nodes[i], comment, ownLine)
+			cmap.add(node, comment, trailing)
+			continue
+		}
+		if next > 0 && (!ownLine || next == len(stmts)) {
+			cmap.add(stmts[next-1], comment, true)
+		} else {
+			cmap.add(stmts[next], comment, false)
+		}
+	}
+	return cmap
+}
+
+// collectNodes returns the nodes below stmt in depth-first order.
+func collectNodes(stmt Expr) []Expr {
+	var nodes []Expr
+	Walk(stmt, func(node Expr) bool {
+		if node != stmt && node.Pos() <= node.End() {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// nearestNode returns the node of stmt that comment, which lies within stmt,
+// is attached to and whether it trails that node. Among nodes sharing the
+// same start or end the outermost one wins, as it comes first in nodes.
+func nearestNode(input string, stmt Expr, nodes []Expr, comment *Comment, ownLine bool) (Expr, bool) {
+	var before, after Expr
+	lineStart := Pos(strings.LastIndexAny(input[:comment.Pos()], "\r\n") + 1)
+	for _, node := range nodes {
+		if node.End() <= comment.Pos() {
+			if before == nil || node.End() > before.End() ||
+				node.End() == before.End() && node.Pos() < before.Pos() {
+				before = node
+			}
+		}
+		if node.Pos() >= comment.End() {
+			if after == nil || node.Pos() < after.Pos() ||
+				node.Pos() == after.Pos() && node.End() > after.End() {
+				after = node
+			}
+		}
+	}
+	switch {
+	case before != nil && !ownLine && before.End() > lineStart:
+		return before, true
+	case after != nil:
+		return after, false
+	case before != nil:
+		return before, true
+	}
+	return stmt, true
+}
+
+// startsLine reports whether only whitespace precedes offset on its line.
+func startsLine(input string, offset int) bool {
+	for i := offset - 1; i >= 0; i-- {
+		switch input[i] {
+		case '\n', '\r':
+			return true
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithComments makes the formatter re-emit the comments attached to the nodes
+// it writes: leading comments before the node and trailing comments after
+// it. A line comment always ends its line, so the formatter breaks the line
+// after it even in compact mode.
+func (f *Formatter) WithComments(comments CommentMap) *Formatter {
+	f.comments = comments
+	f.emittedComments = map[*Comment]bool{}
+	return f
+}
+
+func (f *Formatter) writeExprWithComments(expr Expr) {
+	comments := f.comments[expr]
+	f.depth++
+	if comments != nil {
+		for _, comment := range comments.Leading {
+			f.writeLeadingComment(comment)
+		}
+	}
+	expr.FormatSQL(f)
+	if comments != nil {
+		for _, comment := range comments.Trailing {
+			f.writeTrailingComment(comment)
+		}
+	}
+	f.depth--
+	if f.depth > 0 {
+		return
+	}
+	// Nodes that are formatted without WriteExpr never had their comments
+	// written; append those to the statement rather than drop them.
+	Walk(expr, func(node Expr) bool {
+		if comments := f.comments[node]; comments != nil {
+			for _, comment := range comments.Leading {
+				f.writeTrailingComment(comment)
+			}
+			for _, comment := range comments.Trailing {
+				f.writeTrailingComment(comment)
+			}
+		}
+		return true
+	})
+}
+
+func (f *Formatter) writeLeadingComment(comment *Comment) {
+	if f.emittedComments[comment] {
+		return
+	}
+	f.emittedComments[comment] = true
+	f.flushComments()
+	f.writeCommentSeparator()
+	f.writeRaw(comment.Text)
+	if comment.IsLineComment() {
+		f.WriteByte(newline)
+	} else {
+		f.WriteByte(whitespace)
+	}
+}
+
+// writeTrailingComment writes a block comment right away but holds a line
+// comment back until the formatter ends the line or writes a space, so that
+// punctuation following the node, such as a comma, is not commented out.
+func (f *Formatter) writeTrailingComment(comment *Comment) {
+	if f.emittedComments[comment] {
+		return
+	}
+	f.emittedComments[comment] = true
+	if comment.IsLineComment() {
+		f.pendingComments = append(f.pendingComments, comment)
+		return
+	}
+	f.flushComments()
+	f.writeCommentSeparator()
+	f.writeRaw(comment.Text)
+}
+
+// flushComments writes the pending line comments and ends the line.
+func (f *Formatter) flushComments() {
+	if len(f.pendingComments) == 0 {
+		return
+	}
+	comments := f.pendingComments
+	f.pendingComments = nil
+	for i, comment := range comments {
+		if i == 0 {
+			f.writeCommentSeparator()
+		} else {
+			f.WriteByte(newline)
+		}
+		f.writeRaw(comment.Text)
+	}
+	f.WriteByte(newline)
+}
+
+func (f *Formatter) writeCommentSeparator() {
+	s := f.builder.String()
+	if len(s) > 0 && s[len(s)-1] != whitespace && s[len(s)-1] != newline {
+		f.builder.WriteByte(whitespace)
+	}
+}
+
+// writeRaw writes s as is: unlike WriteString it does not indent the lines
+// of a multi-line comment.
+func (f *Formatter) writeRaw(s string) {
+	if f.mode == FormatModeBeautify {
+		f.writeIndentIfNeeded()
+	}
+	f.builder.WriteString(s)
+}
+
+// pendingCommentsString renders the pending line comments the way
+// flushComments would, without the final line break.
+func (f *Formatter) pendingCommentsString() string {
+	var sb strings.Builder
+	s := f.builder.String()
+	for i, comment := range f.pendingComments {
+		if i == 0 {
+			if len(s) > 0 && s[len(s)-1] != whitespace && s[len(s)-1] != newline {
+				sb.WriteByte(whitespace)
+			}
+		} else {
+			sb.WriteByte(newline)
+			if f.mode == FormatModeBeautify {
+				sb.WriteString(strings.Repeat(f.indent, f.indentLevel))
+			}
+		}
+		sb.WriteString(comment.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const migrationSQL = `-- +goose Up
+-- create the table
+CREATE TABLE t (
+  id UInt64, -- the id
+  /* display name */ name String
+) ENGINE = MergeTree ORDER BY id; # keep in sync with the dictionary
+
+SELECT a, -- first
+  b /* second */
+FROM t
+-- only the first row
+WHERE a = 1;
+-- +goose Down
+DROP TABLE t;
+-- end of migration
+`
+
+func formatWithComments(t *testing.T, sql string, beautify bool) []string {
+	parser := NewParser(sql).WithComments()
+	stmts, err := parser.ParseStmts()
+	require.NoError(t, err)
+	var out []string
+	for _, stmt := range stmts {
+		formatter := NewFormatter().WithComments(parser.CommentMap())
+		if beautify {
+			formatter.WithBeautify()
+		}
+		formatter.WriteExpr(stmt)
+		out = append(out, formatter.String())
+	}
+	return out
+}
+
+func commentTexts(comments []*Comment) []string {
+	var texts []string
+	for _, comment := range comments {
+		texts = append(texts, comment.Text)
+	}
+	return texts
+}
+
+func TestComments_Recorded(t *testing.T) {
+	sql := "SELECT 1 -- one\n, /* two */ 2 # three\n#!four\n"
+	parser := NewParser(sql).WithComments()
+	_, err := parser.ParseStmts()
+	require.NoError(t, err)
+
+	comments := parser.Comments()
+	require.Equal(t, []string{"-- one", "/* two */", "# three", "#!four"}, commentTexts(comments))
+	for _, comment := range comments {
+		require.Equal(t, comment.Text, sql[comment.Pos():comment.End()])
+	}
+	require.True(t, comments[0].IsLineComment())
+	require.False(t, comments[1].IsLineComment())
+	require.True(t, comments[2].IsLineComment())
+}
+
+func TestComments_NotRecordedByDefault(t *testing.T) {
+	parser := NewParser("SELECT 1 -- one\n# two\n")
+	stmts, err := parser.ParseStmts()
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	require.Empty(t, parser.Comments())
+	require.Empty(t, parser.CommentMap())
+	require.Equal(t, "SELECT 1", Format(stmts[0]))
+}
+
+func TestComments_Attached(t *testing.T) {
+	parser := NewParser(migrationSQL).WithComments()
+	stmts, err := parser.ParseStmts()
+	require.NoError(t, err)
+	require.Len(t, stmts, 3)
+	cmap := parser.CommentMap()
+
+	require.Equal(t, []string{"-- +goose Up", "-- create the table"}, commentTexts(cmap[stmts[0]].Leading))
+	require.Equal(t, []string{"# keep in sync with the dictionary"}, commentTexts(cmap[stmts[0]].Trailing))
+	require.Equal(t, []string{"-- +goose Down"}, commentTexts(cmap[stmts[2]].Leading))
+	require.Equal(t, []string{"-- end of migration"}, commentTexts(cmap[stmts[2]].Trailing))
+
+	selectQuery := stmts[1].(*SelectQuery)
+	require.Equal(t, []string{"-- first"}, commentTexts(cmap[selectQuery.SelectItems[0]].Trailing))
+	require.Equal(t, []string{"/* second */"}, commentTexts(cmap[selectQuery.SelectItems[1]].Trailing))
+	require.Equal(t, []string{"-- only the first row"}, commentTexts(cmap[selectQuery.Where].Leading))
+}
+
+func TestComments_Format(t *testing.T) {
+	require.Equal(t, []string{
+		"-- +goose Up\n-- create the table\n" +
+			"CREATE TABLE t (id UInt64, -- the id\n/* display name */ name String) ENGINE = MergeTree ORDER BY id # keep in sync with the dictionary",
+		"SELECT a, -- first\nb /* second */ FROM t -- only the first row\nWHERE a = 1",
+		"-- +goose Down\nDROP TABLE t -- end of migration",
+	}, formatWithComments(t, migrationSQL, false))
+
+	require.Equal(t, []string{
+		"-- +goose Up\n-- create the table\n" +
+			"CREATE TABLE t\n(\n  id UInt64, -- the id\n  /* display name */ name String\n)\n" +
+			"ENGINE = MergeTree\nORDER BY\n  id # keep in sync with the dictionary",
+		"SELECT\n  a, -- first\n  b /* second */\nFROM\n  t\n-- only the first row\nWHERE\n  a = 1",
+		"-- +goose Down\nDROP TABLE t -- end of migration",
+	}, formatWithComments(t, migrationSQL, true))
+}
+
+func TestComments_PendingLineComment(t *testing.T) {
+	parser := NewParser("SELECT 1 -- one").WithComments()
+	stmts, err := parser.ParseStmts()
+	require.NoError(t, err)
+
+	formatter := NewFormatter().WithComments(parser.CommentMap())
+	formatter.WriteExpr(stmts[0])
+	require.Equal(t, "SELECT 1 -- one", formatter.String())
+	// punctuation written after the statement goes before the comment
+	formatter.WriteByte(';')
+	require.Equal(t, "SELECT 1; -- one", formatter.String())
+	formatter.WriteString(" SELECT 2")
+	require.Equal(t, "SELECT 1; -- one\nSELECT 2", formatter.String())
+}
+
+// TestComments_Testdata checks that formatting with comments keeps every
+// comment of the testdata and that the output parses back to the same
+// statements and comments.
+func TestComments_Testdata(t *testing.T) {
+	for _, dir := range []string{"./testdata/dml", "./testdata/ddl", "./testdata/query", "./testdata/basic"} {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			t.Run(entry.Name(), func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				parser := NewParser(string(fileBytes)).WithComments()
+				stmts, err := parser.ParseStmts()
+				require.NoError(t, err)
+
+				for _, beautify := range []bool{false, true} {
+					var sb strings.Builder
+					for _, stmt := range stmts {
+						formatter := NewFormatter().WithComments(parser.CommentMap())
+						if beautify {
+							formatter.WithBeautify()
+						}
+						formatter.WriteExpr(stmt)
+						sb.WriteString(formatter.String())
+						sb.WriteString("\n;\n")
+					}
+					reparser := NewParser(sb.String()).WithComments()
+					reparsed, err := reparser.ParseStmts()
+					require.NoError(t, err, sb.String())
+					require.Len(t, reparsed, len(stmts))
+					require.Equal(t, commentTexts(parser.Comments()), commentTexts(reparser.Comments()))
+					for i := range stmts {
+						require.Equal(t, Format(stmts[i]), Format(reparsed[i]))
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DataRow is a row decoded from a DataPayload. Names is set for formats that
+// carry column names (JSONEachRow and the WithNames variants) and is parallel
+// to Values.
+type DataRow struct {
+	Names  []string
+	Values []any
+}
+
+type dataDecoder func(data string) ([]DataRow, error)
+
+// dataDecoders are keyed by the upper-cased format name.
+var dataDecoders = map[string]dataDecoder{
+	"VALUES":                decodeValuesData,
+	"CSV":                   decodeCSVData(false),
+	"CSVWITHNAMES":          decodeCSVData(true),
+	"TSV":                   decodeTSVData(false),
+	"TABSEPARATED":          decodeTSVData(false),
+	"TSVWITHNAMES":          decodeTSVData(true),
+	"TABSEPARATEDWITHNAMES": decodeTSVData(true),
+	"JSONEACHROW":           decodeJSONEachRowData,
+	"JSONLINES":             decodeJSONEachRowData,
+	"NDJSON":                decodeJSONEachRowData,
+}
+
+// DecodeRows decodes the payload of the Values, CSV, TSV (TabSeparated) and
+// JSONEachRow formats, including their WithNames variants, into rows.
+//
+// CSV and TSV fields decode to strings, with \N decoding to nil. JSON values
+// decode as encoding/json does, except that integral numbers become int64.
+// Values literals decode to int64, float64, string, bool, nil, and []any for arrays
+// and tuples; any other expression, such as a function call, is returned as
+// the parsed Expr.
+func (d *DataPayload) DecodeRows() ([]DataRow, error) {
+	decode, ok := dataDecoders[strings.ToUpper(d.Format)]
+	if !ok {
+		return nil, fmt.Errorf("no decoder for format %s", d.Format)
+	}
+	return decode(d.Data)
+}
+
+func decodeValuesData(data string) ([]DataRow, error) {
+	p := NewParser(data)
+	if err := p.lexer.consumeToken(); err != nil {
+		return nil, p.wrapError(err)
+	}
+	var rows []DataRow
+	for p.current() != nil {
+		tuple, err := p.parseAssignmentValues(p.Pos())
+		if err != nil {
+			return nil, p.wrapError(err)
+		}
+		row := DataRow{Values: make([]any, 0, len(tuple.Values))}
+		for _, value := range tuple.Values {
+			row.Values = append(row.Values, literalValue(value))
+		}
+		rows = append(rows, row)
+		if p.tryConsumeTokenKind(TokenKindComma) == nil {
+			break
+		}
+	}
+	if p.current() != nil && !p.matchTokenKind(";") {
+		return nil, p.wrapError(p.unexpectedTokenError())
+	}
+	return rows, nil
+}
+
+// literalValue converts a literal expression to its Go value, returning any
+// other expression unchanged.
+func literalValue(expr Expr) any {
+	switch e := expr.(type) {
+	case *NumberLiteral:
+		base := 10
+		if e.Base == 16 {
+			base = 0 // keeps the 0x prefix
+		}
+		if i, err := strconv.ParseInt(e.Literal, base, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(e.Literal, 64); err == nil {
+			return f
+		}
+	case *StringLiteral:
+		return e.Literal
+	case *BoolLiteral:
+		return strings.EqualFold(e.Literal, "true")
+	case *NullLiteral:
+		return nil
+	case *Ident:
+		// NULL, true and false lex as plain names
+		if e.QuoteType != Unquoted {
+			break
+		}
+		switch strings.ToUpper(e.Name) {
+		case KeywordNull:
+			return nil
+		case KeywordTrue:
+			return true
+		case KeywordFalse:
+			return false
+		}
+	case *UnaryExpr:
+		if e.Kind != TokenKindDash {
+			break
+		}
+		switch v := literalValue(e.Expr).(type) {
+		case int64:
+			return -v
+		case float64:
+			return -v
+		}
+	case *ColumnExpr:
+		if e.Alias == nil {
+			return literalValue(e.Expr)
+		}
+	case *AssignmentValues:
+		return listValues(e.Values)
+	case *ArrayParamList:
+		return listValues(e.Items.Items)
+	}
+	return expr
+}
+
+func listValues(items []Expr) []any {
+	values := make([]any, 0, len(items))
+	for _, item := range items {
+		values = append(values, literalValue(item))
+	}
+	return values
+}
+
+func decodeCSVData(withNames bool) dataDecoder {
+	return func(data string) ([]DataRow, error) {
+		reader := csv.NewReader(strings.NewReader(data))
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		return textRows(records, withNames, func(field string) any {
+			if field == `\N` {
+				return nil
+			}
+			return field
+		}), nil
+	}
+}
+
+func decodeTSVData(withNames bool) dataDecoder {
+	return func(data string) ([]DataRow, error) {
+		var records [][]string
+		for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+			records = append(records, strings.Split(strings.TrimSuffix(line, "\r"), "\t"))
+		}
+		return textRows(records, withNames, unescapeTSVField), nil
+	}
+}
+
+// unescapeTSVField undoes the backslash escapes of the TabSeparated format.
+func unescapeTSVField(field string) any {
+	if field == `\N` {
+		return nil
+	}
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i+1 == len(field) {
+			b.WriteByte(field[i])
+			continue
+		}
+		i++
+		switch field[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '0':
+			b.WriteByte(0)
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String()
+}
+
+func textRows(records [][]string, withNames bool, value func(field string) any) []DataRow {
+	var names []string
+	if withNames && len(records) > 0 {
+		names, records = records[0], records[1:]
+	}
+	rows := make([]DataRow, 0, len(records))
+	for _, record := range records {
+		row := DataRow{Names: names, Values: make([]any, 0, len(record))}
+		for _, field := range record {
+			row.Values = append(row.Values, value(field))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// decodeJSONEachRowData decodes one JSON object per row. The objects may be
+// separated by whitespace or commas, or wrapped in an array.
+func decodeJSONEachRowData(data string) ([]DataRow, error) {
+	data = strings.TrimSpace(data)
+	if strings.HasPrefix(data, "[") && strings.HasSuffix(data, "]") {
+		data = data[1 : len(data)-1]
+	}
+
+	var rows []DataRow
+	for offset := 0; ; {
+		rest := strings.TrimLeft(data[offset:], " \t\r\n,")
+		if rest == "" {
+			return rows, nil
+		}
+		offset = len(data) - len(rest)
+
+		decoder := json.NewDecoder(strings.NewReader(rest))
+		decoder.UseNumber()
+		if token, err := decoder.Token(); err != nil {
+			return nil, err
+		} else if token != json.Delim('{') {
+			return nil, fmt.Errorf("expected a JSON object at offset %d, got %v", offset, token)
+		}
+		var row DataRow
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			var value any
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+			row.Names = append(row.Names, key.(string))
+			row.Values = append(row.Values, jsonValue(value))
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		offset += int(decoder.InputOffset())
+	}
+}
+
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = jsonValue(v[key])
+		}
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseInsertData(t *testing.T, sql string) *DataPayload {
+	t.Helper()
+	insert, ok := parseOneStmt(t, sql).(*InsertStmt)
+	require.True(t, ok)
+	require.NotNil(t, insert.Data)
+	return insert.Data
+}
+
+func TestDataPayloadRange(t *testing.T) {
+	sql := "INSERT INTO t FORMAT CSV\r\n1,2\r\n"
+	data := parseInsertData(t, sql)
+	require.Equal(t, "1,2", data.Data)
+	require.Equal(t, "CSV", data.Format)
+	require.Equal(t, "1,2", sql[data.Pos():data.End()])
+}
+
+func TestParseStmtsResumesAfterDataPayload(t *testing.T) {
+	sql := "INSERT INTO t FORMAT TSV\n1\t'x\nSELECT 1;\n" +
+		"INSERT INTO t FORMAT Values (1, ';'), (2, 'b'); SELECT 2;\n" +
+		"INSERT INTO t FORMAT CSV\n;\nSELECT 3"
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	require.Len(t, stmts, 6)
+	require.Equal(t, "1\t'x", stmts[0].(*InsertStmt).Data.Data)
+	require.Equal(t, "(1, ';'), (2, 'b')", stmts[2].(*InsertStmt).Data.Data)
+	require.Nil(t, stmts[4].(*InsertStmt).Data)
+	require.Equal(t, "SELECT 3", Format(stmts[5]))
+}
+
+func TestInsertFromInfileHasNoDataPayload(t *testing.T) {
+	insert := parseOneStmt(t, "INSERT INTO t FROM INFILE 'a.csv' FORMAT CSV").(*InsertStmt)
+	require.Nil(t, insert.Data)
+}
+
+func TestDataPayloadDecodeRows(t *testing.T) {
+	for _, tc := range []struct {
+		sql  string
+		rows []DataRow
+	}{
+		{
+			"INSERT INTO t FORMAT Values (1, -2.5, 'a', NULL, true, [1, 2], (3, 'b'), now())",
+			[]DataRow{{Values: []any{int64(1), -2.5, "a", nil, true, []any{int64(1), int64(2)}, []any{int64(3), "b"}, &FunctionExpr{}}}},
+		},
+		{
+			"INSERT INTO t FORMAT CSV\n1,\"a,b\",\\N",
+			[]DataRow{{Values: []any{"1", "a,b", nil}}},
+		},
+		{
+			"INSERT INTO t FORMAT CSVWithNames\nid,name",
+			[]DataRow{},
+		},
+		{
+			"INSERT INTO t FORMAT TabSeparated 1\ta\\tb\t\\N",
+			[]DataRow{{Values: []any{"1", "a\tb", nil}}},
+		},
+		{
+			`INSERT INTO t FORMAT JSONEachRow {"id": 1, "v": 0.5}, {"id": 2, "v": [1, "x"]}`,
+			[]DataRow{
+				{Names: []string{"id", "v"}, Values: []any{int64(1), 0.5}},
+				{Names: []string{"id", "v"}, Values: []any{int64(2), []any{int64(1), "x"}}},
+			},
+		},
+	} {
+		rows, err := parseInsertData(t, tc.sql).DecodeRows()
+		require.NoError(t, err, tc.sql)
+		require.Len(t, rows, len(tc.rows), tc.sql)
+		for i, row := range rows {
+			require.Equal(t, tc.rows[i].Names, row.Names, tc.sql)
+			require.Len(t, row.Values, len(tc.rows[i].Values), tc.sql)
+			for j, value := range row.Values {
+				if expr, ok := tc.rows[i].Values[j].(Expr); ok {
+					require.IsType(t, expr, value, tc.sql)
+					continue
+				}
+				require.Equal(t, tc.rows[i].Values[j], value, tc.sql)
+			}
+		}
+	}
+
+	_, err := parseInsertData(t, "INSERT INTO t FORMAT Parquet\nPAR1").DecodeRows()
+	require.ErrorContains(t, err, "no decoder for format Parquet")
+}
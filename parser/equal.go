@@ -0,0 +1,301 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EqualOption configures how Equal and Diff compare two trees.
+type EqualOption func(*equalConfig)
+
+type equalConfig struct {
+	ignorePositions   bool
+	ignoreQuoteStyle  bool
+	ignoreKeywordCase bool
+}
+
+// IgnorePositions makes Equal and Diff skip the Pos-typed fields of every
+// node, such as SelectQuery.SelectPos or Ident.NamePos, so that trees parsed
+// from differently laid out SQL compare equal.
+func IgnorePositions() EqualOption {
+	return func(c *equalConfig) {
+		c.ignorePositions = true
+	}
+}
+
+// IgnoreQuoteStyle makes Equal and Diff skip Ident.QuoteType, so that `a`,
+// "a" and a compare equal.
+func IgnoreQuoteStyle() EqualOption {
+	return func(c *equalConfig) {
+		c.ignoreQuoteStyle = true
+	}
+}
+
+// IgnoreKeywordCase makes Equal and Diff compare keywords recorded as text,
+// such as JoinExpr.Modifiers or DropStmt.DropTarget, case-insensitively.
+// Identifiers and string literals are still compared exactly.
+func IgnoreKeywordCase() EqualOption {
+	return func(c *equalConfig) {
+		c.ignoreKeywordCase = true
+	}
+}
+
+// NodeDiff describes a difference between two trees found by Diff.
+type NodeDiff struct {
+	// Path locates the difference from the roots, e.g.
+	// "SelectQuery.Where.Expr.RightExpr.Literal".
+	Path string
+	// A and B are the differing nodes, or the innermost nodes of each tree
+	// that contain the difference when it is in a field value. One of them is
+	// nil when a node is present in one tree only.
+	A, B Expr
+	// Reason describes the difference, e.g. `"1" != "2"`.
+	Reason string
+}
+
+func (d NodeDiff) String() string {
+	return d.Path + ": " + d.Reason
+}
+
+// Equal reports whether a and b are structurally identical trees: the same
+// node types holding the same values. By default every field is compared,
+// including positions; opts relax the comparison.
+func Equal(a, b Expr, opts ...EqualOption) bool {
+	c := newComparer(opts, true)
+	c.compareNodes(a, b)
+	return len(c.diffs) == 0
+}
+
+// Diff compares a and b like Equal and returns every difference it finds, in
+// depth-first order. It returns nil when the trees are equal.
+func Diff(a, b Expr, opts ...EqualOption) []NodeDiff {
+	c := newComparer(opts, false)
+	c.compareNodes(a, b)
+	return c.diffs
+}
+
+var (
+	exprType  = reflect.TypeOf((*Expr)(nil)).Elem()
+	posType   = reflect.TypeOf(Pos(0))
+	identType = reflect.TypeOf(Ident{})
+)
+
+type comparer struct {
+	config    equalConfig
+	firstOnly bool
+	diffs     []NodeDiff
+	// nodeA and nodeB are the innermost nodes being compared.
+	nodeA, nodeB Expr
+	// keywordText is set while comparing a field of keywordTextFields.
+	keywordText bool
+}
+
+func newComparer(opts []EqualOption, firstOnly bool) *comparer {
+	c := &comparer{firstOnly: firstOnly}
+	for _, opt := range opts {
+		opt(&c.config)
+	}
+	return c
+}
+
+func (c *comparer) compareNodes(a, b Expr) {
+	path := "<nil>"
+	switch {
+	case !isNilExpr(a):
+		path = reflect.TypeOf(a).Elem().Name()
+	case !isNilExpr(b):
+		path = reflect.TypeOf(b).Elem().Name()
+	}
+	c.compare(path, reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+}
+
+func (c *comparer) done() bool {
+	return c.firstOnly && len(c.diffs) > 0
+}
+
+func (c *comparer) report(path, format string, args ...any) {
+	c.diffs = append(c.diffs, NodeDiff{
+		Path:   path,
+		A:      c.nodeA,
+		B:      c.nodeB,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// reportNodes records a difference between the values a and b themselves,
+// which are reported as the differing nodes if they are nodes.
+func (c *comparer) reportNodes(path string, a, b reflect.Value, format string, args ...any) {
+	c.report(path, format, args...)
+	diff := &c.diffs[len(c.diffs)-1]
+	diff.A, diff.B = nodeOrNil(a, c.nodeA), nodeOrNil(b, c.nodeB)
+}
+
+// nodeOrNil returns the node held in v, nil if v holds nothing, and
+// enclosing if v holds a value that is not a node.
+func nodeOrNil(v reflect.Value, enclosing Expr) Expr {
+	if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+		return nil
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if isNilValue(v) {
+		return nil
+	}
+	if node, ok := v.Interface().(Expr); ok {
+		return node
+	}
+	return enclosing
+}
+
+func (c *comparer) compare(path string, a, b reflect.Value) {
+	if c.done() {
+		return
+	}
+	switch a.Kind() {
+	case reflect.Interface:
+		aNil, bNil := a.IsNil() || isNilValue(a.Elem()), b.IsNil() || isNilValue(b.Elem())
+		switch {
+		case aNil && bNil:
+		case aNil:
+			c.reportNodes(path, a, b, "missing in A, %s in B", typeName(b.Elem()))
+		case bNil:
+			c.reportNodes(path, a, b, "%s in A, missing in B", typeName(a.Elem()))
+		case a.Elem().Type() != b.Elem().Type():
+			c.reportNodes(path, a, b, "%s != %s", typeName(a.Elem()), typeName(b.Elem()))
+		default:
+			c.compare(path, a.Elem(), b.Elem())
+		}
+	case reflect.Pointer:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			c.reportNodes(path, a, b, "missing in A, %s in B", typeName(b))
+		case b.IsNil():
+			c.reportNodes(path, a, b, "%s in A, missing in B", typeName(a))
+		default:
+			c.compareNodeValues(path, a, b)
+		}
+	case reflect.Struct:
+		if a.CanAddr() && a.Addr().Type().Implements(exprType) {
+			// Nodes held by value, e.g. EnumType.Values.
+			c.compareNodeValues(path, a.Addr(), b.Addr())
+			return
+		}
+		c.compareFields(path, a, b)
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			c.report(path, "length %d != %d", a.Len(), b.Len())
+		}
+		for i := 0; i < a.Len() && i < b.Len(); i++ {
+			c.compare(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+		}
+	case reflect.String:
+		if a.String() == b.String() {
+			return
+		}
+		if c.config.ignoreKeywordCase && c.keywordText && strings.EqualFold(a.String(), b.String()) {
+			return
+		}
+		c.report(path, "%q != %q", a.String(), b.String())
+	default:
+		if !a.Equal(b) {
+			c.report(path, "%v != %v", a.Interface(), b.Interface())
+		}
+	}
+}
+
+// compareNodeValues compares two non-nil pointers, making them the innermost
+// nodes while their fields are compared if they are nodes.
+func (c *comparer) compareNodeValues(path string, a, b reflect.Value) {
+	nodeA, okA := a.Interface().(Expr)
+	nodeB, okB := b.Interface().(Expr)
+	if okA && okB {
+		savedA, savedB := c.nodeA, c.nodeB
+		c.nodeA, c.nodeB = nodeA, nodeB
+		defer func() {
+			c.nodeA, c.nodeB = savedA, savedB
+		}()
+	}
+	c.compareFields(path, a.Elem(), b.Elem())
+}
+
+func (c *comparer) compareFields(path string, a, b reflect.Value) {
+	typ := a.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if c.config.ignorePositions && field.Type == posType {
+			continue
+		}
+		if c.config.ignoreQuoteStyle && typ == identType && field.Name == "QuoteType" {
+			continue
+		}
+		keywordText := c.keywordText
+		c.keywordText = keywordTextFields[typ.Name()+"."+field.Name]
+		c.compare(path+"."+field.Name, a.Field(i), b.Field(i))
+		c.keywordText = keywordText
+	}
+}
+
+// keywordTextFields are the fields of nodes that record keywords as text,
+// which IgnoreKeywordCase compares case-insensitively. Names and literals,
+// such as Ident.Name, StringLiteral.Literal or EngineExpr.Name, are not among
+// them.
+var keywordTextFields = map[string]bool{
+	"AlterRowPolicy.As":                  true,
+	"AuthenticationClause.AuthType":      true,
+	"BinaryOperation.Operation":          true,
+	"CastExpr.Separator":                 true,
+	"CreateMaterializedView.SQLSecurity": true,
+	"CreateRowPolicy.As":                 true,
+	"DescribeStmt.DescribeType":          true,
+	"DropDatabase.Modifier":              true,
+	"DropStmt.DropTarget":                true,
+	"DropStmt.Modifier":                  true,
+	"DropUserOrRole.Modifier":            true,
+	"DropUserOrRole.Target":              true,
+	"ExplainStmt.Type":                   true,
+	"GrantPrivilegeStmt.WithOptions":     true,
+	"GroupByClause.AggregateType":        true,
+	"HostClause.HostType":                true,
+	"IndexOperation.Operation":           true,
+	"JoinExpr.ConstraintType":            true,
+	"JoinExpr.Kind":                      true,
+	"JoinExpr.Locality":                  true,
+	"JoinExpr.Modifiers":                 true,
+	"JoinExpr.Strictness":                true,
+	"OperationExpr.Kind":                 true,
+	"OrderExpr.Direction":                true,
+	"PrivilegeClause.Keywords":           true,
+	"RefreshExpr.Frequency":              true,
+	"RenameStmt.RenameTarget":            true,
+	"SetOperationExpr.Modifier":          true,
+	"SetOperationExpr.Operator":          true,
+	"SettingPair.Operation":              true,
+	"ShowStmt.LikeType":                  true,
+	"ShowStmt.ShowType":                  true,
+	"SystemCtrlExpr.Command":             true,
+	"SystemCtrlExpr.Type":                true,
+	"SystemDropExpr.Type":                true,
+	"SystemReloadExpr.Type":              true,
+	"TTLPolicyRuleAction.Action":         true,
+	"UnaryExpr.Kind":                     true,
+	"WindowFrameClause.Type":             true,
+	"WindowFrameExtendExpr.Direction":    true,
+	"WindowFrameNumber.Direction":        true,
+	"WindowFrameParam.Direction":         true,
+	"WindowFrameUnbounded.Direction":     true,
+}
+
+// isNilValue reports whether v is a nil pointer.
+func isNilValue(v reflect.Value) bool {
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+func typeName(v reflect.Value) string {
+	return v.Type().String()
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual_Options(t *testing.T) {
+	compact := parseSingleStmt(t, "SELECT a, b FROM t GLOBAL ANY LEFT JOIN u USING id WHERE x IS NOT NULL")
+	spaced := parseSingleStmt(t, "SELECT a,\n  b\nFROM t GLOBAL ANY LEFT JOIN u USING id\nWHERE x IS NOT NULL")
+	quoted := parseSingleStmt(t, "SELECT `a`, \"b\" FROM t GLOBAL ANY LEFT JOIN u USING id WHERE x IS NOT NULL")
+	lower := parseSingleStmt(t, "select a, b from t global any left join u using id where x is not null")
+
+	require.True(t, Equal(compact, compact))
+	require.False(t, Equal(compact, spaced))
+	require.True(t, Equal(compact, spaced, IgnorePositions()))
+
+	require.False(t, Equal(compact, quoted, IgnorePositions()))
+	require.True(t, Equal(compact, quoted, IgnorePositions(), IgnoreQuoteStyle()))
+
+	require.False(t, Equal(compact, lower, IgnorePositions()))
+	require.True(t, Equal(compact, lower, IgnorePositions(), IgnoreKeywordCase()))
+}
+
+func TestEqual_DetectsSemanticChanges(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b string
+	}{
+		{"literal", "SELECT 1", "SELECT 2"},
+		{"identifier case", "SELECT a FROM t", "SELECT A FROM t"},
+		{"string literal case", "SELECT 'a'", "SELECT 'A'"},
+		{"keyword string literal case", "SELECT 'select'", "SELECT 'SELECT'"},
+		{"keyword identifier case", "SELECT `key` FROM t", "SELECT `KEY` FROM t"},
+		{"engine name case", "CREATE TABLE t (a UInt8) ENGINE = Null", "CREATE TABLE t (a UInt8) ENGINE = NULL"},
+		{"operator", "SELECT a + b", "SELECT a - b"},
+		{"column type", "CREATE TABLE t (a UInt8) ENGINE = Memory", "CREATE TABLE t (a UInt16) ENGINE = Memory"},
+		{"extra column", "CREATE TABLE t (a UInt8) ENGINE = Memory", "CREATE TABLE t (a UInt8, b UInt8) ENGINE = Memory"},
+		{"node type", "SELECT a FROM t WHERE x = 1", "SELECT a FROM t WHERE x IN (1)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := parseSingleStmt(t, tc.a), parseSingleStmt(t, tc.b)
+			opts := []EqualOption{IgnorePositions(), IgnoreQuoteStyle(), IgnoreKeywordCase()}
+			require.False(t, Equal(a, b, opts...))
+			require.NotEmpty(t, Diff(a, b, opts...))
+		})
+	}
+}
+
+func TestDiff_Reports(t *testing.T) {
+	a := parseSingleStmt(t, "SELECT a, b FROM t WHERE x = 1")
+	b := parseSingleStmt(t, "SELECT a, c, d FROM t WHERE x = 2")
+
+	diffs := Diff(a, b, IgnorePositions())
+	var lines []string
+	for _, diff := range diffs {
+		lines = append(lines, diff.String())
+	}
+	require.Equal(t, []string{
+		"SelectQuery.SelectItems: length 2 != 3",
+		`SelectQuery.SelectItems[1].Expr.Name: "b" != "c"`,
+		`SelectQuery.Where.Expr.RightExpr.Literal: "1" != "2"`,
+	}, lines)
+
+	// A and B are the innermost nodes holding the difference.
+	require.Equal(t, "b", diffs[1].A.(*Ident).Name)
+	require.Equal(t, "c", diffs[1].B.(*Ident).Name)
+	require.Equal(t, "1", diffs[2].A.(*NumberLiteral).Literal)
+	require.Same(t, a, diffs[0].A)
+	require.Same(t, b, diffs[0].B)
+}
+
+func TestDiff_MissingAndMismatchedNodes(t *testing.T) {
+	a := parseSingleStmt(t, "SELECT a FROM t WHERE x = 1")
+	b := parseSingleStmt(t, "SELECT a FROM t LIMIT 1")
+
+	var lines []string
+	for _, diff := range Diff(a, b, IgnorePositions()) {
+		lines = append(lines, diff.String())
+	}
+	require.Equal(t, []string{
+		"SelectQuery.Where: *parser.WhereClause in A, missing in B",
+		"SelectQuery.Limit: missing in A, *parser.LimitClause in B",
+	}, lines)
+
+	require.Equal(t, []NodeDiff{{
+		Path:   "SelectQuery",
+		A:      a,
+		Reason: "*parser.SelectQuery in A, missing in B",
+	}}, Diff(a, nil))
+	use := &UseStmt{}
+	require.Equal(t, []NodeDiff{{
+		Path:   "SelectQuery",
+		A:      a,
+		B:      use,
+		Reason: "*parser.SelectQuery != *parser.UseStmt",
+	}}, Diff(a, use))
+	require.Same(t, a.(*SelectQuery).Where, Diff(a, b, IgnorePositions())[0].A)
+	require.Nil(t, Diff(a, b, IgnorePositions())[0].B)
+	require.True(t, Equal(nil, nil))
+	require.Nil(t, Diff(a, a))
+}
+
+func TestEqual_CloneOfTestdata(t *testing.T) {
+	for _, dir := range []string{"./testdata/dml", "./testdata/ddl", "./testdata/query", "./testdata/basic"} {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			t.Run(entry.Name(), func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				stmts, err := NewParser(string(fileBytes)).ParseStmts()
+				require.NoError(t, err)
+				for _, stmt := range stmts {
+					require.True(t, Equal(stmt, Clone(stmt)))
+					require.Empty(t, Diff(stmt, Clone(stmt)))
+				}
+			})
+		}
+	}
+}
@@ -5,28 +5,76 @@ import (
 	"strings"
 )
 
+// ErrorCode classifies a ParseError. The codes are stable, so callers can
+// match on them instead of on the rendered message.
+type ErrorCode string
+
+const (
+	// ErrCodeSyntax is a syntax error that fits no narrower code.
+	ErrCodeSyntax ErrorCode = "SYNTAX_ERROR"
+	// ErrCodeUnexpectedToken is a token that cannot appear where it does.
+	ErrCodeUnexpectedToken ErrorCode = "UNEXPECTED_TOKEN"
+	// ErrCodeUnexpectedEOF is input that ends in the middle of a statement.
+	ErrCodeUnexpectedEOF ErrorCode = "UNEXPECTED_EOF"
+	// ErrCodeExpectedToken is a missing token of a kind listed in Expected.
+	ErrCodeExpectedToken ErrorCode = "EXPECTED_TOKEN"
+	// ErrCodeExpectedKeyword is a missing keyword listed in Keywords.
+	ErrCodeExpectedKeyword ErrorCode = "EXPECTED_KEYWORD"
+	// ErrCodeInvalidToken is input that cannot be tokenized, such as an
+	// unclosed string or a stray character.
+	ErrCodeInvalidToken ErrorCode = "INVALID_TOKEN"
+	// ErrCodeInvalidLiteral is a literal with an invalid value, such as a
+	// malformed decimal or an unknown interval unit.
+	ErrCodeInvalidLiteral ErrorCode = "INVALID_LITERAL"
+	// ErrCodeDuplicateClause is a clause that may appear only once.
+	ErrCodeDuplicateClause ErrorCode = "DUPLICATE_CLAUSE"
+	// ErrCodeMissingClause is a required clause or list that is absent or empty.
+	ErrCodeMissingClause ErrorCode = "MISSING_CLAUSE"
+	// ErrCodeInvalidCombination is a valid construct used with another one it
+	// cannot be combined with, such as FINAL on a subquery.
+	ErrCodeInvalidCombination ErrorCode = "INVALID_COMBINATION"
+	// ErrCodeUnknownName is a likely misspelled format or engine name. It is
+	// never returned by parsing, only recorded by Parser.NameHints.
+	ErrCodeUnknownName ErrorCode = "UNKNOWN_NAME"
+)
+
 // ParseError is a structured parse error. It carries the byte offset and the
 // 1-based line/column where parsing stopped, the offending token, and (when
 // known) the tokens the grammar expected at that point. Callers such as
 // editors or linters can inspect these fields programmatically; the CLI relies
 // on Error() to render a human-friendly message with a caret.
 type ParseError struct {
+	Code     ErrorCode   // machine-readable classification of the error
 	Pos      Pos         // byte offset where parsing stopped
 	Line     int         // 1-based line number
 	Column   int         // 1-based column number
 	Got      *Token      // the token we choked on; nil at end of input
 	Expected []TokenKind // token kinds the grammar wanted here, if known
 	Keyword  string      // a specific keyword that was expected, if any
-	Msg      string      // free-form message for the long tail of error sites
+	Keywords []string    // the keywords the grammar wanted here, if known
+	Msg      string      // free-form message for errors the fields above cannot describe
+
+	// Suggestions are the likely intended spellings of a misspelled keyword,
+	// or of a format or engine name in a hint, in alphabetical order.
+	Suggestions []string
 
 	input  string     // original input, for rendering the caret line
 	starts lineStarts // shared line-start offsets, for extracting the offending line
+
+	// lineOffset is the number of lines before the input in a stream, and
+	// columnOffset the number of columns its first line is shifted by; see
+	// StreamParser.
+	lineOffset   int
+	columnOffset int
 }
 
 func (e *ParseError) Error() string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "line %d:%d %s\n", e.Line, e.Column, e.summary())
 	e.renderCaret(&b)
+	if len(e.Suggestions) > 0 {
+		fmt.Fprintf(&b, "hint: did you mean %s?\n", strings.Join(e.Suggestions, " or "))
+	}
 	return b.String()
 }
 
@@ -36,16 +84,21 @@ func (e *ParseError) summary() string {
 	switch {
 	case e.Msg != "":
 		return e.Msg
+	case len(e.Expected)+len(e.Keywords) > 1:
+		parts := make([]string, 0, len(e.Expected)+len(e.Keywords))
+		for _, k := range e.Expected {
+			parts = append(parts, string(k))
+		}
+		parts = append(parts, e.Keywords...)
+		return fmt.Sprintf("expected one of [%s], but got '%s'", strings.Join(parts, ", "), tokenDesc(e.Got))
 	case e.Keyword != "":
 		return fmt.Sprintf("expected keyword <%q>, but got '%s'", e.Keyword, tokenDesc(e.Got))
 	case len(e.Expected) == 1:
 		return fmt.Sprintf("expected '%s', but got '%s'", e.Expected[0], tokenDesc(e.Got))
-	case len(e.Expected) > 1:
-		parts := make([]string, len(e.Expected))
-		for i, k := range e.Expected {
-			parts[i] = string(k)
-		}
-		return fmt.Sprintf("expected one of [%s], but got '%s'", strings.Join(parts, ", "), tokenDesc(e.Got))
+	case e.Got != nil:
+		return fmt.Sprintf("unexpected '%s'", e.Got.String)
+	case e.Code == ErrCodeUnexpectedEOF:
+		return "unexpected end of input"
 	default:
 		return "syntax error"
 	}
@@ -57,7 +110,15 @@ func (e *ParseError) renderCaret(b *strings.Builder) {
 	if e.starts == nil {
 		return
 	}
-	line := e.starts.lineText(e.input, e.Line)
+	line := e.starts.lineText(e.input, e.Line-e.lineOffset)
+	if e.Line == e.lineOffset+1 {
+		// blank out the text before the input on its first line
+		if e.columnOffset >= 0 {
+			line = strings.Repeat(" ", e.columnOffset) + line
+		} else {
+			line = line[min(-e.columnOffset, len(line)):]
+		}
+	}
 	b.WriteString(line)
 	b.WriteByte('\n')
 	for i := 1; i < e.Column; i++ {
@@ -79,3 +140,81 @@ func tokenDesc(t *Token) string {
 	}
 	return string(t.Kind)
 }
+
+// errorCode infers the code of an error built without one from the
+// information it carries.
+func (e *ParseError) errorCode() ErrorCode {
+	switch {
+	case e.Code != "":
+		return e.Code
+	case e.Keyword != "" || len(e.Keywords) > 0:
+		return ErrCodeExpectedKeyword
+	case len(e.Expected) > 0:
+		return ErrCodeExpectedToken
+	case e.Got == nil:
+		return ErrCodeUnexpectedEOF
+	default:
+		return ErrCodeSyntax
+	}
+}
+
+// expectedError reports that the current token is none of the token kinds
+// and keywords.
+func (p *Parser) expectedError(kinds []TokenKind, keywords []string) *ParseError {
+	pe := &ParseError{
+		Code:     ErrCodeExpectedToken,
+		Pos:      p.Pos(),
+		Got:      p.current(),
+		Expected: kinds,
+		Keywords: keywords,
+	}
+	if len(kinds) == 0 {
+		pe.Code = ErrCodeExpectedKeyword
+	}
+	if len(keywords) == 1 {
+		pe.Keyword = keywords[0]
+	}
+	return pe
+}
+
+// expectedKeywordsError reports that the current token is none of keywords.
+func (p *Parser) expectedKeywordsError(keywords ...string) *ParseError {
+	return p.expectedError(nil, keywords)
+}
+
+// expectedTokenError reports that the current token is none of kinds.
+func (p *Parser) expectedTokenError(kinds ...TokenKind) *ParseError {
+	return p.expectedError(kinds, nil)
+}
+
+// unexpectedTokenError reports that the current token cannot appear here.
+func (p *Parser) unexpectedTokenError() *ParseError {
+	code := ErrCodeUnexpectedToken
+	if p.current() == nil {
+		code = ErrCodeUnexpectedEOF
+	}
+	return &ParseError{
+		Code: code,
+		Pos:  p.Pos(),
+		Got:  p.current(),
+	}
+}
+
+// errorAt reports an error at pos with a message.
+func errorAt(pos Pos, code ErrorCode, format string, args ...any) *ParseError {
+	return &ParseError{
+		Code: code,
+		Pos:  pos,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// syntaxError reports an error at the current token with a message.
+func (p *Parser) syntaxError(code ErrorCode, format string, args ...any) *ParseError {
+	return &ParseError{
+		Code: code,
+		Pos:  p.Pos(),
+		Got:  p.current(),
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
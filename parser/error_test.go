@@ -2,6 +2,10 @@ package parser
 
 import (
 	"errors"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -47,3 +51,158 @@ func TestParseError_ExpectedTokenKind(t *testing.T) {
 	require.Equal(t, []TokenKind{TokenKindRParen}, pe.Expected)
 	require.True(t, strings.HasPrefix(pe.Error(), "line "))
 }
+
+// TestParseErrorSitesAreStructured scans the package so that a new error site
+// built with fmt.Errorf or errors.New, which carries no position or code,
+// fails the build. Only code that reports errors about something other than
+// SQL text is exempt.
+func TestParseErrorSitesAreStructured(t *testing.T) {
+	allowed := map[string]bool{
+		"json.go":                               true, // AST JSON decoding
+		"data_payload.go:DecodeRows":            true, // unsupported data formats
+		"data_payload.go:decodeJSONEachRowData": true,
+	}
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || allowed[file] {
+			continue
+		}
+		f, err := goparser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || allowed[file+":"+fn.Name.Name] {
+				continue
+			}
+			ast.Inspect(fn, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				pkg, ok := sel.X.(*ast.Ident)
+				if ok && (pkg.Name == "fmt" && sel.Sel.Name == "Errorf" || pkg.Name == "errors" && sel.Sel.Name == "New") {
+					t.Errorf("%s: %s.%s in %s; return a *ParseError instead",
+						fset.Position(call.Pos()), pkg.Name, sel.Sel.Name, fn.Name.Name)
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestParseError_Codes(t *testing.T) {
+	for _, tc := range []struct {
+		sql  string
+		code ErrorCode
+	}{
+		{"SELECT 'unclosed", ErrCodeInvalidToken},
+		{"SELECT 1 2", ErrCodeExpectedToken},
+		{"SHOW NOTHING", ErrCodeExpectedKeyword},
+		{"FOO BAR", ErrCodeUnexpectedToken},
+		{"SELECT 1 +", ErrCodeUnexpectedEOF},
+		{"SELECT * FROM numbers(10) FINAL", ErrCodeInvalidCombination},
+		{"CREATE DICTIONARY d (id UInt64) PRIMARY KEY id LAYOUT(FLAT()) LIFETIME(0)", ErrCodeMissingClause},
+		{"ALTER TABLE t ADD INDEX i a TYPE minmax GRANULARITY 0x1", ErrCodeInvalidLiteral},
+		{"CREATE DICTIONARY d (id UInt64 DEFAULT 0 DEFAULT 1) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT()) LIFETIME(0)", ErrCodeDuplicateClause},
+	} {
+		_, err := NewParser(tc.sql).ParseStmts()
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe), "%s: expected a *ParseError, got %T", tc.sql, err)
+		require.Equal(t, tc.code, pe.Code, "%s: %s", tc.sql, pe.Error())
+		require.Positive(t, pe.Line, tc.sql)
+		require.Positive(t, pe.Column, tc.sql)
+	}
+}
+
+func TestParseError_KeywordAlternatives(t *testing.T) {
+	_, err := NewParser("SYSTEM NOTHING").ParseStmts()
+	var pe *ParseError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, ErrCodeExpectedKeyword, pe.Code)
+	require.Contains(t, pe.Keywords, KeywordFlush)
+	require.Contains(t, pe.Error(), "expected one of [")
+}
+
+func TestParseError_Suggestions(t *testing.T) {
+	for _, tc := range []struct {
+		sql         string
+		suggestions []string
+	}{
+		{"SELCT 1", []string{KeywordSelect}},
+		{"SELECT a FROM t GRUOP BY a", []string{KeywordGroup}},
+		{"SELECT a FROM t WHERE a = 1 ODRER BY a", []string{KeywordOrder}},
+		{"ALTER TABLE t FETCH PART 'p' FROM '/path'", nil},
+		{"SYSTEM FLUSHH LOGS", []string{KeywordFlush}},
+		{"SELECT a FROM t banana potato", nil},
+	} {
+		_, err := NewParser(tc.sql).ParseStmts()
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe), tc.sql)
+		require.Equal(t, tc.suggestions, pe.Suggestions, "%s: %s", tc.sql, pe.Error())
+	}
+
+	_, err := NewParser("SELECT a FROM t GRUOP BY a").ParseStmts()
+	require.True(t, strings.HasSuffix(err.Error(), "^^\nhint: did you mean GROUP?\n"), err.Error())
+
+	// format and engine names are not checked against a list
+	for _, sql := range []string{
+		"SELECT 1 FORMAT PrettyJSON",
+		"SELECT 1 FORMAT RowBinaryWithDefault",
+		"CREATE TABLE t (a Int32) ENGINE = DeltaLakeS3('url')",
+		"CREATE TABLE t (a Int32) ENGINE = HudiS3('url')",
+		"CREATE TABLE t (a Int32) ENGINE = Loop",
+	} {
+		_, err = NewParser(sql).ParseStmts()
+		require.NoError(t, err, sql)
+	}
+}
+
+func TestParser_NameHints(t *testing.T) {
+	sql := "SELECT 1 FORMAT JSONEachRows;\n" +
+		"CREATE TABLE t (a Int32) ENGINE = MergeTre ORDER BY a;\n" +
+		"SHOW TABLES FORMAT PrettyCompct;\n" +
+		"SELECT 1 FORMAT JSONEachRow;\n" +
+		"CREATE TABLE u (a Int32) ENGINE = Loop"
+	p := NewParser(sql).WithNameHints()
+	_, err := p.ParseStmts()
+	require.NoError(t, err)
+
+	hints := p.NameHints()
+	require.Len(t, hints, 3)
+	for i, expected := range []struct {
+		line        int
+		msg         string
+		suggestions []string
+	}{
+		{1, "unknown format JSONEachRows", []string{"JSONEachRow"}},
+		{2, "unknown engine MergeTre", []string{"MergeTree"}},
+		{3, "unknown format PrettyCompct", []string{"PrettyCompact"}},
+	} {
+		require.Equal(t, ErrCodeUnknownName, hints[i].Code)
+		require.Equal(t, expected.line, hints[i].Line)
+		require.Equal(t, expected.msg, hints[i].Msg)
+		require.Equal(t, expected.suggestions, hints[i].Suggestions)
+	}
+	require.True(t, strings.HasSuffix(hints[0].Error(), "hint: did you mean JSONEachRow?\n"), hints[0].Error())
+
+	// names are not checked unless asked for
+	p = NewParser(sql)
+	_, err = p.ParseStmts()
+	require.NoError(t, err)
+	require.Empty(t, p.NameHints())
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("GROUP", "GROUP"))
+	require.Equal(t, 1, editDistance("GRUOP", "GROUP"))
+	require.Equal(t, 1, editDistance("SELCT", "SELECT"))
+	require.Equal(t, 2, editDistance("CSV", "TSVX"))
+	require.Equal(t, 3, editDistance("", "abc"))
+}
@@ -22,6 +22,13 @@ type Formatter struct {
 	indentLevel int
 	lineStart   bool
 	indent      string
+
+	// comments, set by WithComments, are re-emitted around their nodes.
+	comments        CommentMap
+	emittedComments map[*Comment]bool
+	// pendingComments are trailing line comments waiting for the line to end.
+	pendingComments []*Comment
+	depth           int // nesting of WriteExpr calls while writing comments
 }
 
 func NewFormatter() *Formatter {
@@ -61,6 +68,11 @@ func (f *Formatter) WriteString(s string) {
 }
 
 func (f *Formatter) WriteByte(b byte) {
+	if len(f.pendingComments) > 0 && (b == whitespace || b == newline) {
+		// the line break after the comments replaces the separator
+		f.flushComments()
+		return
+	}
 	if f.mode == FormatModeBeautify {
 		if b == newline {
 			f.builder.WriteByte(newline)
@@ -78,6 +90,10 @@ func (f *Formatter) WriteExpr(expr Expr) {
 	if expr == nil {
 		return
 	}
+	if f.comments != nil {
+		f.writeExprWithComments(expr)
+		return
+	}
 	expr.FormatSQL(f)
 }
 
@@ -107,6 +123,9 @@ func (f *Formatter) Dedent() {
 }
 
 func (f *Formatter) String() string {
+	if len(f.pendingComments) > 0 {
+		return f.builder.String() + f.pendingCommentsString()
+	}
 	return f.builder.String()
 }
 
@@ -179,7 +198,7 @@ func (p *BinaryOperation) FormatSQL(formatter *Formatter) {
 }
 
 func (a *AliasExpr) FormatSQL(formatter *Formatter) {
-	if _, isSelect := a.Expr.(*SelectQuery); isSelect {
+	if isQuery(a.Expr) {
 		formatter.WriteByte('(')
 		formatter.WriteExpr(a.Expr)
 		formatter.WriteByte(')')
@@ -216,6 +235,144 @@ func (a *AlterRole) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (a *AlterQuota) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("ALTER QUOTA ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	for i, pair := range a.RenamePairs {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(pair)
+	}
+	writeQuotaClauses(formatter, a.KeyedBy, a.NotKeyed, a.Limits, a.To)
+}
+
+func (a *AlterRowPolicy) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("ALTER ROW POLICY ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	for i, policy := range a.Policies {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(policy)
+	}
+	if a.As != "" {
+		formatter.Break()
+		formatter.WriteString("AS " + a.As)
+	}
+	if a.ForSelect {
+		formatter.Break()
+		formatter.WriteString("FOR SELECT")
+	}
+	if a.Using != nil {
+		formatter.Break()
+		formatter.WriteString("USING ")
+		formatter.WriteExpr(a.Using)
+	} else if a.UsingNone {
+		formatter.Break()
+		formatter.WriteString("USING NONE")
+	}
+	if a.To != nil {
+		formatter.Break()
+		formatter.WriteExpr(a.To)
+	}
+}
+
+func (a *AlterSettingsProfile) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("ALTER SETTINGS PROFILE ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	for i, pair := range a.RenamePairs {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(pair)
+	}
+	writeRoleSettings(formatter, a.Settings)
+	if a.To != nil {
+		formatter.Break()
+		formatter.WriteExpr(a.To)
+	}
+}
+
+func (a *AlterUser) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("ALTER USER ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	for i, pair := range a.UserRenamePairs {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(pair)
+	}
+	if a.Authentication != nil {
+		formatter.Break()
+		formatter.WriteExpr(a.Authentication)
+	}
+	if a.ValidUntil != nil {
+		formatter.Break()
+		formatter.WriteString("VALID UNTIL ")
+		formatter.WriteExpr(a.ValidUntil)
+	}
+	for _, hosts := range []struct {
+		prefix string
+		hosts  []*HostClause
+	}{{"", a.Hosts}, {"ADD ", a.AddHosts}, {"DROP ", a.DropHosts}} {
+		if len(hosts.hosts) == 0 {
+			continue
+		}
+		formatter.Break()
+		formatter.WriteString(hosts.prefix)
+		for i, host := range hosts.hosts {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(host)
+		}
+	}
+	if a.DefaultRole != nil {
+		formatter.Break()
+		formatter.WriteExpr(a.DefaultRole)
+	}
+	if a.DefaultDatabase != nil {
+		formatter.Break()
+		formatter.WriteString("DEFAULT DATABASE ")
+		formatter.WriteExpr(a.DefaultDatabase)
+	} else if a.DefaultDbNone {
+		formatter.Break()
+		formatter.WriteString("DEFAULT DATABASE NONE")
+	}
+	if a.Grantees != nil {
+		formatter.Break()
+		formatter.WriteExpr(a.Grantees)
+	}
+	writeRoleSettings(formatter, a.Settings)
+}
+
+// writeRoleSettings writes the SETTINGS clause of the access entity statements.
+func writeRoleSettings(formatter *Formatter, settings []*RoleSetting) {
+	if len(settings) == 0 {
+		return
+	}
+	formatter.Break()
+	formatter.WriteString("SETTINGS")
+	formatter.Indent()
+	for i, setting := range settings {
+		if i > 0 {
+			formatter.WriteString(",")
+		}
+		formatter.Break()
+		formatter.WriteExpr(setting)
+	}
+	formatter.Dedent()
+}
+
 func (a *AlterTable) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("ALTER TABLE ")
 	formatter.WriteExpr(a.TableIdentifier)
@@ -241,6 +398,8 @@ func (a *AlterTableAddColumn) FormatSQL(formatter *Formatter) {
 	if a.After != nil {
 		formatter.WriteString(" AFTER ")
 		formatter.WriteExpr(a.After)
+	} else if a.First {
+		formatter.WriteString(" FIRST")
 	}
 	if a.Settings != nil {
 		formatter.Break()
@@ -248,6 +407,20 @@ func (a *AlterTableAddColumn) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (a *AlterTableAddConstraint) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("ADD CONSTRAINT ")
+	if a.IfNotExists {
+		formatter.WriteString("IF NOT EXISTS ")
+	}
+	formatter.WriteExpr(a.Name)
+	if a.Assume {
+		formatter.WriteString(" ASSUME ")
+	} else {
+		formatter.WriteString(" CHECK ")
+	}
+	formatter.WriteExpr(a.Expr)
+}
+
 func (a *AlterTableAddIndex) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("ADD ")
 	if a.IfNotExists {
@@ -272,6 +445,14 @@ func (a *AlterTableAddProjection) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (a *AlterTableApplyDeletedMask) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("APPLY DELETED MASK")
+	if a.InPartition != nil {
+		formatter.WriteString(" IN ")
+		formatter.WriteExpr(a.InPartition)
+	}
+}
+
 func (a *AlterTableAttachPartition) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("ATTACH ")
 	formatter.WriteExpr(a.Partition)
@@ -323,8 +504,23 @@ func (a *AlterTableClearProjection) FormatSQL(formatter *Formatter) {
 
 }
 
+func (a *AlterTableCommentColumn) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("COMMENT COLUMN ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	formatter.WriteExpr(a.ColumnName)
+	formatter.WriteByte(whitespace)
+	formatter.WriteExpr(a.Comment)
+}
+
 func (a *AlterTableDelete) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("DELETE")
+	if a.InPartition != nil {
+		formatter.Break()
+		formatter.WriteString("IN ")
+		formatter.WriteExpr(a.InPartition)
+	}
 	formatter.Break()
 	formatter.WriteString("WHERE ")
 	formatter.WriteExpr(a.WhereClause)
@@ -347,6 +543,14 @@ func (a *AlterTableDropColumn) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(a.ColumnName)
 }
 
+func (a *AlterTableDropConstraint) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("DROP CONSTRAINT ")
+	if a.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	formatter.WriteExpr(a.Name)
+}
+
 func (a *AlterTableDropIndex) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("DROP INDEX ")
 	if a.IfExists {
@@ -375,6 +579,13 @@ func (a *AlterTableDropProjection) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(a.ProjectionName)
 }
 
+func (a *AlterTableFetchPartition) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("FETCH ")
+	formatter.WriteExpr(a.Partition)
+	formatter.WriteString(" FROM ")
+	formatter.WriteExpr(a.From)
+}
+
 func (a *AlterTableFreezePartition) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("FREEZE")
 	if a.Partition != nil {
@@ -424,6 +635,11 @@ func (a *AlterTableModifyColumn) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (a *AlterTableModifyComment) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("MODIFY COMMENT ")
+	formatter.WriteExpr(a.Comment)
+}
+
 func (a *AlterTableModifyQuery) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("MODIFY QUERY")
 	formatter.Indent()
@@ -437,6 +653,11 @@ func (a *AlterTableModifyOrderBy) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(a.OrderBy)
 }
 
+func (a *AlterTableModifySampleBy) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("MODIFY SAMPLE BY ")
+	formatter.WriteExpr(a.SampleBy)
+}
+
 func (a *AlterTableModifySetting) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("MODIFY SETTING")
 	formatter.Indent()
@@ -457,6 +678,22 @@ func (a *AlterTableModifyTTL) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(a.TTL)
 }
 
+func (a *AlterTableMovePartition) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("MOVE ")
+	formatter.WriteExpr(a.Partition)
+	switch {
+	case a.ToDisk != nil:
+		formatter.WriteString(" TO DISK ")
+		formatter.WriteExpr(a.ToDisk)
+	case a.ToVolume != nil:
+		formatter.WriteString(" TO VOLUME ")
+		formatter.WriteExpr(a.ToVolume)
+	default:
+		formatter.WriteString(" TO TABLE ")
+		formatter.WriteExpr(a.ToTable)
+	}
+}
+
 func (a *AlterTableRemoveTTL) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("REMOVE TTL")
 }
@@ -567,6 +804,11 @@ func (a *AuthenticationClause) FormatSQL(formatter *Formatter) {
 	}
 }
 
+// FormatSQL writes the skipped text as it was.
+func (b *BadStmt) FormatSQL(formatter *Formatter) {
+	formatter.WriteString(b.Text)
+}
+
 func (f *BetweenClause) FormatSQL(formatter *Formatter) {
 	keyword := "BETWEEN "
 	if f.Not {
@@ -591,7 +833,7 @@ func (b *BoolLiteral) FormatSQL(formatter *Formatter) {
 func (c *CTEStmt) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(c.Expr)
 	formatter.WriteString(" AS ")
-	if _, isSelect := c.Alias.(*SelectQuery); isSelect {
+	if isQuery(c.Alias) {
 		formatter.WriteByte('(')
 		formatter.WriteExpr(c.Alias)
 		formatter.WriteByte(')')
@@ -681,6 +923,10 @@ func (c *ColumnDef) FormatSQL(formatter *Formatter) {
 		formatter.WriteString(" ALIAS ")
 		formatter.WriteExpr(c.AliasExpr)
 	}
+	if c.Comment != nil {
+		formatter.WriteString(" COMMENT ")
+		formatter.WriteExpr(c.Comment)
+	}
 	if c.Codec != nil {
 		formatter.WriteByte(whitespace)
 		formatter.WriteExpr(c.Codec)
@@ -689,10 +935,6 @@ func (c *ColumnDef) FormatSQL(formatter *Formatter) {
 		formatter.WriteByte(whitespace)
 		formatter.WriteExpr(c.TTL)
 	}
-	if c.Comment != nil {
-		formatter.WriteString(" COMMENT ")
-		formatter.WriteExpr(c.Comment)
-	}
 }
 
 func (c *ColumnExpr) FormatSQL(formatter *Formatter) {
@@ -767,8 +1009,13 @@ func (c *CompressionCodec) FormatSQL(formatter *Formatter) {
 }
 
 func (c *ConstraintClause) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("CONSTRAINT ")
 	formatter.WriteExpr(c.Constraint)
-	formatter.WriteByte(whitespace)
+	if c.Assume {
+		formatter.WriteString(" ASSUME ")
+	} else {
+		formatter.WriteString(" CHECK ")
+	}
 	formatter.WriteExpr(c.Expr)
 }
 
@@ -1023,6 +1270,117 @@ func (c *CreateRole) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (c *CreateQuota) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("CREATE QUOTA ")
+	if c.IfNotExists {
+		formatter.WriteString("IF NOT EXISTS ")
+	}
+	if c.OrReplace {
+		formatter.WriteString("OR REPLACE ")
+	}
+	for i, name := range c.Names {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(name)
+	}
+	if c.AccessStorageType != nil {
+		formatter.WriteString(" IN ")
+		formatter.WriteExpr(c.AccessStorageType)
+	}
+	writeQuotaClauses(formatter, c.KeyedBy, c.NotKeyed, c.Limits, c.To)
+}
+
+// writeQuotaClauses writes the clauses shared by CREATE QUOTA and ALTER QUOTA.
+func writeQuotaClauses(formatter *Formatter, keyedBy []*Ident, notKeyed bool, limits []*QuotaLimit, to *ToRolesClause) {
+	if len(keyedBy) > 0 {
+		formatter.Break()
+		formatter.WriteString("KEYED BY ")
+		for i, key := range keyedBy {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(key)
+		}
+	} else if notKeyed {
+		formatter.Break()
+		formatter.WriteString("NOT KEYED")
+	}
+	for i, limit := range limits {
+		if i > 0 {
+			formatter.WriteByte(',')
+		}
+		formatter.Break()
+		formatter.WriteExpr(limit)
+	}
+	if to != nil {
+		formatter.Break()
+		formatter.WriteExpr(to)
+	}
+}
+
+func (c *CreateRowPolicy) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("CREATE ROW POLICY ")
+	if c.IfNotExists {
+		formatter.WriteString("IF NOT EXISTS ")
+	}
+	if c.OrReplace {
+		formatter.WriteString("OR REPLACE ")
+	}
+	for i, policy := range c.Policies {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(policy)
+	}
+	if c.AccessStorageType != nil {
+		formatter.WriteString(" IN ")
+		formatter.WriteExpr(c.AccessStorageType)
+	}
+	if c.ForSelect {
+		formatter.Break()
+		formatter.WriteString("FOR SELECT")
+	}
+	if c.Using != nil {
+		formatter.Break()
+		formatter.WriteString("USING ")
+		formatter.WriteExpr(c.Using)
+	}
+	if c.As != "" {
+		formatter.Break()
+		formatter.WriteString("AS " + c.As)
+	}
+	if c.To != nil {
+		formatter.Break()
+		formatter.WriteExpr(c.To)
+	}
+}
+
+func (c *CreateSettingsProfile) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("CREATE SETTINGS PROFILE ")
+	if c.IfNotExists {
+		formatter.WriteString("IF NOT EXISTS ")
+	}
+	if c.OrReplace {
+		formatter.WriteString("OR REPLACE ")
+	}
+	for i, name := range c.Names {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(name)
+	}
+	if c.AccessStorageType != nil {
+		formatter.WriteString(" IN ")
+		formatter.WriteExpr(c.AccessStorageType)
+	}
+	writeRoleSettings(formatter, c.Settings)
+	if c.To != nil {
+		formatter.Break()
+		formatter.WriteExpr(c.To)
+	}
+}
+
 func (c *CreateTable) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("CREATE")
 	if c.OrReplace {
@@ -1169,6 +1527,14 @@ func (c *CreateView) FormatSQL(formatter *Formatter) {
 	}
 }
 
+// FormatSQL writes the data on a line of its own and ends the line, which is
+// what delimits the data when the statement is parsed again.
+func (d *DataPayload) FormatSQL(formatter *Formatter) {
+	formatter.WriteByte(newline)
+	formatter.WriteString(d.Data)
+	formatter.WriteByte(newline)
+}
+
 func (d *DeduplicateClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString(" DEDUPLICATE")
 	if d.By != nil {
@@ -1408,6 +1774,27 @@ func (d *DropDatabase) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (d *DropRowPolicy) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("DROP ROW POLICY ")
+	if d.IfExists {
+		formatter.WriteString("IF EXISTS ")
+	}
+	for i, policy := range d.Policies {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(policy)
+	}
+	if d.OnCluster != nil {
+		formatter.WriteByte(whitespace)
+		formatter.WriteExpr(d.OnCluster)
+	}
+	if d.From != nil {
+		formatter.WriteString(" FROM ")
+		formatter.WriteExpr(d.From)
+	}
+}
+
 func (d *DropStmt) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("DROP ")
 	if d.IsTemporary {
@@ -1586,6 +1973,57 @@ func (g *GrantPrivilegeStmt) FormatSQL(formatter *Formatter) {
 
 }
 
+func (r *RevokeStmt) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("REVOKE ")
+	if r.OnCluster != nil {
+		formatter.WriteExpr(r.OnCluster)
+		formatter.WriteByte(whitespace)
+	}
+	if r.AdminOptionFor {
+		formatter.WriteString("ADMIN OPTION FOR ")
+	}
+	if r.GrantOptionFor {
+		formatter.WriteString("GRANT OPTION FOR ")
+	}
+	if len(r.Privileges) > 0 {
+		for i, privilege := range r.Privileges {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(privilege)
+		}
+		formatter.WriteString(" ON ")
+		formatter.WriteExpr(r.On)
+	} else {
+		for i, role := range r.Roles {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(role)
+		}
+	}
+	formatter.WriteString(" FROM ")
+	if r.FromAll {
+		formatter.WriteString("ALL")
+		if len(r.Except) > 0 {
+			formatter.WriteString(" EXCEPT ")
+			for i, except := range r.Except {
+				if i > 0 {
+					formatter.WriteString(", ")
+				}
+				formatter.WriteExpr(except)
+			}
+		}
+		return
+	}
+	for i, from := range r.From {
+		if i > 0 {
+			formatter.WriteString(", ")
+		}
+		formatter.WriteExpr(from)
+	}
+}
+
 func (g *GranteesClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("GRANTEES ")
 	if g.Any {
@@ -1691,11 +2129,29 @@ func (i *InsertStmt) FormatSQL(formatter *Formatter) {
 		formatter.Indent()
 		formatter.WriteExpr(i.ColumnNames)
 		formatter.Dedent()
+	} else if i.AllColumns != nil {
+		formatter.WriteByte(whitespace)
+		formatter.WriteExpr(i.AllColumns)
+	}
+	if i.InFile != nil {
+		formatter.WriteString(" FROM INFILE ")
+		formatter.WriteExpr(i.InFile)
+		if i.Compression != nil {
+			formatter.WriteString(" COMPRESSION ")
+			formatter.WriteExpr(i.Compression)
+		}
+	}
+	if i.Settings != nil {
+		formatter.Break()
+		formatter.WriteExpr(i.Settings)
 	}
 	if i.Format != nil {
 		formatter.WriteByte(whitespace)
 		formatter.WriteExpr(i.Format)
 	}
+	if i.Data != nil {
+		formatter.WriteExpr(i.Data)
+	}
 
 	if i.SelectExpr != nil {
 		formatter.Break()
@@ -1715,6 +2171,15 @@ func (i *InsertStmt) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (i *InsertAllColumnsExpr) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("(*")
+	if i.Except != nil {
+		formatter.WriteString(" EXCEPT ")
+		formatter.WriteExpr(i.Except)
+	}
+	formatter.WriteByte(')')
+}
+
 func (i *InterpolateClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("INTERPOLATE")
 	if len(i.Items) > 0 {
@@ -1880,12 +2345,12 @@ func writeJoinSQL(formatter *Formatter, expr Expr) {
 		return
 	}
 
-	if len(joinExpr.Modifiers) == 0 {
+	if joinExpr.isCommaJoin() {
 		formatter.WriteByte(',')
 		formatter.WriteExpr(joinExpr.Left)
 	} else {
 		formatter.Break()
-		formatter.WriteString(strings.Join(joinExpr.Modifiers, " "))
+		formatter.WriteString(strings.Join(joinExpr.joinKeywords(), " "))
 		formatter.Indent()
 		formatter.Break()
 		formatter.WriteExpr(joinExpr.Left)
@@ -1900,6 +2365,44 @@ func writeJoinSQL(formatter *Formatter, expr Expr) {
 	}
 }
 
+// isCommaJoin reports whether the table follows a comma rather than a JOIN.
+// Trees built before the typed fields existed mark a comma only by leaving
+// Modifiers empty.
+func (j *JoinExpr) isCommaJoin() bool {
+	if j.Kind == JoinKindComma {
+		return true
+	}
+	return len(j.Modifiers) == 0 && j.Locality == JoinLocalityNone && j.Strictness == JoinStrictnessNone &&
+		j.Kind == JoinKindNone && !j.HasOuter && !j.IsArray
+}
+
+// joinKeywords returns the keywords of the join in canonical order:
+// locality, strictness, kind, OUTER, ARRAY, JOIN. A tree that only sets
+// Modifiers gets them back as written.
+func (j *JoinExpr) joinKeywords() []string {
+	if j.Locality == JoinLocalityNone && j.Strictness == JoinStrictnessNone && j.Kind == JoinKindNone &&
+		!j.HasOuter && !j.IsArray && len(j.Modifiers) > 1 {
+		return j.Modifiers
+	}
+	keywords := make([]string, 0, 6)
+	if j.Locality != JoinLocalityNone {
+		keywords = append(keywords, string(j.Locality))
+	}
+	if j.Strictness != JoinStrictnessNone {
+		keywords = append(keywords, string(j.Strictness))
+	}
+	if j.Kind != JoinKindNone {
+		keywords = append(keywords, string(j.Kind))
+	}
+	if j.HasOuter {
+		keywords = append(keywords, KeywordOuter)
+	}
+	if j.IsArray {
+		keywords = append(keywords, KeywordArray)
+	}
+	return append(keywords, KeywordJoin)
+}
+
 func (j *JoinTableExpr) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(j.Table)
 	if j.SampleRatio != nil {
@@ -2099,6 +2602,7 @@ func (p *PartitionByClause) FormatSQL(formatter *Formatter) {
 func (p *PartitionClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("PARTITION ")
 	if p.ID != nil {
+		formatter.WriteString("ID ")
 		formatter.WriteExpr(p.ID)
 	} else if p.All {
 		formatter.WriteString("ALL")
@@ -2178,6 +2682,31 @@ func (q *QueryParam) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("}")
 }
 
+func (q *QuotaLimit) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("FOR ")
+	if q.Randomized {
+		formatter.WriteString("RANDOMIZED ")
+	}
+	formatter.WriteString("INTERVAL ")
+	formatter.WriteExpr(q.Interval)
+	formatter.WriteByte(whitespace)
+	formatter.WriteExpr(q.Unit)
+	switch {
+	case q.NoLimits:
+		formatter.WriteString(" NO LIMITS")
+	case q.TrackingOnly:
+		formatter.WriteString(" TRACKING ONLY")
+	default:
+		formatter.WriteString(" MAX ")
+		for i, limit := range q.Limits {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(limit)
+		}
+	}
+}
+
 func (r *RatioExpr) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(r.Numerator)
 	if r.Denominator != nil {
@@ -2255,6 +2784,22 @@ func (r *RoleSetting) FormatSQL(formatter *Formatter) {
 	}
 }
 
+func (r *RowPolicyName) FormatSQL(formatter *Formatter) {
+	formatter.WriteExpr(r.Name)
+	if r.OnCluster != nil {
+		formatter.WriteByte(whitespace)
+		formatter.WriteExpr(r.OnCluster)
+	}
+	if r.On != nil {
+		formatter.WriteString(" ON ")
+		formatter.WriteExpr(r.On)
+	}
+	if r.RenameTo != nil {
+		formatter.WriteString(" RENAME TO ")
+		formatter.WriteExpr(r.RenameTo)
+	}
+}
+
 func (s *SampleByClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("SAMPLE BY ")
 	formatter.WriteExpr(s.Expr)
@@ -2368,27 +2913,32 @@ func (s *SelectQuery) FormatSQL(formatter *Formatter) {
 		formatter.Break()
 		formatter.WriteExpr(s.Format)
 	}
-	if s.UnionAll != nil {
-		formatter.Break()
-		formatter.WriteString("UNION ALL")
-		formatter.Break()
-		formatter.WriteExpr(s.UnionAll)
-	} else if s.UnionDistinct != nil {
-		formatter.Break()
-		formatter.WriteString("UNION DISTINCT")
-		formatter.Break()
-		formatter.WriteExpr(s.UnionDistinct)
-	} else if s.Except != nil {
-		formatter.Break()
-		formatter.WriteString("EXCEPT")
-		formatter.Break()
-		formatter.WriteExpr(s.Except)
-	} else if s.Intersect != nil {
-		formatter.Break()
-		formatter.WriteString("INTERSECT")
-		formatter.Break()
-		formatter.WriteExpr(s.Intersect)
+}
+
+func (s *SetOperationExpr) FormatSQL(formatter *Formatter) {
+	s.writeOperand(formatter, s.Left, false)
+	formatter.Break()
+	formatter.WriteString(string(s.Operator))
+	if s.Modifier != SetModifierNone {
+		formatter.WriteByte(whitespace)
+		formatter.WriteString(string(s.Modifier))
 	}
+	formatter.Break()
+	s.writeOperand(formatter, s.Right, true)
+}
+
+// writeOperand parenthesizes an operand that would otherwise bind to its
+// neighbours differently, which only happens for trees built or rewritten in
+// code: the parser keeps the parentheses of the input as a SubQuery.
+func (s *SetOperationExpr) writeOperand(formatter *Formatter, operand Expr, isRight bool) {
+	child, ok := operand.(*SetOperationExpr)
+	if !ok || child.Precedence() > s.Precedence() || (!isRight && child.Precedence() == s.Precedence()) {
+		formatter.WriteExpr(operand)
+		return
+	}
+	formatter.WriteByte('(')
+	formatter.WriteExpr(operand)
+	formatter.WriteByte(')')
 }
 
 func (s *SetStmt) FormatSQL(formatter *Formatter) {
@@ -2436,13 +2986,38 @@ func (s *SettingsClause) FormatSQL(formatter *Formatter) {
 
 func (s *ShowStmt) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("SHOW ")
-	formatter.WriteString(s.ShowType)
+	formatter.WriteString(string(s.ShowType))
 	if s.Target != nil {
-		formatter.WriteByte(whitespace)
+		if s.ShowType == ShowTypeColumns || s.ShowType == ShowTypeIndex {
+			formatter.WriteString(" FROM ")
+		} else {
+			formatter.WriteByte(whitespace)
+		}
 		formatter.WriteExpr(s.Target)
 	}
+	if s.Database != nil {
+		if s.ShowType == ShowTypeCreateDatabase {
+			formatter.WriteByte(whitespace)
+		} else {
+			formatter.WriteString(" FROM ")
+		}
+		formatter.WriteExpr(s.Database)
+	}
+	if len(s.Users) > 0 {
+		if s.ShowType == ShowTypeGrants {
+			formatter.WriteString(" FOR ")
+		} else {
+			formatter.WriteByte(whitespace)
+		}
+		for i, user := range s.Users {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(user)
+		}
+	}
 
-	// Add optional clauses for SHOW DATABASES
+	// Add optional clauses shared by the SHOW statements
 	if s.LikeType != "" && s.LikePattern != nil {
 		if s.NotLike {
 			formatter.WriteString(" NOT ")
@@ -2693,6 +3268,32 @@ func (t *TernaryOperation) FormatSQL(formatter *Formatter) {
 	formatter.WriteExpr(t.FalseExpr)
 }
 
+func (t *ToRolesClause) FormatSQL(formatter *Formatter) {
+	formatter.WriteString("TO ")
+	switch {
+	case t.None:
+		formatter.WriteString("NONE")
+	case t.All:
+		formatter.WriteString("ALL")
+		if len(t.Except) > 0 {
+			formatter.WriteString(" EXCEPT ")
+			for i, except := range t.Except {
+				if i > 0 {
+					formatter.WriteString(", ")
+				}
+				formatter.WriteExpr(except)
+			}
+		}
+	default:
+		for i, role := range t.Roles {
+			if i > 0 {
+				formatter.WriteString(", ")
+			}
+			formatter.WriteExpr(role)
+		}
+	}
+}
+
 func (t *TopClause) FormatSQL(formatter *Formatter) {
 	formatter.WriteString("TOP ")
 	formatter.WriteString(t.Number.Literal)
@@ -71,3 +71,21 @@ func TestFormatter_DefaultIndent(t *testing.T) {
 	formatter := NewFormatter()
 	require.Equal(t, "  ", formatter.indent)
 }
+
+func TestFormatConstraintsAndColumnComments(t *testing.T) {
+	// CHECK and ASSUME constraints keep their keyword, and a column COMMENT
+	// comes before CODEC and TTL, where ClickHouse expects it
+	sql := "CREATE TABLE t (a UInt8 COMMENT 'a' CODEC(ZSTD(1)) TTL d + INTERVAL 1 DAY, d Date, " +
+		"CONSTRAINT c1 CHECK a > 0, CONSTRAINT c2 ASSUME d > '2020-01-01') ENGINE = MergeTree ORDER BY d"
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	require.Equal(t, sql, Format(stmts[0]))
+
+	constraints := FindAll(stmts[0], func(node Expr) bool {
+		_, ok := node.(*ConstraintClause)
+		return ok
+	})
+	require.Len(t, constraints, 2)
+	require.False(t, constraints[0].(*ConstraintClause).Assume)
+	require.True(t, constraints[1].(*ConstraintClause).Assume)
+}
@@ -1,5 +1,11 @@
 package parser
 
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
 func IsDigit(c byte) bool {
 	return '0' <= c && c <= '9'
 }
@@ -15,3 +21,44 @@ func IsIdentStart(c byte) bool {
 func IsIdentPart(c byte) bool {
 	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$'
 }
+
+// IsIdentStartRune reports whether r may start an unquoted identifier: an
+// ASCII or Unicode letter, or an underscore.
+func IsIdentStartRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return IsIdentStart(byte(r))
+	}
+	return unicode.IsLetter(r)
+}
+
+// IsIdentPartRune reports whether r may continue an unquoted identifier: any
+// identifier start, a digit, a dollar sign, or a combining mark, which some
+// scripts need to spell a letter.
+func IsIdentPartRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return IsIdentPart(byte(r))
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)
+}
+
+// quoteEscaper escapes text to be written between single quotes.
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// isQuery reports whether expr is a bare query that needs parentheses when
+// embedded in another expression.
+func isQuery(expr Expr) bool {
+	switch expr.(type) {
+	case *SelectQuery, *SetOperationExpr:
+		return true
+	}
+	return false
+}
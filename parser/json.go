@@ -0,0 +1,259 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ASTJSONVersion is the version of the JSON schema written by MarshalJSON.
+// It is incremented whenever a node type or field is renamed or removed, or
+// the meaning of a field changes; adding node types or fields keeps it.
+const ASTJSONVersion = 1
+
+// MarshalJSON encodes the tree rooted at expr as JSON that UnmarshalJSON can
+// decode back into an identical tree. The result is an object holding the
+// schema version and the root node:
+//
+//	{"version": 1, "node": {"type": "SelectQuery", "SelectPos": 0, ...}}
+//
+// Every node is an object whose "type" member is its Go type name, followed
+// by its fields under their Go names. Helper structs that are not nodes, such
+// as a MapLiteral's KeyValue, are objects without a "type" member. Nil nodes
+// and slices are null, so that they are told apart from empty slices.
+func MarshalJSON(expr Expr) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"version":`)
+	buf.WriteString(strconv.Itoa(ASTJSONVersion))
+	buf.WriteString(`,"node":`)
+	if err := encodeJSONValue(&buf, reflect.ValueOf(&expr).Elem()); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes JSON written by MarshalJSON back into a tree. It
+// rejects documents of a newer schema version, unknown node types and
+// fields, and nodes whose type does not fit the field holding them.
+func UnmarshalJSON(data []byte) (Expr, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var version int
+	if raw, ok := doc["version"]; !ok {
+		return nil, fmt.Errorf("missing AST JSON version")
+	} else if err := json.Unmarshal(raw, &version); err != nil {
+		return nil, fmt.Errorf("version: %w", err)
+	}
+	if version < 1 || version > ASTJSONVersion {
+		return nil, fmt.Errorf("unsupported AST JSON version %d, expected at most %d", version, ASTJSONVersion)
+	}
+	node, ok := doc["node"]
+	if !ok {
+		return nil, fmt.Errorf("missing AST JSON node")
+	}
+	var expr Expr
+	if err := decodeJSONValue(node, reflect.ValueOf(&expr).Elem(), "node"); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// nodeTypesByName maps the JSON type name of every node to its pointer type,
+// read off the parameters of the ASTVisitor Visit methods.
+var nodeTypesByName = func() map[string]reflect.Type {
+	visitor := reflect.TypeOf((*ASTVisitor)(nil)).Elem()
+	types := map[string]reflect.Type{}
+	for i := 0; i < visitor.NumMethod(); i++ {
+		method := visitor.Method(i).Type
+		if method.NumIn() != 1 || method.In(0) == exprType {
+			continue
+		}
+		types[method.In(0).Elem().Name()] = method.In(0)
+	}
+	return types
+}()
+
+// isNodeType reports whether a pointer to typ is a node.
+func isNodeType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && reflect.PointerTo(typ).Implements(exprType)
+}
+
+func encodeJSONValue(buf *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return encodeJSONValue(buf, v.Elem())
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		if isNodeType(v.Type()) {
+			buf.WriteString(`"type":`)
+			writeJSONString(buf, v.Type().Name())
+			first = false
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			writeJSONString(buf, field.Name)
+			buf.WriteByte(':')
+			if err := encodeJSONValue(buf, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSONValue(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		return nil
+	default:
+		return fmt.Errorf("cannot encode %s as AST JSON", v.Type())
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s) // nolint: errcheck, a string always marshals
+	buf.Write(data)
+}
+
+// decodeJSONValue decodes data into v, which must be settable. path locates v
+// in the document for error messages.
+func decodeJSONValue(data json.RawMessage, v reflect.Value, path string) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Struct:
+		// Decode objects into a map rather than a struct: encoding/json
+		// matches struct fields case-insensitively, which would confuse
+		// "type" with a node's Type field.
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return decodeJSONObject(members, v, path)
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeJSONValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	default:
+		if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// decodeJSONObject decodes the members of a JSON object into v, which is a
+// struct, a pointer to one or an interface holding a node.
+func decodeJSONObject(members map[string]json.RawMessage, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		typ, err := jsonNodeType(members, path)
+		if err != nil {
+			return err
+		}
+		if !typ.Implements(v.Type()) {
+			return fmt.Errorf("%s: node type %s cannot be used as %s", path, typ.Elem().Name(), v.Type())
+		}
+		node := reflect.New(typ.Elem())
+		if err := decodeJSONObject(members, node.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(node)
+		return nil
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeJSONObject(members, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	typ := v.Type()
+	isNode := isNodeType(typ)
+	if isNode {
+		nodeType, err := jsonNodeType(members, path)
+		if err != nil {
+			return err
+		}
+		if nodeType.Elem() != typ {
+			return fmt.Errorf("%s: node type %s cannot be used as %s", path, nodeType.Elem().Name(), typ.Name())
+		}
+	}
+	for name, member := range members {
+		if isNode && name == "type" {
+			continue
+		}
+		field, ok := typ.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return fmt.Errorf("%s: unknown field %q of %s", path, name, typ.Name())
+		}
+		if err := decodeJSONValue(member, v.FieldByIndex(field.Index), path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonNodeType returns the node type named by the "type" member of an object.
+func jsonNodeType(members map[string]json.RawMessage, path string) (reflect.Type, error) {
+	tag, ok := members["type"]
+	if !ok {
+		return nil, fmt.Errorf("%s: missing node type", path)
+	}
+	var name string
+	if err := json.Unmarshal(tag, &name); err != nil {
+		return nil, fmt.Errorf("%s.type: %w", path, err)
+	}
+	typ, ok := nodeTypesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown node type %q", path, name)
+	}
+	return typ, nil
+}
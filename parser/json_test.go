@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSONCoversAllNodeTypes populates every field of every AST node type and
+// asserts that it survives a MarshalJSON/UnmarshalJSON round trip, so a new
+// node type or field kind the encoding cannot represent fails here.
+func TestJSONCoversAllNodeTypes(t *testing.T) {
+	nodeTypes := astNodeTypes()
+	require.Len(t, nodeTypesByName, len(nodeTypes), "node type names must be unique")
+	for _, typ := range nodeTypes {
+		t.Run(typ.Elem().Name(), func(t *testing.T) {
+			node := reflect.New(typ.Elem())
+			fillNode(t, node.Elem(), nodeTypes, 3)
+			original := node.Interface().(Expr)
+
+			data, err := MarshalJSON(original)
+			require.NoError(t, err)
+			decoded, err := UnmarshalJSON(data)
+			require.NoError(t, err)
+			require.Equal(t, original, decoded)
+		})
+	}
+}
+
+func TestJSONRoundTripTestdata(t *testing.T) {
+	for _, dir := range []string{"./testdata/dml", "./testdata/ddl", "./testdata/query", "./testdata/basic"} {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".sql") {
+				continue
+			}
+			t.Run(entry.Name(), func(t *testing.T) {
+				fileBytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoError(t, err)
+				stmts, err := NewParser(string(fileBytes)).ParseStmts()
+				require.NoError(t, err)
+				for _, stmt := range stmts {
+					data, err := MarshalJSON(stmt)
+					require.NoError(t, err)
+					require.True(t, json.Valid(data))
+					decoded, err := UnmarshalJSON(data)
+					require.NoError(t, err)
+					require.Empty(t, Diff(stmt, decoded))
+					require.Equal(t, Format(stmt), Format(decoded))
+				}
+			})
+		}
+	}
+}
+
+func TestMarshalJSON_TypeTags(t *testing.T) {
+	stmt := parseSingleStmt(t, "SELECT t.a FROM t")
+	data, err := MarshalJSON(stmt)
+	require.NoError(t, err)
+
+	var doc struct {
+		Version int `json:"version"`
+		Node    struct {
+			Type        string `json:"type"`
+			SelectItems []struct {
+				Expr struct {
+					Type   string `json:"type"`
+					Fields []map[string]any
+				}
+			}
+			Where any
+		} `json:"node"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, ASTJSONVersion, doc.Version)
+	require.Equal(t, "SelectQuery", doc.Node.Type)
+	require.Equal(t, "Path", doc.Node.SelectItems[0].Expr.Type)
+	require.Equal(t, "Ident", doc.Node.SelectItems[0].Expr.Fields[0]["type"])
+	require.Equal(t, "t", doc.Node.SelectItems[0].Expr.Fields[0]["Name"])
+	require.Nil(t, doc.Node.Where)
+}
+
+func TestUnmarshalJSON_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"no version", `{"node": null}`, "missing AST JSON version"},
+		{"newer version", `{"version": 99, "node": null}`, "unsupported AST JSON version 99, expected at most 1"},
+		{"no node", `{"version": 1}`, "missing AST JSON node"},
+		{"no type", `{"version": 1, "node": {"Name": "a"}}`, "node: missing node type"},
+		{"unknown type", `{"version": 1, "node": {"type": "Foo"}}`, `node: unknown node type "Foo"`},
+		{"unknown field", `{"version": 1, "node": {"type": "Ident", "Foo": 1}}`, `node: unknown field "Foo" of Ident`},
+		{
+			"type mismatch",
+			`{"version": 1, "node": {"type": "TableIdentifier", "Table": {"type": "NumberLiteral"}}}`,
+			"node.Table: node type NumberLiteral cannot be used as Ident",
+		},
+		{
+			"interface mismatch",
+			`{"version": 1, "node": {"type": "AlterTable", "AlterExprs": [{"type": "Ident"}]}}`,
+			"node.AlterExprs[0]: node type Ident cannot be used as parser.AlterTableClause",
+		},
+		{"bad value", `{"version": 1, "node": {"type": "Ident", "Name": 1}}`, "node.Name: json: cannot unmarshal number"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalJSON([]byte(tc.json))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestJSONNilRoot(t *testing.T) {
+	data, err := MarshalJSON(nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version": 1, "node": null}`, string(data))
+	decoded, err := UnmarshalJSON(data)
+	require.NoError(t, err)
+	require.Nil(t, decoded)
+}
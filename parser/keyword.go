@@ -590,3 +590,58 @@ var keywords = NewSet(
 	KeywordSQL,
 	KeywordSecurity,
 )
+
+// Context keywords are keywords only where the grammar expects them, such as
+// DELETED in APPLY DELETED MASK. They are not in the keywords set, so the
+// lexer reads them as identifiers and they stay usable as names, including
+// implicit aliases (`FROM t mask`); matchKeyword compares their text where the
+// grammar asks for them.
+const (
+	KeywordAssume      = "ASSUME"
+	KeywordCompression = "COMPRESSION"
+	KeywordDeleted     = "DELETED"
+	KeywordEngines     = "ENGINES"
+	KeywordFetch       = "FETCH"
+	KeywordGrants      = "GRANTS"
+	KeywordIndexes     = "INDEXES"
+	KeywordIndices     = "INDICES"
+	KeywordInfile      = "INFILE"
+	KeywordKeyed       = "KEYED"
+	KeywordKeys        = "KEYS"
+	KeywordLimits      = "LIMITS"
+	KeywordMask        = "MASK"
+	KeywordOnly        = "ONLY"
+	KeywordPaste       = "PASTE"
+	KeywordPermissive  = "PERMISSIVE"
+	KeywordProcesslist = "PROCESSLIST"
+	KeywordProfile     = "PROFILE"
+	KeywordRandomized  = "RANDOMIZED"
+	KeywordRestrictive = "RESTRICTIVE"
+	KeywordRevoke      = "REVOKE"
+	KeywordTracking    = "TRACKING"
+)
+
+var contextKeywords = NewSet(
+	KeywordAssume,
+	KeywordCompression,
+	KeywordDeleted,
+	KeywordEngines,
+	KeywordFetch,
+	KeywordGrants,
+	KeywordIndexes,
+	KeywordIndices,
+	KeywordInfile,
+	KeywordKeyed,
+	KeywordKeys,
+	KeywordLimits,
+	KeywordMask,
+	KeywordOnly,
+	KeywordPaste,
+	KeywordPermissive,
+	KeywordProcesslist,
+	KeywordProfile,
+	KeywordRandomized,
+	KeywordRestrictive,
+	KeywordRevoke,
+	KeywordTracking,
+)
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,6 +60,32 @@ func TestNonReservedKeywordAsIdentifier(t *testing.T) {
 	}
 }
 
+// TestContextKeywordAsIdentifier asserts that the context keywords, which
+// are keywords only inside the clauses that use them, stay usable as names,
+// in particular as implicit table aliases.
+func TestContextKeywordAsIdentifier(t *testing.T) {
+	for _, word := range contextKeywords.Members() {
+		word = strings.ToLower(word)
+		for _, sql := range []string{
+			"SELECT a FROM t " + word,
+			"SELECT a FROM t " + word + " WHERE " + word + ".a = 1",
+			"SELECT a FROM t " + word + " LEFT JOIN u ON " + word + ".a = u.a",
+			"SELECT " + word + " FROM t",
+			"CREATE TABLE t (" + word + " String) ENGINE=Memory",
+		} {
+			_, err := NewParser(sql).ParseStmts()
+			require.NoError(t, err, sql)
+		}
+	}
+
+	// PASTE right before JOIN is the join kind, so it needs AS as an alias there
+	stmts, err := NewParser("SELECT a FROM t paste PASTE JOIN u").ParseStmts()
+	require.NoError(t, err)
+	join := stmts[0].(*SelectQuery).From.Expr.(*JoinExpr)
+	require.Equal(t, "paste", join.Left.(*JoinTableExpr).Table.Expr.(*AliasExpr).Alias.(*Ident).Name)
+	require.Equal(t, JoinKindPaste, join.Right.(*JoinExpr).Kind)
+}
+
 // TestCaseExprRequiresEnd asserts that END, which is non-reserved so that
 // columns can be named `end`, still terminates a CASE expression: a CASE
 // without it must fail instead of running into the next clause.
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
@@ -77,12 +76,18 @@ func (t *Token) ToString() string {
 type lexerState struct {
 	offset       int    // byte offset into input of the next unread character
 	currentToken *Token // current lookahead token; nil at end of input
+	prevToken    *Token // last token read before currentToken; nil at the start of input
+	err          error  // why currentToken is nil before the end of input, if it is
 }
 
 type Lexer struct {
 	lexerState
 
 	input string
+
+	// keepComments makes the lexer record the comments it skips in comments.
+	keepComments bool
+	comments     []*Comment
 }
 
 func NewLexer(buf string) *Lexer {
@@ -113,6 +118,15 @@ func (l *Lexer) peekOk(n int) bool {
 	return l.offset+n < len(l.input)
 }
 
+// peekRune decodes the UTF-8 rune n bytes ahead and returns it with its
+// size in bytes. The size is 0 at end of input.
+func (l *Lexer) peekRune(n int) (rune, int) {
+	if !l.peekOk(n) {
+		return utf8.RuneError, 0
+	}
+	return utf8.DecodeRuneInString(l.input[l.offset+n:])
+}
+
 func (l *Lexer) isKeyword(ident string) bool {
 	return keywords.Contains(ident)
 }
@@ -151,7 +165,7 @@ func (l *Lexer) consumeNumber() error {
 			// a second dot ("1.2.3"), a dot after the exponent ("1e2.3") or
 			// a dot in a hex literal ("0x1.8") cannot start a valid number tail
 			if hasDot || hasExp || base == 16 {
-				return errors.New("invalid number")
+				return l.tokenError(l.offset, "invalid number")
 			}
 			hasDot = true
 			tokenKind = TokenKindFloat
@@ -159,14 +173,14 @@ func (l *Lexer) consumeNumber() error {
 			continue
 		case base != 16 && (c == 'e' || c == 'E'):
 			if hasExp {
-				return errors.New("invalid number")
+				return l.tokenError(l.offset, "invalid number")
 			}
 			i++
 			if l.peekOk(i) && (l.peekN(i) == '+' || l.peekN(i) == '-') {
 				i++
 			}
 			if !l.peekOk(i) || !IsDigit(l.peekN(i)) {
-				return errors.New("exponent part should contain at least one digit")
+				return l.tokenError(l.offset, "exponent part should contain at least one digit")
 			}
 			hasExp = true
 			// scientific notation always denotes a floating-point value
@@ -175,8 +189,8 @@ func (l *Lexer) consumeNumber() error {
 		}
 		break
 	}
-	if (l.peekOk(i) && IsIdentPart(l.peekN(i))) || !hasNumberPart {
-		return errors.New("invalid number")
+	if r, size := l.peekRune(i); (size > 0 && IsIdentPartRune(r)) || !hasNumberPart {
+		return l.tokenError(l.offset, "invalid number")
 	}
 	l.currentToken = &Token{
 		Kind:   tokenKind,
@@ -206,8 +220,12 @@ func (l *Lexer) consumeIdent(_ Pos) error {
 		if l.peekOk(i) && l.peekN(i) == '$' {
 			i++
 		}
-		for l.peekOk(i) && IsIdentPart(l.peekN(i)) {
-			i++
+		for {
+			r, size := l.peekRune(i)
+			if size == 0 || !IsIdentPartRune(r) {
+				break
+			}
+			i += size
 		}
 	} else {
 		for l.peekOk(i) && (quoteType == BackTicks && l.peekN(i) != '`' ||
@@ -216,11 +234,13 @@ func (l *Lexer) consumeIdent(_ Pos) error {
 		}
 		if !l.peekOk(i) || (quoteType == BackTicks && l.peekN(i) != '`') ||
 			(quoteType == DoubleQuote && l.peekN(i) != '"') {
-			return fmt.Errorf("unclosed quoted identifier: %s", l.slice(0, i))
+			return l.tokenError(l.offset-1, "unclosed quoted identifier: %s", l.slice(0, i))
 		}
 	}
 	slice := l.slice(0, i)
-	if quoteType == Unquoted && l.isKeyword(strings.ToUpper(slice)) {
+	// Keywords are ASCII; checking only ASCII identifiers also keeps letters
+	// such as the dotless ı, which upper-cases to I, from forming one.
+	if quoteType == Unquoted && isASCII(slice) && l.isKeyword(strings.ToUpper(slice)) {
 		token.Kind = TokenKindKeyword
 	} else {
 		token.Kind = TokenKindIdent
@@ -239,11 +259,13 @@ func (l *Lexer) consumeIdent(_ Pos) error {
 }
 
 func (l *Lexer) consumeSingleLineComment() {
+	start := l.offset
 	l.skipN(2)
 	i := 0
 	for l.peekOk(i) && l.peekN(i) != '\r' && l.peekN(i) != '\n' {
 		i++
 	}
+	l.recordComment(start, l.offset+i)
 	if l.peekOk(i) {
 		// consume the newline too; at EOF there is none to consume
 		i++
@@ -252,17 +274,36 @@ func (l *Lexer) consumeSingleLineComment() {
 }
 
 func (l *Lexer) consumeMultiLineComment() error {
+	start := l.offset
 	l.skipN(2)
 	i := 0
 	for l.peekOk(i) {
 		if l.peekOk(i+1) && l.peekN(i) == '*' && l.peekN(i+1) == '/' {
 			l.skipN(i + 2)
+			l.recordComment(start, l.offset)
 			return nil
 		}
 		i++
 	}
 	l.skipN(i)
-	return errors.New("unclosed multi-line comment")
+	return l.tokenError(start, "unclosed multi-line comment")
+}
+
+// recordComment records the comment input[start:end] if comments are kept.
+// Peeking and backtracking lex the same comments again, so a comment that
+// does not come after the last recorded one has been recorded already.
+func (l *Lexer) recordComment(start, end int) {
+	if !l.keepComments {
+		return
+	}
+	if n := len(l.comments); n > 0 && l.comments[n-1].CommentPos >= Pos(start) {
+		return
+	}
+	l.comments = append(l.comments, &Comment{
+		CommentPos: Pos(start),
+		CommentEnd: Pos(end),
+		Text:       l.input[start:end],
+	})
 }
 
 func (l *Lexer) consumeString() error {
@@ -290,7 +331,7 @@ func (l *Lexer) consumeString() error {
 		i++
 	}
 	if !l.peekOk(i) || l.peekN(i) != endChar {
-		return errors.New("invalid string")
+		return l.tokenError(l.offset, "invalid string")
 	}
 	l.currentToken = &Token{
 		Kind:   TokenKindString,
@@ -302,6 +343,38 @@ func (l *Lexer) consumeString() error {
 	return nil
 }
 
+// heredocDelimiter returns the `$tag$` (or `$$`) that opens a heredoc string
+// at the current offset, or "" if there is none.
+func (l *Lexer) heredocDelimiter() string {
+	i := 1
+	for l.peekOk(i) && l.peekN(i) < utf8.RuneSelf && l.peekN(i) != '$' && IsIdentPart(l.peekN(i)) {
+		i++
+	}
+	if !l.peekOk(i) || l.peekN(i) != '$' {
+		return ""
+	}
+	return l.slice(0, i+1)
+}
+
+// consumeHeredoc consumes a heredoc string such as $$it's$$ or
+// $tag$it's$tag$, which holds its text as is, up to the closing delimiter.
+// The token holds the text escaped, as a quoted string token does.
+func (l *Lexer) consumeHeredoc(delimiter string) error {
+	body := l.input[l.offset+len(delimiter):]
+	i := strings.Index(body, delimiter)
+	if i < 0 {
+		return l.tokenError(l.offset, "unclosed heredoc %s", delimiter)
+	}
+	l.currentToken = &Token{
+		Kind:   TokenKindString,
+		String: quoteEscaper.Replace(body[:i]),
+		Pos:    Pos(l.offset + len(delimiter)),
+		End:    Pos(l.offset + len(delimiter) + i),
+	}
+	l.skipN(len(delimiter)*2 + i)
+	return nil
+}
+
 func (l *Lexer) skipComments() error {
 	for !l.isEOF() {
 		l.skipSpace()
@@ -315,6 +388,13 @@ func (l *Lexer) skipComments() error {
 				continue
 			}
 			return nil
+		case '#':
+			// ClickHouse only treats "# " and "#!" as comments
+			if l.peekOk(1) && (l.peekN(1) == ' ' || l.peekN(1) == '!') {
+				l.consumeSingleLineComment()
+				continue
+			}
+			return nil
 		case '/': // multi-line comment
 			if l.peekOk(1) && l.peekN(1) == '*' {
 				if err := l.consumeMultiLineComment(); err != nil {
@@ -356,9 +436,21 @@ func (l *Lexer) hasPrecedenceToken(last *Token) bool {
 		last.Kind == TokenKindRBracket)
 }
 
+// consumeToken replaces the current token with the next one. Callers that
+// know a token is there ignore the error, so it is also kept in err for the
+// parser to report in place of an unexpected end of input.
 func (l *Lexer) consumeToken() error {
+	l.err = l.nextToken()
+	return l.err
+}
+
+func (l *Lexer) nextToken() error {
 	// replace the current token; keep the previous one to disambiguate unary +/-
 	prevToken := l.currentToken
+	if prevToken != nil {
+		// reading past the end of the input keeps the last token
+		l.prevToken = prevToken
+	}
 	l.currentToken = nil
 	if err := l.skipComments(); err != nil {
 		return err
@@ -399,7 +491,12 @@ func (l *Lexer) consumeToken() error {
 		}
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		return l.consumeNumber()
-	case '`', '$', '"':
+	case '$':
+		if delimiter := l.heredocDelimiter(); delimiter != "" {
+			return l.consumeHeredoc(delimiter)
+		}
+		return l.consumeIdent(Pos(l.offset))
+	case '`', '"':
 		return l.consumeIdent(Pos(l.offset))
 	case '\'':
 		return l.consumeString()
@@ -425,16 +522,16 @@ func (l *Lexer) consumeToken() error {
 		return nil
 	}
 
-	if IsIdentStart(l.peekN(0)) {
+	r, _ := l.peekRune(0)
+	if IsIdentStartRune(r) {
 		return l.consumeIdent(Pos(l.offset))
 	}
 
-	// Non-ASCII bytes can only appear inside quoted identifiers or string
-	// literals, which are handled above. Report the whole rune instead of
-	// emitting a one-byte token that splits the UTF-8 sequence.
-	if l.peekN(0) >= utf8.RuneSelf {
-		r, _ := utf8.DecodeRuneInString(l.input[l.offset:])
-		return fmt.Errorf("unexpected character %q", r)
+	// Other non-ASCII characters can only appear inside quoted identifiers
+	// or string literals, which are handled above. Report the whole rune
+	// instead of emitting a one-byte token that splits the UTF-8 sequence.
+	if r >= utf8.RuneSelf {
+		return l.tokenError(l.offset, "unexpected character %q", r)
 	}
 
 	token := &Token{}
@@ -447,6 +544,15 @@ func (l *Lexer) consumeToken() error {
 	return nil
 }
 
+// tokenError reports input starting at offset that cannot be tokenized.
+func (l *Lexer) tokenError(offset int, format string, args ...any) *ParseError {
+	return &ParseError{
+		Code: ErrCodeInvalidToken,
+		Pos:  Pos(offset),
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
 func (l *Lexer) isEOF() bool {
 	return l.offset >= len(l.input)
 }
@@ -326,19 +326,83 @@ func TestSingleLineCommentAtEOF(t *testing.T) {
 	require.LessOrEqual(t, lexer.offset, len(lexer.input))
 }
 
-// TestNonASCIIByteIsAnError guards that a bare multi-byte character produces
-// a readable error naming the whole rune instead of a garbage one-byte token
-// that splits the UTF-8 sequence.
+// TestHashComment guards that "# " and "#!" start a line comment, as in
+// ClickHouse, whether or not comments are recorded, while any other # is
+// still an unexpected character.
+func TestHashComment(t *testing.T) {
+	for _, sql := range []string{"SELECT 1 # one\n", "SELECT 1 #! one", "# one\nSELECT 1"} {
+		stmts, err := NewParser(sql).ParseStmts()
+		require.NoError(t, err, sql)
+		require.Len(t, stmts, 1, sql)
+		require.Equal(t, "SELECT 1", Format(stmts[0]), sql)
+	}
+
+	_, err := NewParser("SELECT a#b FROM t").ParseStmts()
+	require.Error(t, err)
+}
+
+// TestNonASCIIByteIsAnError guards that a bare multi-byte character that is
+// not part of an identifier produces a readable error naming the whole rune
+// instead of a garbage one-byte token that splits the UTF-8 sequence.
 func TestNonASCIIByteIsAnError(t *testing.T) {
-	err := lexAll("SELECT 中文")
+	err := lexAll("SELECT ☃")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'☃'")
+
+	err = lexAll("SELECT a\xff")
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "'中'")
 
-	// quoted identifiers and string literals may still carry non-ASCII text
-	_, err = NewParser("SELECT `中文`, '中文'").ParseStmts()
+	// quoted identifiers and string literals may still carry any text
+	_, err = NewParser("SELECT `☃`, '☃'").ParseStmts()
 	require.NoError(t, err)
 }
 
+func TestUnicodeIdentifiers(t *testing.T) {
+	sql := "SELECT count() AS 合计, имя FROM пользователи WHERE возраст > 18"
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	require.Equal(t, sql, Format(stmts[0]))
+
+	var idents []*Ident
+	Walk(stmts[0], func(node Expr) bool {
+		if ident, ok := node.(*Ident); ok {
+			idents = append(idents, ident)
+		}
+		return true
+	})
+	names := map[string]bool{}
+	for _, ident := range idents {
+		names[ident.Name] = true
+		// positions are byte offsets into the input
+		require.Equal(t, ident.Name, sql[ident.Pos():ident.End()])
+	}
+	require.True(t, names["合计"])
+	require.True(t, names["пользователи"])
+
+	// digits, underscores and combining marks may follow the first letter
+	for _, name := range []string{"столбец_2", "मूल्य", "données١"} {
+		lexer := NewLexer(name)
+		require.NoError(t, lexer.consumeToken())
+		require.Equal(t, TokenKindIdent, lexer.currentToken.Kind)
+		require.Equal(t, name, lexer.currentToken.String)
+		require.True(t, lexer.isEOF())
+	}
+
+	// non-ASCII letters never spell a keyword, even when they upper-case to one
+	lexer := NewLexer("lımıt")
+	require.NoError(t, lexer.consumeToken())
+	require.Equal(t, TokenKindIdent, lexer.currentToken.Kind)
+
+	// a number may not run into a letter
+	require.Error(t, lexAll("SELECT 1я"))
+
+	// the byte predicates stay ASCII-only
+	require.True(t, IsIdentStartRune('я'))
+	require.True(t, IsIdentPartRune('١'))
+	require.False(t, IsIdentStart("я"[0]))
+	require.False(t, IsIdentPart("я"[1]))
+}
+
 // TestNegativeHexLiteral guards the sign handling in consumeNumber: the 0x
 // check previously peeked at the sign instead of the first digit.
 func TestNegativeHexLiteral(t *testing.T) {
@@ -346,3 +410,34 @@ func TestNegativeHexLiteral(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, stmts, 1)
 }
+
+func TestConsumeHeredoc(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`$$hello$$`, `hello`},
+		{`$$$$`, ``},
+		{`$tag$it's $$ a\n$tag$`, `it\'s $$ a\\n`},
+	}
+	for _, tc := range testCases {
+		lexer := NewLexer(tc.input)
+		require.NoError(t, lexer.consumeToken(), tc.input)
+		require.Equal(t, TokenKindString, lexer.currentToken.Kind)
+		require.Equal(t, tc.expected, lexer.currentToken.String)
+		require.True(t, lexer.isEOF())
+	}
+
+	// a lone $ still starts an identifier
+	lexer := NewLexer("$1")
+	require.NoError(t, lexer.consumeToken())
+	require.Equal(t, TokenKindIdent, lexer.currentToken.Kind)
+
+	err := lexAll("SELECT $tag$unclosed$$")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unclosed heredoc $tag$")
+
+	stmts, err := NewParser("SELECT $$it's$$").ParseStmts()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT 'it\'s'`, Format(stmts[0]))
+}
@@ -0,0 +1,572 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamInfo describes a parameter of a statement found by CollectParams.
+type ParamInfo struct {
+	// Name is the name of a `{name:Type}` query parameter, or "" for a
+	// positional `?` placeholder.
+	Name string
+	// Type is the declared type of a query parameter; nil for a placeholder.
+	Type ColumnType
+	// Positions are where the parameter appears, in source order. A
+	// placeholder appears once.
+	Positions []Pos
+}
+
+// ParamConflictError reports a query parameter declared with different types.
+type ParamConflictError struct {
+	Name      string
+	Types     []ColumnType // the distinct declared types
+	Positions []Pos        // where each of them is first declared
+}
+
+func (e *ParamConflictError) Error() string {
+	types := make([]string, 0, len(e.Types))
+	for _, typ := range e.Types {
+		types = append(types, Format(typ))
+	}
+	return fmt.Sprintf("parameter %s is declared with conflicting types %s", e.Name, strings.Join(types, " and "))
+}
+
+// CollectParams returns the parameters of stmt, ordered by where they first
+// appear: every `{name:Type}` query parameter once, with the positions of
+// all of its uses, and every positional `?` placeholder. If a query parameter
+// is declared with different types, the first is reported in its ParamInfo
+// and the error, joining a *ParamConflictError for every such parameter, is
+// returned along with the parameters.
+func CollectParams(stmt Expr) ([]ParamInfo, error) {
+	c := &paramCollector{index: map[string]int{}, conflicts: map[string]*ParamConflictError{}}
+	Walk(stmt, func(node Expr) bool {
+		switch node := node.(type) {
+		case *PlaceHolder:
+			c.params = append(c.params, ParamInfo{Positions: []Pos{node.Pos()}})
+		case *QueryParam:
+			c.add(node)
+		}
+		return true
+	})
+
+	for _, param := range c.params {
+		sort.Slice(param.Positions, func(i, j int) bool {
+			return param.Positions[i] < param.Positions[j]
+		})
+	}
+	sort.SliceStable(c.params, func(i, j int) bool {
+		return c.params[i].Positions[0] < c.params[j].Positions[0]
+	})
+	errs := make([]error, 0, len(c.conflictNames))
+	for _, name := range c.conflictNames {
+		errs = append(errs, c.conflicts[name])
+	}
+	return c.params, errors.Join(errs...)
+}
+
+type paramCollector struct {
+	params        []ParamInfo
+	index         map[string]int // of each query parameter in params
+	conflicts     map[string]*ParamConflictError
+	conflictNames []string
+}
+
+func (c *paramCollector) add(param *QueryParam) {
+	name := param.Name.Name
+	i, ok := c.index[name]
+	if !ok {
+		c.index[name] = len(c.params)
+		c.params = append(c.params, ParamInfo{Name: name, Type: param.Type, Positions: []Pos{param.Pos()}})
+		return
+	}
+	c.params[i].Positions = append(c.params[i].Positions, param.Pos())
+	if Equal(c.params[i].Type, param.Type, IgnorePositions()) {
+		return
+	}
+
+	conflict := c.conflicts[name]
+	if conflict == nil {
+		conflict = &ParamConflictError{
+			Name:      name,
+			Types:     []ColumnType{c.params[i].Type},
+			Positions: []Pos{c.params[i].Positions[0]},
+		}
+		c.conflicts[name] = conflict
+		c.conflictNames = append(c.conflictNames, name)
+	}
+	for _, typ := range conflict.Types {
+		if Equal(typ, param.Type, IgnorePositions()) {
+			return
+		}
+	}
+	conflict.Types = append(conflict.Types, param.Type)
+	conflict.Positions = append(conflict.Positions, param.Pos())
+}
+
+// BindError lists the problems BindParams found with the parameters of a
+// statement. Names are sorted.
+type BindError struct {
+	Missing  []string          // parameters of the statement that were given no value
+	Unused   []string          // values given for parameters the statement does not have
+	Mistyped []*ParamTypeError // values that do not fit the declared type
+}
+
+func (e *BindError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing parameters: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Unused) > 0 {
+		parts = append(parts, "unused parameters: "+strings.Join(e.Unused, ", "))
+	}
+	for _, mistyped := range e.Mistyped {
+		parts = append(parts, mistyped.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParamTypeError reports a value that cannot be bound to a parameter of the
+// type it is declared with.
+type ParamTypeError struct {
+	Name   string // the parameter
+	Type   string // its declared type, such as Array(UInt8)
+	Value  any
+	Reason string
+}
+
+func (e *ParamTypeError) Error() string {
+	return fmt.Sprintf("parameter %s: cannot bind %#v as %s: %s", e.Name, e.Value, e.Type, e.Reason)
+}
+
+// BindParams returns a copy of stmt in which every `{name:Type}` query
+// parameter is replaced by a literal holding params[name], so that a
+// templated query can be sent to a server without the parameter protocol of
+// its HTTP interface. stmt itself is left unchanged and can be bound again.
+//
+// Values are checked against the declared types and escaped:
+//
+//   - Int8 to Int256 and UInt8 to UInt256 take Go integers (or *big.Int) in
+//     their range, Float32 and Float64 take any Go number, Decimal takes a
+//     finite one that fits its precision and scale, and Bool takes a bool.
+//   - String takes a string or a []byte, and FixedString(N) one of at most
+//     N bytes.
+//   - Date, Date32, DateTime and DateTime64 take a time.Time or a string in
+//     the format ClickHouse prints them in; they are bound as a CAST to the
+//     declared type. A time.Time is converted to the declared time zone of a
+//     DateTime or DateTime64, or else to UTC, which the CAST then declares.
+//   - Array(T) takes a slice or an array of values for T, Nullable(T) takes
+//     nil or a value for T, and LowCardinality(T) a value for T.
+//   - Identifier takes a string, which is bound as a quoted identifier.
+//
+// Non-nil pointers are followed. If a parameter has no value, a value does
+// not fit, or a value is given for a parameter stmt does not have, BindParams
+// returns a *BindError listing them all.
+func BindParams(stmt Expr, params map[string]any) (Expr, error) {
+	b := &binder{
+		params:   params,
+		used:     map[string]bool{},
+		mistyped: map[string]*ParamTypeError{},
+	}
+	bound := Apply(Clone(stmt), func(c *Cursor) bool {
+		switch node := c.Node().(type) {
+		case *QueryParam:
+			if literal := b.bindParam(node); literal != nil {
+				c.Replace(literal)
+			}
+			return false
+		case *WindowFrameParam:
+			literal := b.bindParam(node.Param)
+			if literal == nil {
+				return false
+			}
+			// a frame offset must stay a number
+			if number, ok := literal.(*NumberLiteral); ok {
+				c.Replace(&WindowFrameNumber{
+					Number:    number,
+					EndPos:    node.EndPos,
+					Direction: node.Direction,
+				})
+			} else {
+				b.mistype(node.Param, params[node.Param.Name.Name], "a window frame offset must be a number")
+			}
+			return false
+		}
+		return true
+	}, nil)
+
+	err := &BindError{}
+	for name := range b.used {
+		if _, ok := params[name]; !ok {
+			err.Missing = append(err.Missing, name)
+		}
+	}
+	for name := range params {
+		if !b.used[name] {
+			err.Unused = append(err.Unused, name)
+		}
+	}
+	for _, mistyped := range b.mistyped {
+		err.Mistyped = append(err.Mistyped, mistyped)
+	}
+	if len(err.Missing)+len(err.Unused)+len(err.Mistyped) == 0 {
+		return bound, nil
+	}
+	sort.Strings(err.Missing)
+	sort.Strings(err.Unused)
+	sort.Slice(err.Mistyped, func(i, j int) bool {
+		return err.Mistyped[i].Name < err.Mistyped[j].Name
+	})
+	return nil, err
+}
+
+type binder struct {
+	params   map[string]any
+	used     map[string]bool
+	mistyped map[string]*ParamTypeError
+}
+
+// bindParam returns the literal for param, or nil if it has no value or the
+// value does not fit.
+func (b *binder) bindParam(param *QueryParam) Expr {
+	name := param.Name.Name
+	b.used[name] = true
+	value, ok := b.params[name]
+	if !ok {
+		return nil
+	}
+	literal, reason := bindValue(param.Type, reflect.ValueOf(value), param.Pos(), param.End())
+	if reason != "" {
+		b.mistype(param, value, reason)
+		return nil
+	}
+	return literal
+}
+
+func (b *binder) mistype(param *QueryParam, value any, reason string) {
+	name := param.Name.Name
+	if _, ok := b.mistyped[name]; !ok {
+		b.mistyped[name] = &ParamTypeError{
+			Name:   name,
+			Type:   Format(param.Type),
+			Value:  value,
+			Reason: reason,
+		}
+	}
+}
+
+// bindValue returns the literal for value as typ, placed at pos and end, or
+// the reason why value does not fit typ.
+func bindValue(typ ColumnType, value reflect.Value, pos, end Pos) (Expr, string) {
+	name, args := columnTypeName(typ)
+	if name == "LowCardinality" && len(args) == 1 {
+		return bindValue(args[0], value, pos, end)
+	}
+	if name == "Nullable" && len(args) == 1 {
+		if isNullValue(value) {
+			return &NullLiteral{NullPos: pos}, ""
+		}
+		return bindValue(args[0], value, pos, end)
+	}
+	if isNullValue(value) {
+		return nil, "NULL is only allowed for a Nullable type"
+	}
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch {
+	case name == "Array" && len(args) == 1:
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return nil, "an array needs a slice or an array"
+		}
+		items := make([]Expr, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item, reason := bindValue(args[0], value.Index(i), pos, end)
+			if reason != "" {
+				return nil, fmt.Sprintf("element %d: %s", i, reason)
+			}
+			items = append(items, item)
+		}
+		return &ArrayParamList{
+			LeftBracketPos:  pos,
+			RightBracketPos: end,
+			Items:           &ColumnExprList{ListPos: pos, ListEnd: end, Items: items},
+		}, ""
+	case name == "Identifier":
+		identifier, ok := stringValue(value)
+		if !ok {
+			return nil, "an identifier needs a string"
+		}
+		if identifier == "" || strings.ContainsAny(identifier, "`\\") {
+			return nil, "not a valid identifier"
+		}
+		return &Ident{Name: identifier, QuoteType: BackTicks, NamePos: pos, NameEnd: end}, ""
+	case name == "String" || name == "FixedString":
+		s, ok := stringValue(value)
+		if !ok {
+			return nil, "a string needs a string or a []byte"
+		}
+		if name == "FixedString" {
+			if size, err := strconv.Atoi(typeParam(typ, 0)); err == nil && len(s) > size {
+				return nil, fmt.Sprintf("longer than %d bytes", size)
+			}
+		}
+		return &StringLiteral{LiteralPos: pos, LiteralEnd: end, Literal: quoteEscaper.Replace(s)}, ""
+	case name == "Bool" || name == "Boolean":
+		if value.Kind() != reflect.Bool {
+			return nil, "a bool needs a bool"
+		}
+		return &BoolLiteral{LiteralPos: pos, LiteralEnd: end, Literal: strconv.FormatBool(value.Bool())}, ""
+	case strings.HasPrefix(name, "Int") || strings.HasPrefix(name, "UInt"):
+		bits, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(name, "U"), "Int"))
+		if err != nil {
+			return nil, "unsupported parameter type"
+		}
+		n, ok := integerValue(value)
+		if !ok {
+			return nil, "an integer type needs a Go integer"
+		}
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		low := big.NewInt(0)
+		if !strings.HasPrefix(name, "U") {
+			limit.Rsh(limit, 1)
+			low.Neg(limit)
+		}
+		if n.Cmp(low) < 0 || n.Cmp(limit) >= 0 {
+			return nil, "out of range"
+		}
+		return &NumberLiteral{NumPos: pos, NumEnd: end, Literal: n.String(), Base: 10}, ""
+	case strings.HasPrefix(name, "Decimal"):
+		literal, reason := decimalValue(typ, name, value)
+		if reason != "" {
+			return nil, reason
+		}
+		return &NumberLiteral{NumPos: pos, NumEnd: end, Literal: literal, Base: 10}, ""
+	case strings.HasPrefix(name, "Float"):
+		literal, ok := numberValue(value)
+		if !ok {
+			return nil, "a number type needs a Go number"
+		}
+		return &NumberLiteral{NumPos: pos, NumEnd: end, Literal: literal, Base: 10}, ""
+	case name == "Date" || name == "Date32" || name == "DateTime" || name == "DateTime64":
+		asType := Clone(typ)
+		if t, ok := value.Interface().(time.Time); ok && strings.HasPrefix(name, "DateTime") {
+			// a time.Time is an instant, written as the wall-clock time of
+			// the declared time zone, or of UTC, which the cast then declares
+			zone := dateTimeZone(typ, name)
+			if zone == "" {
+				zone = "UTC"
+				asType = withTimeZone(typ, name, zone, pos, end)
+			}
+			loc, err := time.LoadLocation(zone)
+			if err != nil {
+				return nil, "unknown time zone " + zone
+			}
+			value = reflect.ValueOf(t.In(loc))
+		}
+		literal, ok := timeValue(value, name, typeParam(typ, 0))
+		if !ok {
+			return nil, "a date or time needs a time.Time or a string in the format of the type"
+		}
+		return &CastExpr{
+			CastPos:   pos,
+			Expr:      &StringLiteral{LiteralPos: pos, LiteralEnd: end, Literal: literal},
+			Separator: KeywordAs,
+			AsPos:     pos,
+			AsType:    asType,
+		}, ""
+	}
+	return nil, "unsupported parameter type"
+}
+
+// columnTypeName returns the name of typ and the types it is built from.
+func columnTypeName(typ ColumnType) (string, []ColumnType) {
+	switch typ := typ.(type) {
+	case *ScalarType:
+		return typ.Name.Name, nil
+	case *ComplexType:
+		return typ.Name.Name, typ.Params
+	case *TypeWithParams:
+		return typ.Name.Name, nil
+	}
+	return typ.Type(), nil
+}
+
+// typeParam returns the i-th literal parameter of typ, such as the size of a
+// FixedString, or "".
+func typeParam(typ ColumnType, i int) string {
+	withParams, ok := typ.(*TypeWithParams)
+	if !ok || i >= len(withParams.Params) {
+		return ""
+	}
+	switch param := withParams.Params[i].(type) {
+	case *NumberLiteral:
+		return param.Literal
+	case *StringLiteral:
+		return param.Literal
+	}
+	return ""
+}
+
+// isNullValue reports whether a parameter value stands for NULL: a nil pointer
+// or interface, or no value at all.
+func isNullValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	kind := value.Kind()
+	return (kind == reflect.Pointer || kind == reflect.Interface) && value.IsNil()
+}
+
+func stringValue(value reflect.Value) (string, bool) {
+	switch {
+	case value.Kind() == reflect.String:
+		return value.String(), true
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return string(value.Bytes()), true
+	}
+	return "", false
+}
+
+func integerValue(value reflect.Value) (*big.Int, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(value.Uint()), true
+	}
+	if n, ok := value.Interface().(big.Int); ok {
+		return &n, true
+	}
+	return nil, false
+}
+
+func numberValue(value reflect.Value) (string, bool) {
+	if n, ok := integerValue(value); ok {
+		return n.String(), true
+	}
+	if value.Kind() != reflect.Float32 && value.Kind() != reflect.Float64 {
+		return "", false
+	}
+	f := value.Float()
+	switch {
+	case math.IsNaN(f):
+		return "nan", true
+	case math.IsInf(f, 1):
+		return "inf", true
+	case math.IsInf(f, -1):
+		return "-inf", true
+	}
+	return strconv.FormatFloat(f, 'g', -1, value.Type().Bits()), true
+}
+
+// decimalPrecisions are the precisions of the Decimal types that take only a
+// scale.
+var decimalPrecisions = map[string]int{
+	"Decimal32":  9,
+	"Decimal64":  18,
+	"Decimal128": 38,
+	"Decimal256": 76,
+}
+
+// decimalValue formats value as a literal of the Decimal type typ named name,
+// or returns why it cannot: the value is not a finite number, or it does not
+// fit the precision and scale of the type.
+func decimalValue(typ ColumnType, name string, value reflect.Value) (string, string) {
+	var literal string
+	if n, ok := integerValue(value); ok {
+		literal = n.String()
+	} else if value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64 {
+		f := value.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", "a decimal type needs a finite number"
+		}
+		literal = strconv.FormatFloat(f, 'f', -1, value.Type().Bits())
+	} else {
+		return "", "a number type needs a Go number"
+	}
+
+	// a bare Decimal is Decimal(10, 0)
+	precision, scale := 10, 0
+	if p, ok := decimalPrecisions[name]; ok {
+		precision = p
+		scale, _ = strconv.Atoi(typeParam(typ, 0))
+	} else if p, err := strconv.Atoi(typeParam(typ, 0)); err == nil {
+		precision = p
+		scale, _ = strconv.Atoi(typeParam(typ, 1))
+	}
+	digits, fraction, _ := strings.Cut(strings.TrimPrefix(literal, "-"), ".")
+	if len(fraction) > scale {
+		return "", fmt.Sprintf("more than %d decimal places", scale)
+	}
+	if len(strings.TrimLeft(digits, "0")) > precision-scale {
+		return "", "out of range"
+	}
+	return literal, ""
+}
+
+// dateTimeZone returns the time zone declared by the DateTime or DateTime64
+// type typ named name, or "".
+func dateTimeZone(typ ColumnType, name string) string {
+	if name == "DateTime64" {
+		return typeParam(typ, 1)
+	}
+	return typeParam(typ, 0)
+}
+
+// withTimeZone returns the DateTime or DateTime64 type typ named name, which
+// declares no time zone, with the time zone zone.
+func withTimeZone(typ ColumnType, name, zone string, pos, end Pos) ColumnType {
+	params := []Literal{&StringLiteral{LiteralPos: pos, LiteralEnd: end, Literal: zone}}
+	if name == "DateTime64" {
+		precision := typeParam(typ, 0)
+		if precision == "" {
+			precision = "3" // the default precision
+		}
+		params = append([]Literal{&NumberLiteral{NumPos: pos, NumEnd: end, Literal: precision, Base: 10}}, params...)
+	}
+	return &TypeWithParams{
+		LeftParenPos:  pos,
+		RightParenPos: end,
+		Name:          &Ident{Name: name, NamePos: pos, NameEnd: end},
+		Params:        params,
+	}
+}
+
+// timeValue formats value as a literal of the date or time type name, whose
+// DateTime64 precision is the given one.
+func timeValue(value reflect.Value, name, precision string) (string, bool) {
+	layout := "2006-01-02"
+	if strings.HasPrefix(name, "DateTime") {
+		layout += " 15:04:05"
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		if name == "DateTime64" {
+			digits, err := strconv.Atoi(precision)
+			if err != nil {
+				digits = 3 // the default precision
+			}
+			if digits > 0 {
+				return t.Format(layout + "." + strings.Repeat("0", min(digits, 9))), true
+			}
+		}
+		return t.Format(layout), true
+	}
+	s, ok := stringValue(value)
+	if !ok {
+		return "", false
+	}
+	// parsing accepts fractional seconds the layout does not mention
+	if _, err := time.Parse(layout, s); err != nil {
+		return "", false
+	}
+	return s, true
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseOne(t *testing.T, sql string) Expr {
+	t.Helper()
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	return stmts[0]
+}
+
+func TestBindParams(t *testing.T) {
+	sql := "SELECT {col:Identifier}, {big:UInt256}, {ratio:Float64}, {ok:Bool} FROM t " +
+		"WHERE id IN {ids:Array(UInt32)} AND name = {name:String} AND code = {code:FixedString(2)} " +
+		"AND day = {day:Date} AND at > {at:DateTime64(3)} AND parent = {parent:Nullable(Int8)} " +
+		"AND tags = {tags:Array(Nullable(String))} LIMIT {limit:UInt8}"
+	stmt := parseOne(t, sql)
+
+	limit := uint8(10)
+	tag := "a"
+	bound, err := BindParams(stmt, map[string]any{
+		"col":    "user id",
+		"big":    new(big.Int).Lsh(big.NewInt(1), 200),
+		"ratio":  0.5,
+		"ok":     true,
+		"ids":    []int{1, 2},
+		"name":   `O'Brien \ co`,
+		"code":   []byte("ab"),
+		"day":    "2024-02-29",
+		"at":     time.Date(2024, 1, 2, 3, 4, 5, 600e6, time.UTC),
+		"parent": nil,
+		"tags":   []*string{&tag, nil},
+		"limit":  &limit,
+	})
+	require.NoError(t, err)
+	expected := "SELECT `user id`, 1606938044258990275541962092341162602522202993782792835301376, 0.5, true FROM t " +
+		"WHERE id IN [1, 2] AND name = 'O\\'Brien \\\\ co' AND code = 'ab' " +
+		"AND day = CAST('2024-02-29' AS Date) AND at > CAST('2024-01-02 03:04:05.600' AS DateTime64(3, 'UTC')) AND parent = NULL " +
+		"AND tags = ['a', NULL] LIMIT 10"
+	require.Equal(t, expected, Format(bound))
+
+	// the bound statement parses back to itself
+	require.Equal(t, expected, Format(parseOne(t, expected)))
+	// the template is left unchanged
+	require.Equal(t, Format(parseOne(t, sql)), Format(stmt))
+}
+
+func TestBindParamsEscapesStrings(t *testing.T) {
+	stmt := parseOne(t, "SELECT * FROM t WHERE name = {name:String}")
+	bound, err := BindParams(stmt, map[string]any{"name": `x\' OR 1 = 1 --`})
+	require.NoError(t, err)
+
+	reparsed := parseOne(t, Format(bound))
+	literals := FindAll(reparsed, func(node Expr) bool {
+		_, ok := node.(*StringLiteral)
+		return ok
+	})
+	require.Len(t, literals, 1)
+	require.Equal(t, `x\\\' OR 1 = 1 --`, literals[0].(*StringLiteral).Literal)
+}
+
+func TestBindParamsTimeZones(t *testing.T) {
+	stmt := parseOne(t, "SELECT {a:DateTime}, {b:DateTime('Asia/Tokyo')}, {c:DateTime64(3, 'UTC')}, {d:DateTime64}, {e:DateTime}")
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+	bound, err := BindParams(stmt, map[string]any{"a": at, "b": at, "c": at, "d": at, "e": "2024-01-02 03:04:05"})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT CAST('2024-01-02 02:04:05' AS DateTime('UTC')), "+
+		"CAST('2024-01-02 11:04:05' AS DateTime('Asia/Tokyo')), "+
+		"CAST('2024-01-02 02:04:05.000' AS DateTime64(3, 'UTC')), "+
+		"CAST('2024-01-02 02:04:05.000' AS DateTime64(3, 'UTC')), "+
+		"CAST('2024-01-02 03:04:05' AS DateTime)", Format(bound))
+	require.Equal(t, Format(bound), Format(parseOne(t, Format(bound))))
+
+	_, err = BindParams(parseOne(t, "SELECT {a:DateTime('Nowhere/Atlantis')}"), map[string]any{"a": at})
+	var bindErr *BindError
+	require.True(t, errors.As(err, &bindErr))
+	require.Equal(t, "unknown time zone Nowhere/Atlantis", bindErr.Mistyped[0].Reason)
+}
+
+func TestBindParamsDecimal(t *testing.T) {
+	stmt := parseOne(t, "SELECT {a:Decimal(5, 2)}, {b:Decimal32(2)}, {c:Decimal}, {d:LowCardinality(Nullable(String))}")
+	bound, err := BindParams(stmt, map[string]any{"a": -123.45, "b": 1e6, "c": 1234567890, "d": nil})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT -123.45, 1000000, 1234567890, NULL", Format(bound))
+
+	for _, test := range []struct {
+		value  any
+		reason string
+	}{
+		{math.NaN(), "a decimal type needs a finite number"},
+		{math.Inf(-1), "a decimal type needs a finite number"},
+		{1.005, "more than 2 decimal places"},
+		{1234.5, "out of range"},
+		{"1", "a number type needs a Go number"},
+	} {
+		_, err := BindParams(parseOne(t, "SELECT {a:Decimal(5, 2)}"), map[string]any{"a": test.value})
+		var bindErr *BindError
+		require.True(t, errors.As(err, &bindErr), "%v", test.value)
+		require.Equal(t, test.reason, bindErr.Mistyped[0].Reason, "%v", test.value)
+	}
+}
+
+func TestBindParamsWindowFrame(t *testing.T) {
+	stmt := parseOne(t, "SELECT sum(a) OVER (ORDER BY b ROWS BETWEEN {n:UInt32} PRECEDING AND CURRENT ROW) FROM t")
+	bound, err := BindParams(stmt, map[string]any{"n": 3})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT sum(a) OVER (ORDER BY b ROWS BETWEEN 3 PRECEDING AND CURRENT ROW) FROM t", Format(bound))
+
+	stmt = parseOne(t, "SELECT sum(a) OVER (ORDER BY b ROWS BETWEEN {n:String} PRECEDING AND CURRENT ROW) FROM t")
+	_, err = BindParams(stmt, map[string]any{"n": "3"})
+	require.Error(t, err)
+}
+
+func TestBindParamsErrors(t *testing.T) {
+	stmt := parseOne(t, "SELECT {a:UInt8}, {b:String}, {c:Int8}, {d:Array(Int32)}, {e:Identifier}, {f:Date}, {a:UInt8} FROM t")
+	_, err := BindParams(stmt, map[string]any{
+		"a":     256,
+		"c":     nil,
+		"d":     []string{"x"},
+		"e":     "a`b",
+		"f":     "29/02/2024",
+		"extra": 1,
+	})
+	var bindErr *BindError
+	require.True(t, errors.As(err, &bindErr))
+	require.Equal(t, []string{"b"}, bindErr.Missing)
+	require.Equal(t, []string{"extra"}, bindErr.Unused)
+
+	reasons := map[string]string{}
+	for _, mistyped := range bindErr.Mistyped {
+		reasons[mistyped.Name] = mistyped.Reason
+	}
+	require.Equal(t, map[string]string{
+		"a": "out of range",
+		"c": "NULL is only allowed for a Nullable type",
+		"d": "element 0: an integer type needs a Go integer",
+		"e": "not a valid identifier",
+		"f": "a date or time needs a time.Time or a string in the format of the type",
+	}, reasons)
+	require.Equal(t, "a", bindErr.Mistyped[0].Name)
+	require.Equal(t, "Array(Int32)", bindErr.Mistyped[2].Type)
+	require.Contains(t, err.Error(), "missing parameters: b; unused parameters: extra; parameter a: cannot bind 256 as UInt8: out of range")
+}
+
+func TestCollectParams(t *testing.T) {
+	sql := "SELECT {a:UInt8}, ?, sum(x) OVER (ORDER BY x ROWS BETWEEN {n:UInt32} PRECEDING AND CURRENT ROW) " +
+		"FROM t WHERE b = {b:Array(String)} AND c = ? AND d = {a:UInt8}"
+	params, err := CollectParams(parseOne(t, sql))
+	require.NoError(t, err)
+	require.Len(t, params, 5)
+
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	require.Equal(t, []string{"a", "", "n", "b", ""}, names)
+
+	require.Equal(t, "UInt8", Format(params[0].Type))
+	require.Equal(t, []Pos{Pos(strings.Index(sql, "{a:")), Pos(strings.LastIndex(sql, "{a:"))}, params[0].Positions)
+	require.Nil(t, params[1].Type)
+	require.Equal(t, []Pos{Pos(strings.Index(sql, "?"))}, params[1].Positions)
+	require.Equal(t, "Array(String)", Format(params[3].Type))
+}
+
+func TestCollectParamsConflicts(t *testing.T) {
+	sql := "SELECT {a:UInt8}, {b:String}, {a:Int64}, {a:UInt8}, {b:Nullable(String)}, {a:String}"
+	params, err := CollectParams(parseOne(t, sql))
+	require.Len(t, params, 2)
+	require.Equal(t, "UInt8", Format(params[0].Type))
+	require.Len(t, params[0].Positions, 4)
+
+	var conflict *ParamConflictError
+	require.True(t, errors.As(err, &conflict))
+	require.Equal(t, "a", conflict.Name)
+	require.Len(t, conflict.Types, 3)
+	require.Equal(t, Pos(strings.Index(sql, "{a:Int64}")), conflict.Positions[1])
+	require.Equal(t, "parameter a is declared with conflicting types UInt8 and Int64 and String\n"+
+		"parameter b is declared with conflicting types String and Nullable(String)", err.Error())
+}
@@ -58,7 +58,7 @@ func (p *Parser) parseSystemFlushExpr(pos Pos) (*SystemFlushExpr, error) {
 			Distributed:  distributed,
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected LOGS|DISTRIBUTED")
+		return nil, p.expectedKeywordsError(KeywordLogs, KeywordDistributed)
 	}
 }
 
@@ -74,21 +74,21 @@ func (p *Parser) parseSystemReloadExpr(pos Pos) (*SystemReloadExpr, error) {
 	switch {
 	case p.matchKeyword(KeywordDictionaries):
 		typ = KeywordDictionaries
-		statementEnd = p.current().End
+		statementEnd = p.End()
 		_ = p.lexer.consumeToken()
 	case p.matchKeyword(KeywordDictionary):
 		typ = KeywordDictionary
-		statementEnd = p.current().End
+		statementEnd = p.End()
 		hasDictionaryName = true
 		_ = p.lexer.consumeToken()
 	case p.tryConsumeKeywords(KeywordEmbedded):
 		typ = "EMBEDDED DICTIONARIES"
-		statementEnd = p.current().End
+		statementEnd = p.End()
 		if err := p.expectKeyword(KeywordDictionaries); err != nil {
 			return nil, err
 		}
 	default:
-		return nil, fmt.Errorf("expected DICTIONARIES|DICTIONARY|EMBEDDED")
+		return nil, p.expectedKeywordsError(KeywordDictionaries, KeywordDictionary, KeywordEmbedded)
 	}
 
 	onCluster, err := p.tryParseClusterClause(p.Pos())
@@ -147,7 +147,7 @@ func (p *Parser) parseSystemSyncExpr(pos Pos) (*SystemSyncExpr, error) {
 
 func (p *Parser) parseSystemCtrlExpr(pos Pos) (*SystemCtrlExpr, error) {
 	if !p.matchKeyword(KeywordStart) && !p.matchKeyword(KeywordStop) {
-		return nil, fmt.Errorf("expected START|STOP")
+		return nil, p.expectedKeywordsError(KeywordStart, KeywordStop)
 	}
 	command := strings.ToUpper(p.current().String)
 	_ = p.lexer.consumeToken()
@@ -168,7 +168,7 @@ func (p *Parser) parseSystemCtrlExpr(pos Pos) (*SystemCtrlExpr, error) {
 				return nil, err
 			}
 		default:
-			return nil, fmt.Errorf("expected SENDS|FETCHES|MERGES|TTL")
+			return nil, p.expectedKeywordsError(KeywordSends, KeywordFetches, KeywordMerges, KeywordTtl)
 		}
 		cluster, err := p.parseTableIdentifier(p.Pos())
 		if err != nil {
@@ -194,7 +194,7 @@ func (p *Parser) parseSystemCtrlExpr(pos Pos) (*SystemCtrlExpr, error) {
 			Type:         typ,
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected DISTRIBUTED|REPLICATED")
+		return nil, p.expectedKeywordsError(KeywordDistributed, KeywordReplicated)
 	}
 }
 
@@ -234,7 +234,7 @@ func (p *Parser) parseSystemDropExpr(pos Pos) (*SystemDropExpr, error) {
 			Type:         "COMPILED EXPRESSION CACHE",
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected DNS|MARK|REPLICA|DATABASE|UNCOMPRESSION|COMPILED|QUERY")
+		return nil, p.expectedKeywordsError(KeywordDNS, KeywordMark, KeywordUncompressed, KeywordFileSystem, KeywordQuery, KeywordCompiled)
 	}
 }
 
@@ -348,7 +348,7 @@ func (p *Parser) parseSystemStmt(pos Pos) (*SystemStmt, error) {
 	case p.matchKeyword(KeywordDrop):
 		expr, err = p.parseSystemDropExpr(p.Pos())
 	default:
-		return nil, fmt.Errorf("expected FLUSH|RELOAD|SYNC|START|STOP")
+		return nil, p.expectedKeywordsError(KeywordFlush, KeywordReload, KeywordSync, KeywordStart, KeywordStop)
 	}
 	if err != nil {
 		return nil, err
@@ -418,7 +418,7 @@ func (p *Parser) parseRoleName(_ Pos) (*RoleName, error) {
 			OnCluster: onCluster,
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected <ident> or <string>")
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindString)
 	}
 }
 
@@ -431,13 +431,13 @@ func (p *Parser) tryParseRoleSettings(pos Pos) ([]*RoleSetting, error) {
 
 func (p *Parser) parseRoleSetting(_ Pos) (*RoleSetting, error) {
 	pairs := make([]*SettingPair, 0)
-	for p.matchTokenKind(TokenKindIdent) {
+	for p.matchTokenKind(TokenKindIdent) && !p.matchKeyword(KeywordTo) {
 		name, err := p.parseIdent()
 		if err != nil {
 			return nil, err
 		}
 		switch name.Name {
-		case "NONE", "READABLE", "WRITABLE", "CONST", "CHANGEABLE_IN_READONLY":
+		case "NONE", "READABLE", "READONLY", "WRITABLE", "CONST", "CHANGEABLE_IN_READONLY":
 			return &RoleSetting{
 				Modifier:     name,
 				SettingPairs: pairs,
@@ -458,9 +458,14 @@ func (p *Parser) parseRoleSetting(_ Pos) (*RoleSetting, error) {
 			}
 			// docs: https://clickhouse.com/docs/en/sql-reference/statements/alter/role
 			// the operator "=" was required if the variable name is NOT in
-			// ["MIN", "MAX", "PROFILE"] and value is existed.
-			if value != nil && name.Name != "MIN" && name.Name != "MAX" && name.Name != "PROFILE" && op != TokenKindSingleEQ {
-				return nil, fmt.Errorf("expected operator = or no value, but got %s", op)
+			// ["MIN", "MAX", "PROFILE", "INHERIT"] and value is existed.
+			if value != nil && name.Name != "MIN" && name.Name != "MAX" && name.Name != "PROFILE" && name.Name != "INHERIT" && op != TokenKindSingleEQ {
+				return nil, &ParseError{
+					Code:     ErrCodeExpectedToken,
+					Pos:      value.Pos(),
+					Expected: []TokenKind{TokenKindSingleEQ},
+					Msg:      fmt.Sprintf("expected operator = before the value of %s", name.Name),
+				}
 			}
 			pairs = append(pairs, &SettingPair{
 				Name:      name,
@@ -570,14 +575,14 @@ func (p *Parser) parseAuthenticationClause(pos Pos) (*AuthenticationClause, erro
 			return nil, err
 		}
 		auth.NotIdentified = true
-		auth.AuthEnd = p.current().End
+		auth.AuthEnd = p.End()
 		return auth, nil
 	}
 
 	if err := p.expectKeyword(KeywordIdentified); err != nil {
 		return nil, err
 	}
-	auth.AuthEnd = p.current().End
+	auth.AuthEnd = p.End()
 
 	if p.tryConsumeKeywords(KeywordWith) {
 		if p.matchKeyword(KeywordLdap) {
@@ -594,7 +599,7 @@ func (p *Parser) parseAuthenticationClause(pos Pos) (*AuthenticationClause, erro
 		} else if p.matchKeyword(KeywordKerberos) {
 			_ = p.lexer.consumeToken()
 			auth.IsKerberos = true
-			auth.AuthEnd = p.current().End
+			auth.AuthEnd = p.End()
 			if p.tryConsumeKeywords(KeywordRealm) {
 				realm, err := p.parseString(p.Pos())
 				if err != nil {
@@ -608,7 +613,7 @@ func (p *Parser) parseAuthenticationClause(pos Pos) (*AuthenticationClause, erro
 			authType := p.current().String
 			_ = p.lexer.consumeToken()
 			auth.AuthType = authType
-			auth.AuthEnd = p.current().End
+			auth.AuthEnd = p.End()
 
 			if p.tryConsumeKeywords(KeywordBy) {
 				value, err := p.parseString(p.Pos())
@@ -636,7 +641,7 @@ func (p *Parser) parseHostClause(pos Pos) (*HostClause, error) {
 		hostType := p.current().String
 		_ = p.lexer.consumeToken()
 		host.HostType = hostType
-		host.HostEnd = p.current().End
+		host.HostEnd = p.End()
 	case p.matchOneOfKeywords(KeywordName, KeywordRegexp, KeywordIp, KeywordLike):
 		hostType := p.current().String
 		_ = p.lexer.consumeToken()
@@ -648,7 +653,7 @@ func (p *Parser) parseHostClause(pos Pos) (*HostClause, error) {
 		host.HostValue = value
 		host.HostEnd = value.End()
 	default:
-		return nil, fmt.Errorf("expected LOCAL|NAME|REGEXP|IP|LIKE|ANY|NONE")
+		return nil, p.expectedKeywordsError(KeywordLocal, KeywordName, KeywordRegexp, KeywordIp, KeywordLike, KeywordAny, KeywordNone)
 	}
 
 	return host, nil
@@ -666,7 +671,7 @@ func (p *Parser) parseDefaultRoleClause(pos Pos) (*DefaultRoleClause, error) {
 
 	if p.tryConsumeKeywords(KeywordNone) {
 		defaultRole.None = true
-		defaultRole.DefaultEnd = p.current().End
+		defaultRole.DefaultEnd = p.End()
 		return defaultRole, nil
 	}
 
@@ -699,10 +704,10 @@ func (p *Parser) parseGranteesClause(pos Pos) (*GranteesClause, error) {
 
 	if p.tryConsumeKeywords(KeywordAny) {
 		grantees.Any = true
-		grantees.GranteesEnd = p.current().End
+		grantees.GranteesEnd = p.End()
 	} else if p.tryConsumeKeywords(KeywordNone) {
 		grantees.None = true
-		grantees.GranteesEnd = p.current().End
+		grantees.GranteesEnd = p.End()
 	} else {
 		// Parse list of grantees
 		granteeList := make([]*RoleName, 0)
@@ -824,7 +829,7 @@ func (p *Parser) parseDefaultClause(createUser *CreateUser) (bool, error) {
 		_ = p.lexer.consumeToken() // consume DATABASE
 		if p.tryConsumeKeywords(KeywordNone) {
 			createUser.DefaultDbNone = true
-			createUser.StatementEnd = p.current().End
+			createUser.StatementEnd = p.End()
 		} else {
 			db, err := p.parseIdent()
 			if err != nil {
@@ -936,11 +941,17 @@ func (p *Parser) parseCreateUser(pos Pos) (*CreateUser, error) {
 func (p *Parser) parserDropUserOrRole(pos Pos) (*DropUserOrRole, error) {
 	var target string
 	switch {
-	case p.matchOneOfKeywords(KeywordUser, KeywordRole):
+	case p.matchOneOfKeywords(KeywordUser, KeywordRole, KeywordQuota):
 		target = p.current().String
 		_ = p.lexer.consumeToken()
+	case p.matchOneOfKeywords(KeywordSettings, KeywordProfile):
+		p.tryConsumeKeywords(KeywordSettings)
+		if err := p.expectKeyword(KeywordProfile); err != nil {
+			return nil, err
+		}
+		target = KeywordSettings + " " + KeywordProfile
 	default:
-		return nil, fmt.Errorf("expected USER|ROLE")
+		return nil, p.expectedKeywordsError(KeywordUser, KeywordRole, KeywordQuota, KeywordSettings+" "+KeywordProfile)
 	}
 
 	ifExists, err := p.tryParseIfExists()
@@ -1036,7 +1047,7 @@ func (p *Parser) parsePrivilegeAlter(pos Pos) (*PrivilegeClause, error) {
 		case p.tryConsumeKeywords(KeywordTtl):
 			keywords = append(keywords, KeywordTtl)
 		default:
-			return nil, fmt.Errorf("expected COLUMN|INDEX")
+			return nil, p.expectedKeywordsError(KeywordColumn, KeywordIndex)
 		}
 	case p.tryConsumeKeywords(KeywordOrder):
 		if err := p.expectKeyword(KeywordBy); err != nil {
@@ -1058,7 +1069,7 @@ func (p *Parser) parsePrivilegeAlter(pos Pos) (*PrivilegeClause, error) {
 		case p.tryConsumeKeywords(KeywordRefresh):
 			keywords = append(keywords, KeywordRefresh)
 		default:
-			return nil, fmt.Errorf("expected MODIFY|REFRESH")
+			return nil, p.expectedKeywordsError(KeywordModify, KeywordRefresh)
 		}
 	case p.matchOneOfKeywords(KeywordMove, KeywordFreeze):
 		keyword := p.current().String
@@ -1069,7 +1080,7 @@ func (p *Parser) parsePrivilegeAlter(pos Pos) (*PrivilegeClause, error) {
 		}
 		keywords = append(keywords, KeywordPartition)
 	default:
-		return nil, fmt.Errorf("expected UPDATE|DELETE|ADD|DROP|MODIFY|CLEAR|COMMENT|RENAME|MATERIALIZED|ORDER|SAMPLE|SETTINGS|VIEW|MOVE|FREEZE")
+		return nil, p.expectedKeywordsError(KeywordUpdate, KeywordDelete, KeywordAdd, KeywordDrop, KeywordModify, KeywordClear, KeywordComment, KeywordRename, KeywordMaterialized, KeywordOrder, KeywordSample, KeywordSettings, KeywordView, KeywordMove, KeywordFreeze)
 	}
 	return &PrivilegeClause{
 		PrivilegePos: pos,
@@ -1095,7 +1106,7 @@ func (p *Parser) parsePrivilegeCreate(pos Pos) (*PrivilegeClause, error) {
 		}
 		keywords = append(keywords, KeywordRows, KeywordPolicy)
 	default:
-		return nil, fmt.Errorf("expected DATABASE|DICTIONARY|TABLE|FUNCTION|VIEW|USER|ROLE|ROWS")
+		return nil, p.expectedKeywordsError(KeywordDatabase, KeywordDictionary, KeywordTable, KeywordFunction, KeywordView, KeywordUser, KeywordRole, KeywordRows)
 	}
 	return &PrivilegeClause{
 		PrivilegePos: pos,
@@ -1111,7 +1122,7 @@ func (p *Parser) parsePrivilegeDrop(pos Pos) (*PrivilegeClause, error) {
 		_ = p.lexer.consumeToken()
 		keywords = append(keywords, keyword)
 	default:
-		return nil, fmt.Errorf("expected DATABASE|DICTIONARY|TABLE|FUNCTION|VIEW")
+		return nil, p.expectedKeywordsError(KeywordDatabase, KeywordDictionary, KeywordTable, KeywordFunction, KeywordView)
 	}
 	return &PrivilegeClause{
 		PrivilegePos: pos,
@@ -1127,7 +1138,7 @@ func (p *Parser) parsePrivilegeShow(pos Pos) (*PrivilegeClause, error) {
 		_ = p.lexer.consumeToken()
 		keywords = append(keywords, keyword)
 	default:
-		return nil, fmt.Errorf("expected DATABASES|DICTIONARIES|TABLES|COLUMNS")
+		return nil, p.expectedKeywordsError(KeywordDatabases, KeywordDictionaries, KeywordTables, KeywordColumns)
 	}
 	return &PrivilegeClause{
 		PrivilegePos: pos,
@@ -1156,7 +1167,7 @@ func (p *Parser) parsePrivilegeSystem(pos Pos) (*PrivilegeClause, error) {
 			}
 			keywords = append(keywords, KeywordCache)
 		default:
-			return nil, fmt.Errorf("expected CACHE|MARK|DNS|UNCOMPRESSED")
+			return nil, p.expectedKeywordsError(KeywordCache, KeywordMark, KeywordDNS, KeywordUncompressed)
 		}
 	case p.tryConsumeKeywords(KeywordReload):
 		keywords = append(keywords, KeywordReload)
@@ -1166,7 +1177,7 @@ func (p *Parser) parsePrivilegeSystem(pos Pos) (*PrivilegeClause, error) {
 			_ = p.lexer.consumeToken()
 			keywords = append(keywords, keyword)
 		default:
-			return nil, fmt.Errorf("expected DICTIONARY|FUNCTION|FUNCTIONS|CONFIG")
+			return nil, p.expectedKeywordsError(KeywordDictionary, KeywordFunction, KeywordFunctions, KeywordConfig)
 		}
 	case p.tryConsumeKeywords(KeywordFlush):
 		keywords = append(keywords, KeywordFlush)
@@ -1176,7 +1187,7 @@ func (p *Parser) parsePrivilegeSystem(pos Pos) (*PrivilegeClause, error) {
 			_ = p.lexer.consumeToken()
 			keywords = append(keywords, keyword)
 		default:
-			return nil, fmt.Errorf("expected LOGS|DISTRIBUTED")
+			return nil, p.expectedKeywordsError(KeywordLogs, KeywordDistributed)
 		}
 	case p.tryConsumeKeywords(KeywordTtl):
 		keywords = append(keywords, KeywordTtl)
@@ -1199,7 +1210,7 @@ func (p *Parser) parsePrivilegeSystem(pos Pos) (*PrivilegeClause, error) {
 		}
 		keywords = append(keywords, KeywordQueues)
 	default:
-		return nil, fmt.Errorf("expected QUEUES|SHUTDOWN|MERGES|FETCHES|SENDS|MOVES|CLUSTER|DROP|RELOAD|FLUSH|TTL|SYNC|RESTART|REPLICATION")
+		return nil, p.expectedKeywordsError(KeywordQueues, KeywordShutdown, KeywordMerges, KeywordFetches, KeywordSends, KeywordMoves, KeywordCluster, KeywordDrop, KeywordReload, KeywordFlush, KeywordTtl, KeywordSync, KeywordRestart, KeywordReplication)
 	}
 	return &PrivilegeClause{
 		PrivilegePos: pos,
@@ -1268,7 +1279,7 @@ func (p *Parser) parsePrivilegeClause(pos Pos) (*PrivilegeClause, error) {
 			Keywords:     []string{KeywordRole, KeywordAdmin},
 		}, nil
 	}
-	return nil, fmt.Errorf("expected SELECT|INSERT|ALTER|CREATE|DROP|SHOW|KILL|SYSTEM|OPTIMIZE|TRUNCATE")
+	return nil, p.expectedKeywordsError(KeywordSelect, KeywordInsert, KeywordAlter, KeywordCreate, KeywordDrop, KeywordShow, KeywordKill, KeywordSystem, KeywordOptimize, KeywordTruncate)
 }
 
 func (p *Parser) parsePrivilegeRoles(_ Pos) ([]*Ident, error) {
@@ -1402,6 +1413,103 @@ func (p *Parser) parseGrantPrivilegeStmt(pos Pos) (*GrantPrivilegeStmt, error) {
 	}, nil
 }
 
+func (p *Parser) parseRevokeStmt(pos Pos) (*RevokeStmt, error) {
+	if err := p.expectKeyword(KeywordRevoke); err != nil {
+		return nil, err
+	}
+	onCluster, err := p.tryParseClusterClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	revoke := &RevokeStmt{
+		RevokePos: pos,
+		OnCluster: onCluster,
+	}
+	switch {
+	case p.tryConsumeKeywords(KeywordAdmin, KeywordOption, KeywordFor):
+		revoke.AdminOptionFor = true
+	case p.tryConsumeKeywords(KeywordGrant, KeywordOption, KeywordFor):
+		revoke.GrantOptionFor = true
+	}
+
+	// ADMIN OPTION FOR only applies to roles.
+	if revoke.AdminOptionFor || p.revokesRoles() {
+		revoke.Roles, err = p.parsePrivilegeRoles(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		privilege, err := p.parsePrivilegeClause(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		revoke.Privileges = append(revoke.Privileges, privilege)
+		for p.tryConsumeTokenKind(TokenKindComma) != nil {
+			privilege, err := p.parsePrivilegeClause(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+			revoke.Privileges = append(revoke.Privileges, privilege)
+		}
+		if err := p.expectKeyword(KeywordOn); err != nil {
+			return nil, err
+		}
+		revoke.On, err = p.parseGrantSource(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeyword(KeywordFrom); err != nil {
+		return nil, err
+	}
+	if p.matchKeyword(KeywordAll) {
+		revoke.FromAll = true
+		revoke.StatementEnd = p.End()
+		_ = p.lexer.consumeToken()
+		if p.tryConsumeKeywords(KeywordExcept) {
+			revoke.Except, err = p.parsePrivilegeRoles(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+			revoke.StatementEnd = revoke.Except[len(revoke.Except)-1].NameEnd
+		}
+		return revoke, nil
+	}
+	revoke.From, err = p.parsePrivilegeRoles(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	revoke.StatementEnd = revoke.From[len(revoke.From)-1].NameEnd
+	return revoke, nil
+}
+
+// revokesRoles reports whether the REVOKE being parsed revokes roles rather
+// than privileges. Role names may be keywords, such as admin, so they are
+// told apart by what follows the list: ON for privileges, FROM for roles.
+func (p *Parser) revokesRoles() bool {
+	savedState := p.lexer.saveState()
+	defer p.lexer.restoreState(savedState)
+
+	depth := 0
+	for p.current() != nil && !p.matchTokenKind(";") {
+		switch {
+		case p.matchTokenKind(TokenKindLParen):
+			depth++
+		case p.matchTokenKind(TokenKindRParen):
+			depth--
+		case depth == 0 && p.matchKeyword(KeywordOn):
+			return false
+		case depth == 0 && p.matchKeyword(KeywordFrom):
+			return true
+		}
+		if err := p.lexer.consumeToken(); err != nil {
+			return false
+		}
+	}
+	return false
+}
+
 func (p *Parser) parseAlterRole(pos Pos) (*AlterRole, error) {
 	if err := p.expectKeyword(KeywordRole); err != nil {
 		return nil, err
@@ -1466,3 +1574,669 @@ func (p *Parser) parseRoleRenamePair(_ Pos) (*RoleRenamePair, error) {
 	}
 	return roleRenamePair, nil
 }
+
+func (p *Parser) parseAlterUser(pos Pos) (*AlterUser, error) {
+	if err := p.expectKeyword(KeywordUser); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+
+	userRenamePairs, err := p.parseRoleRenamePairs()
+	if err != nil {
+		return nil, err
+	}
+
+	// The clauses shared with CREATE USER are parsed into a scratch CreateUser,
+	// while ADD HOST and DROP HOST only exist in ALTER USER.
+	clauses := &CreateUser{StatementEnd: userRenamePairs[len(userRenamePairs)-1].End()}
+	var addHosts, dropHosts []*HostClause
+	for {
+		if err := p.parseOptionalClauses(clauses); err != nil {
+			return nil, err
+		}
+		var hosts *[]*HostClause
+		switch {
+		case p.matchKeyword(KeywordAdd):
+			hosts = &addHosts
+		case p.matchKeyword(KeywordDrop):
+			hosts = &dropHosts
+		}
+		if hosts == nil {
+			break
+		}
+		_ = p.lexer.consumeToken()
+		*hosts, err = p.parseHostClauses()
+		if err != nil {
+			return nil, err
+		}
+		clauses.StatementEnd = (*hosts)[len(*hosts)-1].End()
+	}
+
+	return &AlterUser{
+		AlterPos:        pos,
+		StatementEnd:    clauses.StatementEnd,
+		IfExists:        ifExists,
+		UserRenamePairs: userRenamePairs,
+		Authentication:  clauses.Authentication,
+		ValidUntil:      clauses.ValidUntil,
+		Hosts:           clauses.Hosts,
+		AddHosts:        addHosts,
+		DropHosts:       dropHosts,
+		DefaultRole:     clauses.DefaultRole,
+		DefaultDatabase: clauses.DefaultDatabase,
+		DefaultDbNone:   clauses.DefaultDbNone,
+		Grantees:        clauses.Grantees,
+		Settings:        clauses.Settings,
+	}, nil
+}
+
+func (p *Parser) parseRoleRenamePairs() ([]*RoleRenamePair, error) {
+	pairs := make([]*RoleRenamePair, 0)
+	pair, err := p.parseRoleRenamePair(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	pairs = append(pairs, pair)
+	for p.tryConsumeTokenKind(TokenKindComma) != nil {
+		pair, err := p.parseRoleRenamePair(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// parseAccessEntityModifiers parses the IF NOT EXISTS or OR REPLACE following
+// the object keyword of CREATE QUOTA, ROW POLICY and SETTINGS PROFILE.
+func (p *Parser) parseAccessEntityModifiers() (ifNotExists bool, orReplace bool, err error) {
+	if p.tryConsumeKeywords(KeywordOr) {
+		if err := p.expectKeyword(KeywordReplace); err != nil {
+			return false, false, err
+		}
+		return false, true, nil
+	}
+	ifNotExists, err = p.tryParseIfNotExists()
+	return ifNotExists, false, err
+}
+
+func (p *Parser) tryParseAccessStorageType() (*Ident, error) {
+	if !p.tryConsumeKeywords(KeywordIn) {
+		return nil, nil // nolint
+	}
+	return p.parseIdent()
+}
+
+func (p *Parser) tryParseToRolesClause(pos Pos) (*ToRolesClause, error) {
+	if !p.tryConsumeKeywords(KeywordTo) {
+		return nil, nil // nolint
+	}
+
+	to := &ToRolesClause{ToPos: pos}
+	switch {
+	case p.matchKeyword(KeywordNone):
+		to.ToEnd = p.End()
+		_ = p.lexer.consumeToken()
+		to.None = true
+		return to, nil
+	case p.matchKeyword(KeywordAll):
+		to.ToEnd = p.End()
+		_ = p.lexer.consumeToken()
+		to.All = true
+		if !p.tryConsumeKeywords(KeywordExcept) {
+			return to, nil
+		}
+		except, err := p.parseUserNames()
+		if err != nil {
+			return nil, err
+		}
+		to.Except = except
+		to.ToEnd = except[len(except)-1].End()
+		return to, nil
+	}
+
+	roles, err := p.parseUserNames()
+	if err != nil {
+		return nil, err
+	}
+	to.Roles = roles
+	to.ToEnd = roles[len(roles)-1].End()
+	return to, nil
+}
+
+func (p *Parser) parseCreateQuota(pos Pos) (*CreateQuota, error) {
+	if err := p.expectKeyword(KeywordQuota); err != nil {
+		return nil, err
+	}
+
+	ifNotExists, orReplace, err := p.parseAccessEntityModifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := p.parseUserNames()
+	if err != nil {
+		return nil, err
+	}
+	createQuota := &CreateQuota{
+		CreatePos:    pos,
+		StatementEnd: names[len(names)-1].End(),
+		IfNotExists:  ifNotExists,
+		OrReplace:    orReplace,
+		Names:        names,
+	}
+
+	createQuota.AccessStorageType, err = p.tryParseAccessStorageType()
+	if err != nil {
+		return nil, err
+	}
+	if createQuota.AccessStorageType != nil {
+		createQuota.StatementEnd = createQuota.AccessStorageType.End()
+	}
+
+	createQuota.KeyedBy, createQuota.NotKeyed, err = p.tryParseQuotaKey(&createQuota.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	createQuota.Limits, err = p.tryParseQuotaLimits(&createQuota.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	createQuota.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if createQuota.To != nil {
+		createQuota.StatementEnd = createQuota.To.End()
+	}
+	return createQuota, nil
+}
+
+func (p *Parser) parseAlterQuota(pos Pos) (*AlterQuota, error) {
+	if err := p.expectKeyword(KeywordQuota); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+
+	renamePairs, err := p.parseRoleRenamePairs()
+	if err != nil {
+		return nil, err
+	}
+	alterQuota := &AlterQuota{
+		AlterPos:     pos,
+		StatementEnd: renamePairs[len(renamePairs)-1].End(),
+		IfExists:     ifExists,
+		RenamePairs:  renamePairs,
+	}
+
+	alterQuota.KeyedBy, alterQuota.NotKeyed, err = p.tryParseQuotaKey(&alterQuota.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	alterQuota.Limits, err = p.tryParseQuotaLimits(&alterQuota.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	alterQuota.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if alterQuota.To != nil {
+		alterQuota.StatementEnd = alterQuota.To.End()
+	}
+	return alterQuota, nil
+}
+
+// tryParseQuotaKey parses KEYED BY key[, key] or NOT KEYED and moves end past
+// the clause.
+func (p *Parser) tryParseQuotaKey(end *Pos) ([]*Ident, bool, error) {
+	switch {
+	case p.matchKeyword(KeywordNot):
+		_ = p.lexer.consumeToken()
+		*end = p.End()
+		if err := p.expectKeyword(KeywordKeyed); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	case p.tryConsumeKeywords(KeywordKeyed):
+		if err := p.expectKeyword(KeywordBy); err != nil {
+			return nil, false, err
+		}
+		keys := make([]*Ident, 0)
+		for {
+			key, err := p.parseIdent()
+			if err != nil {
+				return nil, false, err
+			}
+			keys = append(keys, key)
+			*end = key.End()
+			if p.tryConsumeTokenKind(TokenKindComma) == nil {
+				break
+			}
+		}
+		return keys, false, nil
+	}
+	return nil, false, nil
+}
+
+// tryParseQuotaLimits parses the comma separated FOR INTERVAL clauses of a
+// quota and moves end past the last one.
+func (p *Parser) tryParseQuotaLimits(end *Pos) ([]*QuotaLimit, error) {
+	if !p.matchKeyword(KeywordFor) {
+		return nil, nil
+	}
+	limits := make([]*QuotaLimit, 0)
+	for {
+		limit, err := p.parseQuotaLimit(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+		*end = limit.End()
+		if p.tryConsumeTokenKind(TokenKindComma) == nil {
+			break
+		}
+	}
+	return limits, nil
+}
+
+func (p *Parser) parseQuotaLimit(pos Pos) (*QuotaLimit, error) {
+	if err := p.expectKeyword(KeywordFor); err != nil {
+		return nil, err
+	}
+	limit := &QuotaLimit{ForPos: pos}
+	limit.Randomized = p.tryConsumeKeywords(KeywordRandomized)
+	if err := p.expectKeyword(KeywordInterval); err != nil {
+		return nil, err
+	}
+	interval, err := p.parseNumber(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	limit.Interval = interval
+	unit, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	limit.Unit = unit
+
+	switch {
+	case p.matchKeyword(KeywordNo):
+		_ = p.lexer.consumeToken()
+		limit.LimitEnd = p.End()
+		if err := p.expectKeyword(KeywordLimits); err != nil {
+			return nil, err
+		}
+		limit.NoLimits = true
+	case p.matchKeyword(KeywordTracking):
+		_ = p.lexer.consumeToken()
+		limit.LimitEnd = p.End()
+		if err := p.expectKeyword(KeywordOnly); err != nil {
+			return nil, err
+		}
+		limit.TrackingOnly = true
+	case p.matchKeyword(KeywordMax):
+		_ = p.lexer.consumeToken()
+		limit.Limits = make([]*SettingPair, 0)
+		for {
+			pair, err := p.parseQuotaLimitPair()
+			if err != nil {
+				return nil, err
+			}
+			limit.Limits = append(limit.Limits, pair)
+			limit.LimitEnd = pair.End()
+			// a comma either separates two limits of this interval, which may
+			// repeat MAX, or starts the next FOR clause
+			nextToken, err := p.lexer.peekToken()
+			if err != nil {
+				return nil, err
+			}
+			if !p.matchTokenKind(TokenKindComma) || nextToken == nil ||
+				(nextToken.Kind == TokenKindKeyword && nextToken.String == KeywordFor) {
+				break
+			}
+			_ = p.lexer.consumeToken()
+			p.tryConsumeKeywords(KeywordMax)
+		}
+	default:
+		return nil, p.expectedKeywordsError(KeywordMax, KeywordNo+" "+KeywordLimits, KeywordTracking+" "+KeywordOnly)
+	}
+	return limit, nil
+}
+
+func (p *Parser) parseQuotaLimitPair() (*SettingPair, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectTokenKind(TokenKindSingleEQ); err != nil {
+		return nil, err
+	}
+	value, err := p.parseNumber(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	return &SettingPair{
+		Name:      name,
+		Operation: TokenKindSingleEQ,
+		Value:     value,
+	}, nil
+}
+
+// parseRowPolicyNames parses `name [ON CLUSTER c] ON table [RENAME TO n]`
+// items, where names followed by a comma may share the table of the next one.
+func (p *Parser) parseRowPolicyNames(allowRename bool) ([]*RowPolicyName, error) {
+	policies := make([]*RowPolicyName, 0)
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		policy := &RowPolicyName{Name: name}
+		policies = append(policies, policy)
+		if p.matchKeyword(KeywordOn) {
+			nextToken, err := p.lexer.peekToken()
+			if err != nil {
+				return nil, err
+			}
+			if nextToken != nil && nextToken.Kind == TokenKindKeyword && nextToken.String == KeywordCluster {
+				policy.OnCluster, err = p.tryParseClusterClause(p.Pos())
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		if p.tryConsumeKeywords(KeywordOn) {
+			policy.On, err = p.parseGrantSource(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+			if allowRename && p.tryConsumeKeywords(KeywordRename) {
+				if err := p.expectKeyword(KeywordTo); err != nil {
+					return nil, err
+				}
+				policy.RenameTo, err = p.parseIdent()
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else if !p.matchTokenKind(TokenKindComma) {
+			return nil, p.expectedKeywordsError(KeywordOn)
+		}
+		if p.tryConsumeTokenKind(TokenKindComma) == nil {
+			break
+		}
+	}
+	return policies, nil
+}
+
+// tryParseRowPolicyAs parses AS PERMISSIVE or AS RESTRICTIVE.
+func (p *Parser) tryParseRowPolicyAs(end *Pos) (string, error) {
+	if !p.tryConsumeKeywords(KeywordAs) {
+		return "", nil
+	}
+	if !p.matchOneOfKeywords(KeywordPermissive, KeywordRestrictive) {
+		return "", p.expectedKeywordsError(KeywordPermissive, KeywordRestrictive)
+	}
+	as := p.current().String
+	*end = p.End()
+	_ = p.lexer.consumeToken()
+	return as, nil
+}
+
+func (p *Parser) tryParseForSelect(end *Pos) (bool, error) {
+	if !p.tryConsumeKeywords(KeywordFor) {
+		return false, nil
+	}
+	*end = p.End()
+	if err := p.expectKeyword(KeywordSelect); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *Parser) parseCreateRowPolicy(pos Pos) (*CreateRowPolicy, error) {
+	p.tryConsumeKeywords(KeywordRow)
+	if err := p.expectKeyword(KeywordPolicy); err != nil {
+		return nil, err
+	}
+
+	ifNotExists, orReplace, err := p.parseAccessEntityModifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := p.parseRowPolicyNames(false)
+	if err != nil {
+		return nil, err
+	}
+	createRowPolicy := &CreateRowPolicy{
+		CreatePos:    pos,
+		StatementEnd: policies[len(policies)-1].End(),
+		IfNotExists:  ifNotExists,
+		OrReplace:    orReplace,
+		Policies:     policies,
+	}
+
+	createRowPolicy.AccessStorageType, err = p.tryParseAccessStorageType()
+	if err != nil {
+		return nil, err
+	}
+	if createRowPolicy.AccessStorageType != nil {
+		createRowPolicy.StatementEnd = createRowPolicy.AccessStorageType.End()
+	}
+	createRowPolicy.ForSelect, err = p.tryParseForSelect(&createRowPolicy.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	if p.tryConsumeKeywords(KeywordUsing) {
+		createRowPolicy.Using, err = p.parseExpr(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		createRowPolicy.StatementEnd = createRowPolicy.Using.End()
+	}
+	createRowPolicy.As, err = p.tryParseRowPolicyAs(&createRowPolicy.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	createRowPolicy.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if createRowPolicy.To != nil {
+		createRowPolicy.StatementEnd = createRowPolicy.To.End()
+	}
+	return createRowPolicy, nil
+}
+
+func (p *Parser) parseAlterRowPolicy(pos Pos) (*AlterRowPolicy, error) {
+	p.tryConsumeKeywords(KeywordRow)
+	if err := p.expectKeyword(KeywordPolicy); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := p.parseRowPolicyNames(true)
+	if err != nil {
+		return nil, err
+	}
+	alterRowPolicy := &AlterRowPolicy{
+		AlterPos:     pos,
+		StatementEnd: policies[len(policies)-1].End(),
+		IfExists:     ifExists,
+		Policies:     policies,
+	}
+
+	alterRowPolicy.As, err = p.tryParseRowPolicyAs(&alterRowPolicy.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	alterRowPolicy.ForSelect, err = p.tryParseForSelect(&alterRowPolicy.StatementEnd)
+	if err != nil {
+		return nil, err
+	}
+	if p.tryConsumeKeywords(KeywordUsing) {
+		if p.matchKeyword(KeywordNone) {
+			alterRowPolicy.StatementEnd = p.End()
+			_ = p.lexer.consumeToken()
+			alterRowPolicy.UsingNone = true
+		} else {
+			alterRowPolicy.Using, err = p.parseExpr(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+			alterRowPolicy.StatementEnd = alterRowPolicy.Using.End()
+		}
+	}
+	alterRowPolicy.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if alterRowPolicy.To != nil {
+		alterRowPolicy.StatementEnd = alterRowPolicy.To.End()
+	}
+	return alterRowPolicy, nil
+}
+
+func (p *Parser) parseDropRowPolicy(pos Pos) (*DropRowPolicy, error) {
+	p.tryConsumeKeywords(KeywordRow)
+	if err := p.expectKeyword(KeywordPolicy); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := p.parseRowPolicyNames(false)
+	if err != nil {
+		return nil, err
+	}
+	statementEnd := policies[len(policies)-1].End()
+
+	onCluster, err := p.tryParseClusterClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if onCluster != nil {
+		statementEnd = onCluster.End()
+	}
+
+	var from *Ident
+	if p.tryConsumeKeywords(KeywordFrom) {
+		from, err = p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		statementEnd = from.End()
+	}
+
+	return &DropRowPolicy{
+		DropPos:      pos,
+		StatementEnd: statementEnd,
+		IfExists:     ifExists,
+		Policies:     policies,
+		OnCluster:    onCluster,
+		From:         from,
+	}, nil
+}
+
+func (p *Parser) parseCreateSettingsProfile(pos Pos) (*CreateSettingsProfile, error) {
+	p.tryConsumeKeywords(KeywordSettings)
+	if err := p.expectKeyword(KeywordProfile); err != nil {
+		return nil, err
+	}
+
+	ifNotExists, orReplace, err := p.parseAccessEntityModifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := p.parseUserNames()
+	if err != nil {
+		return nil, err
+	}
+	createProfile := &CreateSettingsProfile{
+		CreatePos:    pos,
+		StatementEnd: names[len(names)-1].End(),
+		IfNotExists:  ifNotExists,
+		OrReplace:    orReplace,
+		Names:        names,
+	}
+
+	createProfile.AccessStorageType, err = p.tryParseAccessStorageType()
+	if err != nil {
+		return nil, err
+	}
+	if createProfile.AccessStorageType != nil {
+		createProfile.StatementEnd = createProfile.AccessStorageType.End()
+	}
+	createProfile.Settings, err = p.tryParseRoleSettings(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if len(createProfile.Settings) > 0 {
+		createProfile.StatementEnd = createProfile.Settings[len(createProfile.Settings)-1].End()
+	}
+	createProfile.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if createProfile.To != nil {
+		createProfile.StatementEnd = createProfile.To.End()
+	}
+	return createProfile, nil
+}
+
+func (p *Parser) parseAlterSettingsProfile(pos Pos) (*AlterSettingsProfile, error) {
+	p.tryConsumeKeywords(KeywordSettings)
+	if err := p.expectKeyword(KeywordProfile); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+
+	renamePairs, err := p.parseRoleRenamePairs()
+	if err != nil {
+		return nil, err
+	}
+	alterProfile := &AlterSettingsProfile{
+		AlterPos:     pos,
+		StatementEnd: renamePairs[len(renamePairs)-1].End(),
+		IfExists:     ifExists,
+		RenamePairs:  renamePairs,
+	}
+
+	alterProfile.Settings, err = p.tryParseRoleSettings(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if len(alterProfile.Settings) > 0 {
+		alterProfile.StatementEnd = alterProfile.Settings[len(alterProfile.Settings)-1].End()
+	}
+	alterProfile.To, err = p.tryParseToRolesClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if alterProfile.To != nil {
+		alterProfile.StatementEnd = alterProfile.To.End()
+	}
+	return alterProfile, nil
+}
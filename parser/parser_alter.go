@@ -1,10 +1,5 @@
 package parser
 
-import (
-	"errors"
-	"fmt"
-)
-
 func (p *Parser) parseAlterTable(pos Pos) (*AlterTable, error) {
 	alterTable := &AlterTable{
 		AlterPos:   pos,
@@ -60,8 +55,16 @@ func (p *Parser) parseAlterTable(pos Pos) (*AlterTable, error) {
 			alter, err = p.parseAlterTableDelete(p.Pos())
 		case p.matchKeyword(KeywordUpdate):
 			alter, err = p.parseAlterTableUpdate(p.Pos())
+		case p.matchKeyword(KeywordMove):
+			alter, err = p.parseAlterTableMovePartition(p.Pos())
+		case p.matchKeyword(KeywordFetch):
+			alter, err = p.parseAlterTableFetchPartition(p.Pos())
+		case p.matchKeyword(KeywordComment):
+			alter, err = p.parseAlterTableCommentColumn(p.Pos())
+		case p.matchKeyword(KeywordApply):
+			alter, err = p.parseAlterTableApplyDeletedMask(p.Pos())
 		default:
-			return nil, errors.New("expected token: ADD|DROP|ATTACH|DETACH|FREEZE|REMOVE|CLEAR|MODIFY|REPLACE|MATERIALIZE|RESET|DELETE|UPDATE")
+			return nil, p.expectedKeywordsError(KeywordAdd, KeywordDrop, KeywordAttach, KeywordDetach, KeywordFreeze, KeywordRemove, KeywordClear, KeywordModify, KeywordReplace, KeywordMaterialize, KeywordReset, KeywordDelete, KeywordUpdate, KeywordMove, KeywordFetch, KeywordComment, KeywordApply)
 		}
 		if err != nil {
 			return nil, err
@@ -72,7 +75,7 @@ func (p *Parser) parseAlterTable(pos Pos) (*AlterTable, error) {
 		}
 	}
 	if len(alterTable.AlterExprs) == 0 {
-		return nil, errors.New("expected token: ADD|DROP")
+		return nil, p.expectedKeywordsError(KeywordAdd, KeywordDrop)
 	}
 	alterTable.StatementEnd = alterTable.AlterExprs[len(alterTable.AlterExprs)-1].End()
 
@@ -91,8 +94,10 @@ func (p *Parser) parseAlterTableAdd(pos Pos) (AlterTableClause, error) {
 		return p.parseAlterTableAddIndex(pos)
 	case p.matchKeyword(KeywordProjection):
 		return p.parseAlterTableAddProjection(pos)
+	case p.matchKeyword(KeywordConstraint):
+		return p.parseAlterTableAddConstraint(pos)
 	default:
-		return nil, errors.New("expected token: COLUMN|INDEX|PROJECTION")
+		return nil, p.expectedKeywordsError(KeywordColumn, KeywordIndex, KeywordProjection, KeywordConstraint)
 	}
 }
 
@@ -116,8 +121,13 @@ func (p *Parser) parseAlterTableAddColumn(pos Pos) (*AlterTableAddColumn, error)
 	if err != nil {
 		return nil, err
 	}
+	first := false
 	if after != nil {
 		statementEnd = after.End()
+	} else if p.matchKeyword(KeywordFirst) {
+		first = true
+		statementEnd = p.End()
+		_ = p.lexer.consumeToken()
 	}
 
 	settings, err := p.tryParseSettingsClause(p.Pos())
@@ -134,6 +144,7 @@ func (p *Parser) parseAlterTableAddColumn(pos Pos) (*AlterTableAddColumn, error)
 		Column:       column,
 		IfNotExists:  ifNotExists,
 		After:        after,
+		First:        first,
 		Settings:     settings,
 	}, nil
 }
@@ -322,8 +333,10 @@ func (p *Parser) parseAlterTableDrop(pos Pos) (AlterTableClause, error) {
 		return p.parseAlterTableDropClause(pos)
 	case p.matchKeyword(KeywordDetached), p.matchKeyword(KeywordPartition):
 		return p.parseAlterTableDropPartition(pos)
+	case p.matchKeyword(KeywordConstraint):
+		return p.parseAlterTableDropConstraint(pos)
 	default:
-		return nil, errors.New("expected keyword: COLUMN|INDEX|PROJECTION|DETACHED|PARTITION")
+		return nil, p.expectedKeywordsError(KeywordColumn, KeywordIndex, KeywordProjection, KeywordDetached, KeywordPartition, KeywordConstraint)
 	}
 }
 
@@ -420,7 +433,7 @@ func (p *Parser) parseAlterTableDropClause(pos Pos) (AlterTableClause, error) {
 	case p.matchKeyword(KeywordProjection):
 		kind = KeywordProjection
 	default:
-		return nil, fmt.Errorf("expected token: COLUMN|INDEX|PROJECTION, but got %s", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordColumn, KeywordIndex, KeywordProjection)
 	}
 	_ = p.lexer.consumeToken()
 
@@ -549,7 +562,7 @@ func (p *Parser) parseAlterTableClearClause(pos Pos) (AlterTableClause, error) {
 	case p.matchKeyword(KeywordProjection):
 		kind = KeywordProjection
 	default:
-		return nil, fmt.Errorf("expected keyword: COLUMN|INDEX|PROJECTION, but got %q", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordColumn, KeywordIndex, KeywordProjection)
 	}
 	_ = p.lexer.consumeToken()
 
@@ -706,9 +719,33 @@ func (p *Parser) parseAlterTableModify(pos Pos) (AlterTableClause, error) {
 			StatementEnd: statementEnd,
 			Settings:     settings,
 		}, nil
+	case p.matchKeyword(KeywordComment):
+		_ = p.lexer.consumeToken() // consume "COMMENT"
+		comment, err := p.parseString(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableModifyComment{
+			ModifyPos:    pos,
+			StatementEnd: comment.End(),
+			Comment:      comment,
+		}, nil
+	case p.matchKeyword(KeywordSample):
+		_ = p.lexer.consumeToken() // consume "SAMPLE"
+		if err := p.expectKeyword(KeywordBy); err != nil {
+			return nil, err
+		}
+		sampleBy, err := p.parseExpr(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		return &AlterTableModifySampleBy{
+			ModifyPos:    pos,
+			StatementEnd: sampleBy.End(),
+			SampleBy:     sampleBy,
+		}, nil
 	default:
-		return nil, fmt.Errorf("expected keyword: COLUMN|TTL|QUERY|ORDER|SETTING, but got %q",
-			p.currentTokenString())
+		return nil, p.expectedKeywordsError(KeywordColumn, KeywordTtl, KeywordQuery, KeywordOrder, KeywordSetting, KeywordComment, KeywordSample)
 	}
 
 }
@@ -804,7 +841,7 @@ func (p *Parser) parseAlterTableMaterialize(pos Pos) (AlterTableClause, error) {
 	case p.matchKeyword(KeywordProjection):
 		kind = KeywordProjection
 	default:
-		return nil, fmt.Errorf("expected keyword: INDEX|PROJECTION, but got %q", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordIndex, KeywordProjection)
 	}
 	_ = p.lexer.consumeToken()
 
@@ -877,12 +914,21 @@ func (p *Parser) parseAlterTableReset(pos Pos) (AlterTableClause, error) {
 	}, nil
 }
 
-// Syntax: ALTER TABLE DELETE WHERE condition
+// Syntax: ALTER TABLE DELETE [IN PARTITION partition_id] WHERE condition
 func (p *Parser) parseAlterTableDelete(pos Pos) (AlterTableClause, error) {
 	if err := p.expectKeyword(KeywordDelete); err != nil {
 		return nil, err
 	}
 
+	var inPartition *PartitionClause
+	if p.tryConsumeKeywords(KeywordIn) {
+		var err error
+		inPartition, err = p.parsePartitionClause(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := p.expectKeyword(KeywordWhere); err != nil {
 		return nil, err
 	}
@@ -895,6 +941,7 @@ func (p *Parser) parseAlterTableDelete(pos Pos) (AlterTableClause, error) {
 	return &AlterTableDelete{
 		DeletePos:    pos,
 		StatementEnd: whereExpr.End(),
+		InPartition:  inPartition,
 		WhereClause:  whereExpr,
 	}, nil
 }
@@ -972,3 +1019,192 @@ func (p *Parser) parseUpdateAssignment(pos Pos) (*UpdateAssignment, error) {
 		Expr:          expr,
 	}, nil
 }
+
+// Syntax: ALTER TABLE MOVE partitionClause TO DISK|VOLUME 'name' | TO TABLE tableIdentifier
+func (p *Parser) parseAlterTableMovePartition(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordMove); err != nil {
+		return nil, err
+	}
+
+	partition, err := p.parsePartitionClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword(KeywordTo); err != nil {
+		return nil, err
+	}
+
+	movePartition := &AlterTableMovePartition{
+		MovePos:   pos,
+		Partition: partition,
+	}
+	switch {
+	case p.tryConsumeKeywords(KeywordDisk):
+		movePartition.ToDisk, err = p.parseString(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		movePartition.StatementEnd = movePartition.ToDisk.End()
+	case p.tryConsumeKeywords(KeywordVolume):
+		movePartition.ToVolume, err = p.parseString(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		movePartition.StatementEnd = movePartition.ToVolume.End()
+	case p.tryConsumeKeywords(KeywordTable):
+		movePartition.ToTable, err = p.parseTableIdentifier(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		movePartition.StatementEnd = movePartition.ToTable.End()
+	default:
+		return nil, p.expectedKeywordsError(KeywordDisk, KeywordVolume, KeywordTable)
+	}
+	return movePartition, nil
+}
+
+// Syntax: ALTER TABLE FETCH partitionClause FROM 'path-in-zookeeper'
+func (p *Parser) parseAlterTableFetchPartition(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordFetch); err != nil {
+		return nil, err
+	}
+
+	partition, err := p.parsePartitionClause(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword(KeywordFrom); err != nil {
+		return nil, err
+	}
+	from, err := p.parseString(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlterTableFetchPartition{
+		FetchPos:     pos,
+		StatementEnd: from.End(),
+		Partition:    partition,
+		From:         from,
+	}, nil
+}
+
+// Syntax: ALTER TABLE ADD CONSTRAINT (IF NOT EXISTS)? ident CHECK|ASSUME expr
+func (p *Parser) parseAlterTableAddConstraint(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordConstraint); err != nil {
+		return nil, err
+	}
+
+	ifNotExists, err := p.tryParseIfNotExists()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	var assume bool
+	switch {
+	case p.tryConsumeKeywords(KeywordCheck):
+	case p.tryConsumeKeywords(KeywordAssume):
+		assume = true
+	default:
+		return nil, p.expectedKeywordsError(KeywordCheck, KeywordAssume)
+	}
+	expr, err := p.parseExpr(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlterTableAddConstraint{
+		AddPos:       pos,
+		StatementEnd: expr.End(),
+		IfNotExists:  ifNotExists,
+		Name:         name,
+		Assume:       assume,
+		Expr:         expr,
+	}, nil
+}
+
+// Syntax: ALTER TABLE DROP CONSTRAINT (IF EXISTS)? ident
+func (p *Parser) parseAlterTableDropConstraint(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordConstraint); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlterTableDropConstraint{
+		DropPos:      pos,
+		StatementEnd: name.End(),
+		IfExists:     ifExists,
+		Name:         name,
+	}, nil
+}
+
+// Syntax: ALTER TABLE COMMENT COLUMN (IF EXISTS)? nestedIdentifier 'comment'
+func (p *Parser) parseAlterTableCommentColumn(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordComment); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword(KeywordColumn); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.tryParseIfExists()
+	if err != nil {
+		return nil, err
+	}
+	columnName, err := p.ParseNestedIdentifier(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	comment, err := p.parseString(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlterTableCommentColumn{
+		CommentPos:   pos,
+		StatementEnd: comment.End(),
+		IfExists:     ifExists,
+		ColumnName:   columnName,
+		Comment:      comment,
+	}, nil
+}
+
+// Syntax: ALTER TABLE APPLY DELETED MASK (IN partitionClause)?
+func (p *Parser) parseAlterTableApplyDeletedMask(pos Pos) (AlterTableClause, error) {
+	if err := p.expectKeyword(KeywordApply); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword(KeywordDeleted); err != nil {
+		return nil, err
+	}
+	statementEnd := p.End()
+	if err := p.expectKeyword(KeywordMask); err != nil {
+		return nil, err
+	}
+
+	applyDeletedMask := &AlterTableApplyDeletedMask{
+		ApplyPos:     pos,
+		StatementEnd: statementEnd,
+	}
+	if p.tryConsumeKeywords(KeywordIn) {
+		partition, err := p.parsePartitionClause(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		applyDeletedMask.InPartition = partition
+		applyDeletedMask.StatementEnd = partition.End()
+	}
+	return applyDeletedMask, nil
+}
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -165,8 +164,8 @@ func (p *Parser) parseInfix(expr Expr, precedence int) (Expr, error) {
 	case p.matchKeyword(KeywordGlobal):
 		_ = p.lexer.consumeToken()
 		hasNot := p.tryConsumeKeywords(KeywordNot)
-		if p.expectKeyword(KeywordIn) != nil {
-			return nil, fmt.Errorf("expected IN after GLOBAL, got %s", p.currentTokenString())
+		if err := p.expectKeyword(KeywordIn); err != nil {
+			return nil, err
 		}
 
 		rightExpr, err := p.parseSubExpr(p.Pos(), precedence)
@@ -215,7 +214,7 @@ func (p *Parser) parseInfix(expr Expr, precedence int) (Expr, error) {
 		case p.matchKeyword(KeywordLike):
 		case p.matchKeyword(KeywordIlike):
 		default:
-			return nil, fmt.Errorf("expected IN, LIKE, ILIKE or BETWEEN after NOT, got %s", p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordIn, KeywordLike, KeywordIlike, KeywordBetween)
 		}
 		op := p.current().ToString()
 		_ = p.lexer.consumeToken()
@@ -262,7 +261,7 @@ func (p *Parser) parseInfix(expr Expr, precedence int) (Expr, error) {
 			Expr:    expr,
 		}, nil
 	default:
-		return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+		return nil, p.unexpectedTokenError()
 	}
 }
 
@@ -380,7 +379,7 @@ func (p *Parser) parseColumnExtractExpr(pos Pos) (*ExtractExpr, error) {
 	}
 
 	if len(parameters) == 0 {
-		return nil, fmt.Errorf("EXTRACT requires at least one parameter")
+		return nil, p.syntaxError(ErrCodeMissingClause, "EXTRACT requires at least one parameter")
 	}
 
 	extractEnd := p.Pos()
@@ -589,8 +588,12 @@ func (p *Parser) parseColumnExpr(pos Pos) (Expr, error) { //nolint:funlen
 			return p.parseAnyKeyword()
 		}
 
+		// the attempt is speculative, so it must fail rather than recover
 		savedState := p.lexer.saveState()
+		recovering := p.recovering
+		p.recovering = false
 		interval, err := p.parseInterval(true)
+		p.recovering = recovering
 		if err != nil {
 			if p.failedIntervalOffsets == nil {
 				p.failedIntervalOffsets = make(map[Pos]struct{})
@@ -663,7 +666,7 @@ func (p *Parser) parseColumnExpr(pos Pos) (Expr, error) { //nolint:funlen
 			Type:           string(TokenKindQuestionMark),
 		}, nil
 	default:
-		return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+		return nil, p.unexpectedTokenError()
 	}
 }
 
@@ -689,7 +692,7 @@ func (p *Parser) parseColumnCastExpr(pos Pos) (Expr, error) {
 		separator = p.current().String
 		_ = p.lexer.consumeToken()
 	default:
-		return nil, fmt.Errorf("expected AS or , but got %s", p.currentTokenKind())
+		return nil, p.expectedError([]TokenKind{TokenKindComma}, []string{KeywordAs})
 	}
 
 	var asColumnType Expr
@@ -797,7 +800,7 @@ func (p *Parser) parseInterval(requireKeyword bool) (*IntervalExpr, error) {
 		return nil, err
 	}
 	if !intervalUnits.Contains(strings.ToUpper(unit.Name)) {
-		return nil, fmt.Errorf("unknown interval type: <%q>", unit.Name)
+		return nil, errorAt(unit.Pos(), ErrCodeInvalidLiteral, "unknown interval type: <%q>", unit.Name)
 	}
 	return &IntervalExpr{
 		IntervalPos: intervalPos,
@@ -923,12 +926,11 @@ func (p *Parser) parseKeywordArgItem(form keywordArgForm, slot int, allowSeparat
 	}
 
 	if form.RequireModifier && !hasModifier {
-		return nil, 0, fmt.Errorf("expected one of %s before %s",
-			strings.Join(form.Modifiers, ", "), form.Separators[0])
+		return nil, 0, p.expectedKeywordsError(form.Modifiers...)
 	}
 
 	if slot+consumed < form.RequiredSeparators {
-		return nil, 0, fmt.Errorf("expected %s", form.Separators[slot+consumed])
+		return nil, 0, p.expectedKeywordsError(form.Separators[slot+consumed])
 	}
 
 	return expr, consumed, nil
@@ -995,7 +997,8 @@ func (p *Parser) parseKeywordArgFunctionParams(pos Pos, form keywordArgForm) (*P
 		}
 
 		items = append(items, &ColumnExpr{Expr: item, Alias: alias})
-		if p.tryConsumeTokenKind(TokenKindComma) == nil {
+		comma := p.tryConsumeTokenKind(TokenKindComma)
+		if comma == nil {
 			break
 		}
 
@@ -1003,7 +1006,12 @@ func (p *Parser) parseKeywordArgFunctionParams(pos Pos, form keywordArgForm) (*P
 		slot++
 
 		if usedSeparator && (form.ClosedKeywordForm || (form.MaxSlots > 0 && slot >= form.MaxSlots)) {
-			return nil, fmt.Errorf("expected ')', but got ','")
+			return nil, &ParseError{
+				Code:     ErrCodeExpectedToken,
+				Pos:      comma.Pos,
+				Got:      comma,
+				Expected: []TokenKind{TokenKindRParen},
+			}
 		}
 	}
 
@@ -1185,7 +1193,7 @@ func (p *Parser) parseSelectItem() (*SelectItem, error) {
 
 	modifiers := make([]*FunctionExpr, 0)
 	for {
-		if p.matchKeyword(KeywordExcept) || p.matchKeyword(KeywordApply) || p.matchKeyword(KeywordReplace) {
+		if (p.matchKeyword(KeywordExcept) && !p.peekExceptSetOperation()) || p.matchKeyword(KeywordApply) || p.matchKeyword(KeywordReplace) {
 			modifier, err := p.parseFunctionExpr(p.Pos())
 			if err != nil {
 				return nil, err
@@ -1285,6 +1293,22 @@ func (p *Parser) parseColumnCaseExpr(pos Pos) (*CaseExpr, error) {
 	return caseExpr, nil
 }
 
+// ParseColumnType parses the whole input as a single column type, such as
+// Array(Nullable(String)).
+func (p *Parser) ParseColumnType() (ColumnType, error) {
+	if err := p.lexer.consumeToken(); err != nil {
+		return nil, p.wrapError(err)
+	}
+	columnType, err := p.parseColumnType(p.Pos())
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+	if p.current() != nil {
+		return nil, p.wrapError(p.expectedTokenError(TokenKindEOF))
+	}
+	return columnType, nil
+}
+
 func (p *Parser) parseColumnType(_ Pos) (ColumnType, error) {
 	ident, err := p.parseIdent()
 	if err != nil {
@@ -1329,7 +1353,7 @@ func (p *Parser) parseColumnTypeArgs(ident *Ident) (ColumnType, error) { // noli
 				RightParenPos: rightParenPos,
 			}, nil
 		default:
-			return nil, fmt.Errorf("unexpected token kind: %v", p.currentTokenKind())
+			return nil, p.unexpectedTokenError()
 		}
 	}
 	return &ScalarType{Name: ident}, nil
@@ -1468,7 +1492,7 @@ func (p *Parser) parseJSONMaxDynamicOptions(pos Pos) (*JSONOption, error) {
 		}
 		return &JSONOption{MaxDynamicPaths: number}, nil
 	default:
-		return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+		return nil, p.unexpectedTokenError()
 	}
 }
 
@@ -1511,7 +1535,7 @@ func (p *Parser) parseJSONOption() (*JSONOption, error) {
 			// Reconstruct handling similar to parseJSONMaxDynamicOptions but we already consumed ident and '='
 			// Determine which option based on the first ident name
 			if len(path.Idents) != 1 {
-				return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+				return nil, p.unexpectedTokenError()
 			}
 			name := path.Idents[0].Name
 			switch name {
@@ -1528,7 +1552,7 @@ func (p *Parser) parseJSONOption() (*JSONOption, error) {
 				}
 				return &JSONOption{MaxDynamicPaths: number}, nil
 			default:
-				return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+				return nil, p.unexpectedTokenError()
 			}
 		}
 		// Otherwise, expect a ColumnType as a type hint for the JSON subpath
@@ -1538,7 +1562,7 @@ func (p *Parser) parseJSONOption() (*JSONOption, error) {
 		}
 		return &JSONOption{Column: &JSONTypeHint{Path: path, Type: colType}}, nil
 	default:
-		return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+		return nil, p.unexpectedTokenError()
 	}
 }
 
@@ -1610,7 +1634,7 @@ func (p *Parser) parseNestedTypeFields() ([]Expr, error) {
 		// Cases like `Tuple(Int, String)`
 		return p.parseNestedTypeFieldsWithoutNames(ident)
 	default:
-		return nil, fmt.Errorf("unexpected token kind: %s", p.currentTokenKind())
+		return nil, p.unexpectedTokenError()
 	}
 }
 
@@ -2,7 +2,6 @@ package parser
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -10,11 +9,25 @@ type Parser struct {
 	lexer *Lexer
 	lines lineStarts // lazily built on the first error, for position lookup
 
+	commentMap CommentMap // built by ParseStmts when comments are kept
+
 	// failedIntervalOffsets records the positions of INTERVAL tokens whose
 	// operator reading already failed, so parseColumnExpr retries each
 	// position at most once. See the KeywordInterval case there for why the
 	// memo is sound and what it prevents.
 	failedIntervalOffsets map[Pos]struct{}
+
+	// recovering is set by ParseStmtsWithRecovery: SELECT clauses that fail
+	// to parse are recorded in errors and skipped instead of failing.
+	recovering bool
+	errors     []*ParseError
+	// stmtPos is the start of the statement being parsed while recovering.
+	stmtPos Pos
+
+	// nameHints is set by WithNameHints: format and engine names that are
+	// near misses of known ones are recorded in hints.
+	nameHints bool
+	hints     []*ParseError
 }
 
 // lineStarts returns the line-start offsets for the input, building them on
@@ -101,11 +114,7 @@ func (p *Parser) expectTokenKind(kind TokenKind) error {
 	if curToken := p.tryConsumeTokenKind(kind); curToken != nil {
 		return nil
 	}
-	return &ParseError{
-		Pos:      p.Pos(),
-		Got:      p.current(),
-		Expected: []TokenKind{kind},
-	}
+	return p.expectedTokenError(kind)
 }
 
 func (p *Parser) tryConsumeTokenKind(kind TokenKind) *Token {
@@ -118,6 +127,12 @@ func (p *Parser) tryConsumeTokenKind(kind TokenKind) *Token {
 }
 
 func (p *Parser) matchKeyword(keyword string) bool {
+	if contextKeywords.Contains(keyword) {
+		// the lexer reads a context keyword as an identifier
+		token := p.current()
+		return token != nil && token.Kind == TokenKindIdent && token.QuoteType == Unquoted &&
+			strings.EqualFold(token.String, keyword)
+	}
 	return p.matchTokenKind(TokenKindKeyword) && strings.EqualFold(p.current().String, keyword)
 }
 
@@ -132,11 +147,7 @@ func (p *Parser) matchOneOfKeywords(keywords ...string) bool {
 
 func (p *Parser) expectKeyword(keyword string) error {
 	if !p.matchKeyword(keyword) {
-		return &ParseError{
-			Pos:     p.Pos(),
-			Got:     p.current(),
-			Keyword: keyword,
-		}
+		return p.expectedKeywordsError(keyword)
 	}
 	_ = p.lexer.consumeToken()
 	return nil
@@ -176,11 +187,7 @@ func (p *Parser) tryParseIdent() *Ident {
 func (p *Parser) parseAnyKeyword() (*Ident, error) {
 	last := p.current()
 	if !p.matchTokenKind(TokenKindIdent, TokenKindKeyword) {
-		return nil, &ParseError{
-			Pos:      p.Pos(),
-			Got:      last,
-			Expected: []TokenKind{TokenKindIdent},
-		}
+		return nil, p.expectedTokenError(TokenKindIdent)
 	}
 	_ = p.lexer.consumeToken()
 	return &Ident{
@@ -218,7 +225,7 @@ func (p *Parser) parseIdentOrStar() (*Ident, error) {
 			Name:    curToken.String,
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected <ident> or *, but got %q", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindMul)
 	}
 }
 
@@ -236,7 +243,7 @@ func (p *Parser) parseIdentOrString() (*Ident, error) {
 			QuoteType: SingleQuote, // Treat string literals as single-quoted identifiers
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected <ident> or <string>, but got %q", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindString)
 	}
 }
 
@@ -342,7 +349,7 @@ func (p *Parser) parseDecimal(pos Pos) (*NumberLiteral, error) {
 		return nil, err
 	}
 	if number.Base != 10 {
-		return nil, fmt.Errorf("invalid decimal literal: %q", number.Literal)
+		return nil, errorAt(number.Pos(), ErrCodeInvalidLiteral, "invalid decimal literal: %q", number.Literal)
 	}
 	return number, nil
 }
@@ -363,12 +370,12 @@ func (p *Parser) parseNumber(pos Pos) (*NumberLiteral, error) {
 			return nil, err
 		}
 		if curToken.Base != 10 {
-			return nil, fmt.Errorf("invalid decimal literal: %q", curToken.String)
+			return nil, errorAt(curToken.Pos, ErrCodeInvalidLiteral, "invalid decimal literal: %q", curToken.String)
 		}
 		curToken.String = "." + curToken.String
 		curToken.Kind = TokenKindFloat
 	default:
-		return nil, fmt.Errorf("expected <int> or <float>, but got %q", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindInt, TokenKindFloat)
 	}
 	if err != nil {
 		return nil, err
@@ -408,7 +415,7 @@ func (p *Parser) parseLiteral(pos Pos) (Literal, error) {
 		// accept the NULL keyword
 		return &NullLiteral{NullPos: pos}, nil
 	default:
-		return nil, fmt.Errorf("expected <int>, <string>, <ident> or keyword <NULL>, but got %q", p.currentTokenKind())
+		return nil, p.expectedError([]TokenKind{TokenKindInt, TokenKindString, TokenKindIdent}, []string{KeywordNull})
 	}
 }
 
@@ -447,6 +454,7 @@ func (p *Parser) parseFormat(pos Pos) (*FormatClause, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.checkKnownName(formatIdent, "format", knownFormats)
 	return &FormatClause{
 		FormatPos: pos,
 		Format:    formatIdent,
@@ -456,21 +464,29 @@ func (p *Parser) parseFormat(pos Pos) (*FormatClause, error) {
 // wrapError finalizes a parse error: it ensures the error is a *ParseError with
 // line/column resolved and the input attached for caret rendering. Errors that
 // already originate as *ParseError (from the expect* helpers) keep their
-// captured position and expected-token information; the long tail of
-// fmt.Errorf sites is wrapped here with the current position.
+// captured position and expected-token information; any other error, which
+// can only come from outside the parser, is wrapped here with the current
+// position. Errors built without a code get one inferred from their fields.
 func (p *Parser) wrapError(err error) error {
 	if err == nil {
 		return nil
 	}
 
 	var pe *ParseError
+	if errors.As(err, &pe) && pe.Got == nil && p.lexer.err != nil {
+		// the input ended early because it could not be tokenized
+		err = p.lexer.err
+	}
 	if !errors.As(err, &pe) {
 		pe = &ParseError{
-			Pos: p.Pos(),
-			Got: p.current(),
-			Msg: err.Error(),
+			Code: ErrCodeSyntax,
+			Pos:  p.Pos(),
+			Got:  p.current(),
+			Msg:  err.Error(),
 		}
 	}
+	pe.Code = pe.errorCode()
+	p.addSuggestions(pe)
 	if pe.Line == 0 {
 		pe.Line, pe.Column = p.lineStarts().position(int(pe.Pos))
 	}
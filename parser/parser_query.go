@@ -1,11 +1,8 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
 	"strings"
-
-	"slices"
 )
 
 func (p *Parser) tryParseWithClause(pos Pos) (*WithClause, error) {
@@ -169,82 +166,85 @@ func (p *Parser) tryParseJoinConstraints(pos Pos) (Expr, error) {
 	return nil, nil
 }
 
-func (p *Parser) parseJoinType(_ Pos) []string {
-	var modifiers []string
-	switch {
-	case p.tryConsumeKeywords(KeywordCross): // cross join
-		modifiers = append(modifiers, KeywordCross)
-	case p.matchKeyword(KeywordAny), p.matchKeyword(KeywordAll):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordFull) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-		if p.matchKeyword(KeywordLeft) || p.matchKeyword(KeywordRight) || p.matchKeyword(KeywordInner) || p.matchKeyword(KeywordOuter) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-	case p.matchKeyword(KeywordSemi), p.matchKeyword(KeywordAsof):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordLeft) || p.matchKeyword(KeywordRight) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-		if p.matchKeyword(KeywordOuter) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-	case p.matchKeyword(KeywordInner):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordAll) || p.matchKeyword(KeywordAny) || p.matchKeyword(KeywordAsof) || p.matchKeyword(KeywordArray) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-	case p.matchKeyword(KeywordLeft):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordOuter) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-		if p.matchKeyword(KeywordSemi) || p.matchKeyword(KeywordAnti) ||
-			p.matchKeyword(KeywordAny) || p.matchKeyword(KeywordAll) ||
-			p.matchKeyword(KeywordAsof) || p.matchKeyword(KeywordArray) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-	case p.matchKeyword(KeywordRight):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordOuter) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-		if p.matchKeyword(KeywordSemi) || p.matchKeyword(KeywordAnti) ||
-			p.matchKeyword(KeywordAny) || p.matchKeyword(KeywordAll) ||
-			p.matchKeyword(KeywordAsof) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
-		}
-	case p.matchKeyword(KeywordFull):
-		modifiers = append(modifiers, p.current().String)
-		_ = p.lexer.consumeToken()
-		if p.matchKeyword(KeywordOuter) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
+// joinType collects the keywords preceding JOIN, both in their source spelling
+// and typed.
+type joinType struct {
+	modifiers  []string
+	locality   JoinLocality
+	strictness JoinStrictness
+	kind       JoinKind
+	hasOuter   bool
+	isArray    bool
+}
+
+// parseJoinType parses the strictness, kind, OUTER and ARRAY keywords of a
+// join in any order, rejecting repeated and impossible combinations.
+func (p *Parser) parseJoinType(_ Pos) (*joinType, error) {
+	jt := &joinType{}
+	var strictnessToken, kindToken, outerToken, arrayToken *Token
+	for p.matchTokenKind(TokenKindKeyword) || p.matchPasteJoin() {
+		token := p.current()
+		var seen *Token
+		switch keyword := strings.ToUpper(token.String); keyword {
+		case KeywordAll, KeywordAny, KeywordAsof, KeywordSemi, KeywordAnti:
+			seen, strictnessToken, jt.strictness = strictnessToken, token, JoinStrictness(keyword)
+		case KeywordInner, KeywordLeft, KeywordRight, KeywordFull, KeywordCross, KeywordPaste:
+			seen, kindToken, jt.kind = kindToken, token, JoinKind(keyword)
+		case KeywordOuter:
+			seen, outerToken, jt.hasOuter = outerToken, token, true
+		case KeywordArray:
+			seen, arrayToken, jt.isArray = arrayToken, token, true
+		default:
+			return jt, p.validateJoinType(jt, strictnessToken, kindToken, outerToken)
 		}
-		if p.matchKeyword(KeywordAll) || p.matchKeyword(KeywordAny) {
-			modifiers = append(modifiers, p.current().String)
-			_ = p.lexer.consumeToken()
+		if seen != nil {
+			return nil, joinTypeError(token, "%s cannot follow %s in a join", token.String, seen.String)
 		}
-	case p.matchKeyword(KeywordArray):
-		modifiers = append(modifiers, p.current().String)
+		jt.modifiers = append(jt.modifiers, token.String)
 		_ = p.lexer.consumeToken()
 	}
-	return modifiers
+	return jt, p.validateJoinType(jt, strictnessToken, kindToken, outerToken)
+}
+
+// matchPasteJoin reports whether the current token is PASTE followed by JOIN.
+// PASTE is a context keyword, so anywhere else it is a name, such as the
+// table alias in `FROM t paste`.
+func (p *Parser) matchPasteJoin() bool {
+	if !p.matchKeyword(KeywordPaste) {
+		return false
+	}
+	savedState := p.lexer.saveState()
+	defer p.lexer.restoreState(savedState)
+	_ = p.lexer.consumeToken()
+	return p.matchKeyword(KeywordJoin)
+}
+
+func (p *Parser) validateJoinType(jt *joinType, strictnessToken, kindToken, outerToken *Token) error {
+	switch {
+	case jt.isArray && strictnessToken != nil:
+		return joinTypeError(strictnessToken, "%s cannot be combined with ARRAY JOIN", strictnessToken.String)
+	case jt.isArray && jt.kind != JoinKindNone && jt.kind != JoinKindInner && jt.kind != JoinKindLeft:
+		return joinTypeError(kindToken, "%s cannot be combined with ARRAY JOIN", kindToken.String)
+	case jt.hasOuter && jt.kind != JoinKindLeft && jt.kind != JoinKindRight && jt.kind != JoinKindFull:
+		return joinTypeError(outerToken, "OUTER requires a LEFT, RIGHT or FULL join")
+	case strictnessToken != nil && (jt.kind == JoinKindCross || jt.kind == JoinKindPaste):
+		return joinTypeError(strictnessToken, "%s cannot be combined with %s JOIN", strictnessToken.String, kindToken.String)
+	case (jt.strictness == JoinStrictnessSemi || jt.strictness == JoinStrictnessAnti) &&
+		jt.kind != JoinKindLeft && jt.kind != JoinKindRight:
+		return joinTypeError(strictnessToken, "%s JOIN requires LEFT or RIGHT", strictnessToken.String)
+	case jt.strictness == JoinStrictnessAsof && jt.kind != JoinKindNone && jt.kind != JoinKindInner && jt.kind != JoinKindLeft:
+		return joinTypeError(strictnessToken, "%s JOIN cannot be combined with %s", strictnessToken.String, kindToken.String)
+	}
+	return nil
+}
+
+func joinTypeError(token *Token, format string, args ...any) *ParseError {
+	return &ParseError{
+		Code: ErrCodeInvalidCombination,
+		Pos:  token.Pos,
+		Got:  token,
+		Msg:  fmt.Sprintf(format, args...),
+	}
 }
 
 func (p *Parser) parseJoinTableExpr(_ Pos) (Expr, error) {
@@ -276,7 +276,7 @@ func (p *Parser) parseJoinTableExpr(_ Pos) (Expr, error) {
 			StatementEnd: statementEnd,
 		}, nil
 	default:
-		return nil, fmt.Errorf("expected table name or subquery, got %s", fmt.Sprintf("%v", p.currentTokenKind()))
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindString, TokenKindLParen)
 	}
 }
 
@@ -284,35 +284,39 @@ func (p *Parser) parseJoinTableExpr(_ Pos) (Expr, error) {
 // GLOBAL/LOCAL locality followed by the join type. When a consumed locality
 // turns out not to precede a join, it rewinds the lexer and returns nil,
 // leaving the keyword for the caller to reject.
-func (p *Parser) parseJoinModifiers(pos Pos) ([]string, error) {
+func (p *Parser) parseJoinModifiers(pos Pos) (*joinType, error) {
 	if !p.matchOneOfKeywords(KeywordGlobal, KeywordLocal) {
-		return p.parseJoinType(pos), nil
+		return p.parseJoinType(pos)
 	}
 
 	savedState := p.lexer.saveState()
 	locality := p.current()
 	_ = p.lexer.consumeToken()
 
-	joinType := p.parseJoinType(p.Pos())
-	if len(joinType) == 0 && !p.matchKeyword(KeywordJoin) {
+	jt, err := p.parseJoinType(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if len(jt.modifiers) == 0 && !p.matchKeyword(KeywordJoin) {
 		p.lexer.restoreState(savedState)
 		return nil, nil
 	}
 
 	// ARRAY JOIN reads a column list rather than a distributed table, so it has
-	// no locality. Modifiers keep their source spelling, hence EqualFold.
-	if slices.ContainsFunc(joinType, func(modifier string) bool {
-		return strings.EqualFold(modifier, KeywordArray)
-	}) {
+	// no locality.
+	if jt.isArray {
 		// point at the locality, not at wherever the join op stopped
 		return nil, &ParseError{
-			Pos: locality.Pos,
-			Got: locality,
-			Msg: fmt.Sprintf("%s cannot be combined with ARRAY JOIN", locality.String),
+			Code: ErrCodeInvalidCombination,
+			Pos:  locality.Pos,
+			Got:  locality,
+			Msg:  fmt.Sprintf("%s cannot be combined with ARRAY JOIN", locality.String),
 		}
 	}
 
-	return append([]string{locality.String}, joinType...), nil
+	jt.locality = JoinLocality(strings.ToUpper(locality.String))
+	jt.modifiers = append([]string{locality.String}, jt.modifiers...)
+	return jt, nil
 }
 
 // peekJoinAfterLocality reports whether the current GLOBAL/LOCAL keyword is
@@ -322,84 +326,95 @@ func (p *Parser) peekJoinAfterLocality() bool {
 	savedState := p.lexer.saveState()
 	defer p.lexer.restoreState(savedState)
 
-	modifiers, err := p.parseJoinModifiers(p.Pos())
+	jt, err := p.parseJoinModifiers(p.Pos())
 	if err != nil {
 		// A malformed locality join such as GLOBAL ARRAY JOIN still belongs
 		// to the FROM clause, which reports the error.
 		return true
 	}
 
-	return modifiers != nil && p.matchKeyword(KeywordJoin)
+	return jt != nil && p.matchKeyword(KeywordJoin)
 }
 
 func (p *Parser) parseJoinRightExpr(pos Pos) (expr Expr, err error) {
-	var rightExpr Expr
-	var modifiers []string
-	switch {
-	case p.tryConsumeTokenKind(TokenKindComma) != nil:
-		return p.parseJoinExpr(p.Pos())
-	default:
-		// GLOBAL/LOCAL only says how the right-hand table is distributed, so
-		// the join type still follows it: `GLOBAL LEFT JOIN` is a LEFT join.
-		modifiers, err = p.parseJoinModifiers(p.Pos())
+	if p.tryConsumeTokenKind(TokenKindComma) != nil {
+		expr, err = p.parseJoinExpr(p.Pos())
 		if err != nil {
 			return nil, err
 		}
+		// a comma join is a cross join, so ON or USING cannot follow it
+		if p.matchOneOfKeywords(KeywordOn, KeywordUsing) {
+			return nil, joinTypeError(p.current(), "%s cannot be combined with a comma join", strings.ToUpper(p.current().String))
+		}
+		joinExpr, ok := expr.(*JoinExpr)
+		if !ok {
+			joinExpr = &JoinExpr{JoinPos: expr.Pos(), Left: expr}
+		}
+		joinExpr.Kind = JoinKindComma
+		return joinExpr, nil
+	}
+
+	// GLOBAL/LOCAL only says how the right-hand table is distributed, so
+	// the join type still follows it: `GLOBAL LEFT JOIN` is a LEFT join.
+	jt, err := p.parseJoinModifiers(p.Pos())
+	if err != nil {
+		return nil, err
+	}
+	if jt == nil {
+		return nil, nil
 	}
 
-	if len(modifiers) != 0 && !p.matchKeyword(KeywordJoin) {
-		return nil, fmt.Errorf("expected JOIN, got %s", p.currentTokenKind())
+	if len(jt.modifiers) != 0 && !p.matchKeyword(KeywordJoin) {
+		return nil, p.expectedKeywordsError(KeywordJoin)
 	}
 	if !p.tryConsumeKeywords(KeywordJoin) {
 		return nil, nil
 	}
 
-	modifiers = append(modifiers, KeywordJoin)
+	joinExpr := &JoinExpr{
+		JoinPos:    pos,
+		Modifiers:  append(jt.modifiers, KeywordJoin),
+		Locality:   jt.locality,
+		Strictness: jt.strictness,
+		Kind:       jt.kind,
+		HasOuter:   jt.hasOuter,
+		IsArray:    jt.isArray,
+	}
 
-	// Check if this is an ARRAY JOIN
-	if slices.Contains(modifiers, KeywordArray) {
-		// For ARRAY JOIN, parse column expression list instead of table expression
-		expr, err = p.parseColumnExprList(p.Pos())
+	if jt.isArray {
+		// ARRAY JOIN reads a column expression list instead of a table
+		// expression, and has no ON/USING constraints
+		joinExpr.Left, err = p.parseColumnExprList(p.Pos())
 		if err != nil {
 			return nil, err
 		}
-
-		// ARRAY JOIN doesn't have constraints (ON/USING)
-		// try parse next join
-		rightExpr, err = p.parseJoinRightExpr(p.Pos())
+	} else {
+		joinExpr.Left, err = p.parseJoinTableExpr(p.Pos())
 		if err != nil {
 			return nil, err
 		}
-		return &JoinExpr{
-			JoinPos:     pos,
-			Left:        expr,
-			Right:       rightExpr,
-			Modifiers:   modifiers,
-			Constraints: nil,
-		}, nil
-	}
-
-	expr, err = p.parseJoinTableExpr(p.Pos())
-	if err != nil {
-		return nil, err
-	}
-	constrains, err := p.tryParseJoinConstraints(p.Pos())
-	if err != nil {
-		return nil, err
+		// CROSS and PASTE joins match rows without a condition
+		if (jt.kind == JoinKindCross || jt.kind == JoinKindPaste) && p.matchOneOfKeywords(KeywordOn, KeywordUsing) {
+			return nil, joinTypeError(p.current(), "%s cannot be combined with %s JOIN", strings.ToUpper(p.current().String), jt.kind)
+		}
+		joinExpr.Constraints, err = p.tryParseJoinConstraints(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		switch joinExpr.Constraints.(type) {
+		case *OnClause:
+			joinExpr.ConstraintType = JoinConstraintOn
+		case *UsingClause:
+			joinExpr.ConstraintType = JoinConstraintUsing
+		}
 	}
 
 	// try parse next join
-	rightExpr, err = p.parseJoinRightExpr(p.Pos())
+	joinExpr.Right, err = p.parseJoinRightExpr(p.Pos())
 	if err != nil {
 		return nil, err
 	}
-	return &JoinExpr{
-		JoinPos:     pos,
-		Left:        expr,
-		Right:       rightExpr,
-		Modifiers:   modifiers,
-		Constraints: constrains,
-	}, nil
+	return joinExpr, nil
 }
 
 func (p *Parser) parseJoinExpr(pos Pos) (expr Expr, err error) {
@@ -447,7 +462,7 @@ func (p *Parser) parseTableExpr(pos Pos) (*TableExpr, error) {
 	case p.matchTokenKind(TokenKindLParen):
 		expr, err = p.parseSubQuery(p.Pos())
 	default:
-		return nil, errors.New("expect table name or subquery")
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindLParen)
 	}
 	if err != nil {
 		return nil, err
@@ -467,7 +482,7 @@ func (p *Parser) parseTableExpr(pos Pos) (*TableExpr, error) {
 			Alias:    alias,
 		}
 		tableEnd = expr.End()
-	} else if p.matchTokenKind(TokenKindIdent) && p.currentTokenKind() != TokenKindKeyword {
+	} else if p.matchTokenKind(TokenKindIdent) && p.currentTokenKind() != TokenKindKeyword && !p.matchPasteJoin() {
 		alias, err := p.parseIdent()
 		if err != nil {
 			return nil, err
@@ -481,12 +496,13 @@ func (p *Parser) parseTableExpr(pos Pos) (*TableExpr, error) {
 	}
 
 	isFinalExist := false
+	finalPos := p.Pos()
 	if p.tryConsumeKeywords(KeywordFinal) {
 		switch expr.(type) {
 		case *TableFunctionExpr:
-			return nil, errors.New("table function doesn't support FINAL")
-		case *SelectQuery:
-			return nil, errors.New("subquery doesn't support FINAL")
+			return nil, errorAt(finalPos, ErrCodeInvalidCombination, "table function doesn't support FINAL")
+		case *SelectQuery, *SetOperationExpr:
+			return nil, errorAt(finalPos, ErrCodeInvalidCombination, "subquery doesn't support FINAL")
 		}
 		isFinalExist = true
 		tableEnd = expr.End()
@@ -605,7 +621,7 @@ func (p *Parser) parseGroupByClause(pos Pos) (*GroupByClause, error) {
 		case p.tryConsumeKeywords(KeywordTotals):
 			groupBy.WithTotals = true
 		default:
-			return nil, fmt.Errorf("expected CUBE, ROLLUP or TOTALS, got %s", p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordCube, KeywordRollup, KeywordTotals)
 		}
 		groupBy.GroupByEnd = keywordEnd
 	}
@@ -728,7 +744,7 @@ func (p *Parser) parseWindowFrameClause(pos Pos) (*WindowFrameClause, error) {
 		windowFrameType = p.current().String
 		_ = p.lexer.consumeToken()
 	} else {
-		return nil, fmt.Errorf("expected ROWS or RANGE for window frame")
+		return nil, p.expectedKeywordsError(KeywordRows, KeywordRange)
 	}
 
 	var expr Expr
@@ -780,7 +796,7 @@ func (p *Parser) parseFrameExtent() (Expr, error) {
 	case p.matchKeyword(KeywordInterval):
 		return p.parseFrameInterval()
 	default:
-		return nil, fmt.Errorf("expected UNBOUNDED, CURRENT ROW, integer, parameter, or interval")
+		return nil, p.expectedError([]TokenKind{TokenKindInt, TokenKindLBrace}, []string{KeywordUnbounded, KeywordCurrent + " " + KeywordRow, KeywordInterval})
 	}
 }
 
@@ -869,13 +885,13 @@ func (p *Parser) parseFrameDirection() (string, error) {
 		_ = p.lexer.consumeToken()
 		return direction, nil
 	default:
-		return "", fmt.Errorf("expected PRECEDING or FOLLOWING, got %s", p.currentTokenKind())
+		return "", p.expectedKeywordsError(KeywordPreceding, KeywordFollowing)
 	}
 }
 
 func (p *Parser) parseFrameDirectionWithEnd() (string, Pos, error) {
 	if !p.matchKeyword(KeywordPreceding) && !p.matchKeyword(KeywordFollowing) {
-		return "", 0, fmt.Errorf("expected PRECEDING or FOLLOWING, got %s", p.currentTokenKind())
+		return "", 0, p.expectedKeywordsError(KeywordPreceding, KeywordFollowing)
 	}
 	endPos := p.End()
 	direction := p.current().String
@@ -1048,53 +1064,112 @@ func (p *Parser) parseSubQuery(_ Pos) (*SubQuery, error) {
 	}, nil
 }
 
-func (p *Parser) parseSelectQuery(_ Pos) (*SelectQuery, error) {
+// parseSelectQuery parses a SELECT statement, optionally combined with others
+// by UNION, EXCEPT or INTERSECT. It returns a *SelectQuery for a single
+// statement and a *SetOperationExpr otherwise.
+func (p *Parser) parseSelectQuery(_ Pos) (Expr, error) {
 	if !p.matchKeyword(KeywordSelect) && !p.matchKeyword(KeywordWith) && !p.matchTokenKind(TokenKindLParen) {
-		return nil, fmt.Errorf("expected SELECT, WITH or (, got %s", p.currentTokenKind())
+		return nil, p.expectedError([]TokenKind{TokenKindLParen}, []string{KeywordSelect, KeywordWith})
 	}
 
-	hasParen := p.tryConsumeTokenKind(TokenKindLParen) != nil
-	selectStmt, err := p.parseSelectStmt(p.Pos())
+	expr, err := p.parseSetOperation(0)
 	if err != nil {
 		return nil, err
 	}
-	switch {
-	case p.tryConsumeKeywords(KeywordUnion):
+	// A fully parenthesized query is returned as the query itself so that
+	// callers wrapping it in their own parentheses keep working as before.
+	if subQuery, ok := expr.(*SubQuery); ok {
+		return subQuery.Select, nil
+	}
+	return expr, nil
+}
+
+// parseSetOperation parses set operations whose operator binds at least as
+// tightly as minPrecedence, by precedence climbing.
+func (p *Parser) parseSetOperation(minPrecedence int) (Expr, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator, precedence := p.peekSetOperator()
+		if operator == "" || precedence < minPrecedence {
+			return left, nil
+		}
+		operatorPos := p.Pos()
+		_ = p.lexer.consumeToken()
+
+		modifier := SetModifierNone
 		switch {
 		case p.tryConsumeKeywords(KeywordAll):
-			unionAllExpr, err := p.parseSelectQuery(p.Pos())
-			if err != nil {
-				return nil, err
-			}
-			selectStmt.UnionAll = unionAllExpr
+			modifier = SetModifierAll
 		case p.tryConsumeKeywords(KeywordDistinct):
-			unionDistinctExpr, err := p.parseSelectQuery(p.Pos())
-			if err != nil {
-				return nil, err
-			}
-			selectStmt.UnionDistinct = unionDistinctExpr
-		default:
-			return nil, fmt.Errorf("expected ALL or DISTINCT, got %s", p.currentTokenKind())
+			modifier = SetModifierDistinct
 		}
-	case p.tryConsumeKeywords(KeywordExcept):
-		exceptExpr, err := p.parseSelectQuery(p.Pos())
+
+		right, err := p.parseSetOperation(precedence + 1)
 		if err != nil {
 			return nil, err
 		}
-		selectStmt.Except = exceptExpr
-	case p.tryConsumeKeywords(KeywordIntersect):
-		intersectExpr, err := p.parseSelectQuery(p.Pos())
-		if err != nil {
-			return nil, err
+		left = &SetOperationExpr{
+			Left:        left,
+			OperatorPos: operatorPos,
+			Operator:    operator,
+			Modifier:    modifier,
+			Right:       right,
 		}
-		selectStmt.Intersect = intersectExpr
 	}
-	if hasParen {
-		if err := p.expectTokenKind(TokenKindRParen); err != nil {
-			return nil, err
-		}
+}
+
+func (p *Parser) peekSetOperator() (SetOperator, int) {
+	switch {
+	case p.matchKeyword(KeywordUnion):
+		return SetOperatorUnion, 1
+	case p.matchKeyword(KeywordExcept):
+		return SetOperatorExcept, 1
+	case p.matchKeyword(KeywordIntersect):
+		return SetOperatorIntersect, 2
+	}
+	return "", 0
+}
+
+// peekExceptSetOperation reports whether the current EXCEPT keyword starts a
+// set operation rather than a column transformer such as `* EXCEPT (a)`,
+// leaving the lexer where it found it.
+func (p *Parser) peekExceptSetOperation() bool {
+	savedState := p.lexer.saveState()
+	defer p.lexer.restoreState(savedState)
+
+	if !p.tryConsumeKeywords(KeywordExcept) {
+		return false
+	}
+	_ = p.tryConsumeKeywords(KeywordAll) || p.tryConsumeKeywords(KeywordDistinct)
+	for p.tryConsumeTokenKind(TokenKindLParen) != nil {
+		// operands may be parenthesized
+	}
+	return p.matchKeyword(KeywordSelect) || p.matchKeyword(KeywordWith)
+}
+
+// parseSetOperand parses a single SELECT statement or a parenthesized query,
+// which may carry its own ORDER BY and LIMIT.
+func (p *Parser) parseSetOperand() (Expr, error) {
+	if p.tryConsumeTokenKind(TokenKindLParen) == nil {
+		return p.parseSelectStmt(p.Pos())
+	}
+	inner, err := p.parseSetOperation(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectTokenKind(TokenKindRParen); err != nil {
+		return nil, err
+	}
+	if subQuery, ok := inner.(*SubQuery); ok {
+		inner = subQuery.Select
 	}
-	return selectStmt, nil
+	return &SubQuery{
+		HasParen: true,
+		Select:   inner,
+	}, nil
 }
 
 func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: funlen
@@ -1117,7 +1192,7 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 		return nil, err
 	}
 	selectItems, err := p.parseSelectItems()
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 
@@ -1126,7 +1201,7 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 		statementEnd = selectItems[len(selectItems)-1].End()
 	}
 	from, err := p.tryParseFromClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 
@@ -1134,21 +1209,21 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 		statementEnd = from.End()
 	}
 	prewhere, err := p.tryParsePrewhereClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if prewhere != nil {
 		statementEnd = prewhere.End()
 	}
 	where, err := p.tryParseWhereClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if where != nil {
 		statementEnd = where.End()
 	}
 	groupBy, err := p.tryParseGroupByClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if groupBy != nil {
@@ -1160,27 +1235,30 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 		// expectKeyword consumes it
 		totalsEnd := p.End()
 		if err := p.expectKeyword(KeywordTotals); err != nil {
-			return nil, err
+			if !p.recoverClause(pos, err) {
+				return nil, err
+			}
+		} else {
+			withTotal = true
+			statementEnd = totalsEnd
 		}
-		withTotal = true
-		statementEnd = totalsEnd
 	}
 	having, err := p.tryParseHavingClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if having != nil {
 		statementEnd = having.End()
 	}
 	window, err := p.tryParseWindowClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if window != nil {
 		statementEnd = window.End()
 	}
 	orderBy, err := p.tryParseOrderByClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if orderBy != nil {
@@ -1190,7 +1268,7 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 	var limitBy *LimitByClause
 	var limit *LimitClause
 	parsedLimitBy, err := p.tryParseLimitByClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if parsedLimitBy != nil {
@@ -1199,7 +1277,7 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 		case *LimitByClause:
 			limitBy = e
 			limit, err = p.tryParseLimitAfterLimitByClause(p.Pos())
-			if err != nil {
+			if err != nil && !p.recoverClause(pos, err) {
 				return nil, err
 			}
 			if limit != nil {
@@ -1219,7 +1297,7 @@ func (p *Parser) parseSelectStmt(pos Pos) (*SelectQuery, error) { // nolint: fun
 	}
 
 	settings, err := p.tryParseSettingsClause(p.Pos())
-	if err != nil {
+	if err != nil && !p.recoverClause(pos, err) {
 		return nil, err
 	}
 	if settings != nil {
@@ -1335,7 +1413,7 @@ func (p *Parser) parseExplainStmt(pos Pos) (*ExplainStmt, error) {
 		explainType = p.current().String
 		_ = p.lexer.consumeToken()
 	default:
-		return nil, fmt.Errorf("expected SYNTAX, PIPELINE, ESTIMATE or AST, got %s", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordSyntax, KeywordPipeline, KeywordEstimate, KeywordAst)
 	}
 	stmt, err := p.parseSelectQuery(p.Pos())
 	if err != nil {
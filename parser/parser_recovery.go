@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ParseStmtsWithRecovery parses every statement in the input like ParseStmts,
+// but does not stop at the first error. A statement that fails to parse is
+// returned as a BadStmt covering the text up to the next `;` outside of
+// brackets, and its error is recorded. Inside a SELECT statement, a clause
+// that fails to parse is skipped up to the next clause keyword (such as WHERE,
+// GROUP BY or ORDER BY) so the rest of the statement is still returned.
+//
+// The errors are returned in input order; they are nil if the input parsed
+// cleanly.
+func (p *Parser) ParseStmtsWithRecovery() ([]Expr, []*ParseError) {
+	p.recovering = true
+	p.errors = nil
+	defer func() {
+		p.recovering = false
+	}()
+
+	var stmts []Expr
+	for {
+		if err := p.lexer.consumeToken(); err != nil {
+			stmts = append(stmts, p.recoverStmt(Pos(p.lexer.offset), err))
+			continue
+		}
+		if p.current() == nil {
+			break
+		}
+		if p.matchTokenKind(";") {
+			continue
+		}
+		pos := p.Pos()
+		p.stmtPos = pos
+		stmt, err := p.parseStmt(pos)
+		if err != nil {
+			stmts = append(stmts, p.recoverStmt(pos, err))
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+	if p.lexer.keepComments {
+		p.commentMap = attachComments(p.lexer.input, stmts, p.lexer.comments)
+	}
+	return stmts, p.errors
+}
+
+// recoverStmt records err and skips to the `;` that ends the broken statement
+// starting at pos, or to the end of the input.
+func (p *Parser) recoverStmt(pos Pos, err error) *BadStmt {
+	p.recordError(err)
+	end := p.skipFrom(pos, func(*Token) bool {
+		return false
+	})
+	return &BadStmt{
+		From: pos,
+		To:   end,
+		Text: p.lexer.input[pos:end],
+	}
+}
+
+// recoverClause records err and skips to the start of the next clause of the
+// SELECT statement starting at pos, reporting whether the caller may go on
+// parsing. It reports false if the parser is not recovering, in which case the
+// caller must return err.
+//
+// If the SELECT statement is a subquery, the bracket that closes it ends the
+// skipped clause as well. A closing bracket that matches nothing is skipped
+// like any other token.
+func (p *Parser) recoverClause(pos Pos, err error) bool {
+	if !p.recovering {
+		return false
+	}
+	p.recordError(err)
+	nested := p.bracketDepthAt(pos) > 0
+	p.skipFrom(pos, func(token *Token) bool {
+		return isClauseBoundary(token, nested)
+	})
+	return true
+}
+
+// bracketDepthAt returns how many brackets are open at pos in the statement
+// being recovered, leaving the parser where it was.
+func (p *Parser) bracketDepthAt(pos Pos) int {
+	state := p.lexer.saveState()
+	defer p.lexer.restoreState(state)
+	depth := 0
+	p.skipUntil(p.stmtPos, pos, func(_ *Token, d int) bool {
+		depth = d
+		return true
+	})
+	return depth
+}
+
+// skipFrom skips from the error at the current token to the next `;`, or to
+// the next token that stop matches, and returns the end of the last skipped
+// token. Both must be outside of brackets, which are counted from pos, the
+// start of the statement being recovered, because the error may have been
+// raised while nested inside them. If the brackets are unbalanced, skipFrom
+// settles for the first `;` after the error.
+func (p *Parser) skipFrom(pos Pos, stop func(token *Token) bool) Pos {
+	errPos := p.Pos()
+	end, ok := p.skipUntil(pos, errPos, func(token *Token, depth int) bool {
+		return depth == 0 && (token.Kind == ";" || stop(token))
+	})
+	if ok {
+		return end
+	}
+	end, _ = p.skipUntil(pos, errPos, func(token *Token, _ int) bool {
+		return token.Kind == ";"
+	})
+	return end
+}
+
+// skipUntil lexes from pos until done matches a token at or after errPos,
+// leaving that token current, and returns the end of the token before it. It
+// reports false if it reached the end of the input instead.
+func (p *Parser) skipUntil(pos, errPos Pos, done func(token *Token, depth int) bool) (Pos, bool) {
+	p.lexer.restoreState(lexerState{offset: int(pos)})
+	end := pos
+	depth := 0
+	for p.skipToken() || !p.lexer.isEOF() {
+		token := p.current()
+		if token == nil {
+			// an untokenizable character, which skipToken has stepped over
+			end = Pos(p.lexer.offset)
+			continue
+		}
+		if token.Pos >= errPos && done(token, depth) {
+			return end, true
+		}
+		depth = nestingDepth(depth, token.Kind)
+		end = token.End
+	}
+	return end, false
+}
+
+// recoveryClauseKeywords are the keywords that start a SELECT clause or a set
+// operation, where recoverClause resumes parsing.
+var recoveryClauseKeywords = []string{
+	KeywordFrom, KeywordPrewhere, KeywordWhere, KeywordGroup, KeywordHaving,
+	KeywordWindow, KeywordOrder, KeywordLimit, KeywordSettings, KeywordFormat,
+	KeywordUnion, KeywordExcept, KeywordIntersect,
+}
+
+// isClauseBoundary reports whether recoverClause resumes parsing at token.
+// Closing brackets only count if nested is set, as a closing bracket outside
+// of the brackets opened while skipping then ends the subquery.
+func isClauseBoundary(token *Token, nested bool) bool {
+	switch token.Kind {
+	case TokenKindRParen, TokenKindRBracket, TokenKindRBrace:
+		return nested
+	case TokenKindKeyword:
+		for _, keyword := range recoveryClauseKeywords {
+			if token.ToString() == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nestingDepth(depth int, kind TokenKind) int {
+	switch kind {
+	case TokenKindLParen, TokenKindLBracket, TokenKindLBrace:
+		return depth + 1
+	case TokenKindRParen, TokenKindRBracket, TokenKindRBrace:
+		if depth > 0 {
+			return depth - 1
+		}
+	}
+	return depth
+}
+
+// skipToken moves to the next token, reporting false at the end of the input
+// or if the input there could not be tokenized. In the latter case the
+// offending character is skipped so that recovery always makes progress.
+func (p *Parser) skipToken() bool {
+	offset := p.lexer.offset
+	if err := p.lexer.consumeToken(); err == nil {
+		return p.current() != nil
+	}
+	if p.lexer.offset == offset {
+		_, size := utf8.DecodeRuneInString(p.lexer.input[offset:])
+		p.lexer.offset += max(size, 1)
+	}
+	p.lexer.currentToken = nil
+	return false
+}
+
+// recordError records err unless an error was already recorded at the same
+// position, which happens when recovery resumes at the token that failed.
+func (p *Parser) recordError(err error) {
+	var pe *ParseError
+	if !errors.As(p.wrapError(err), &pe) {
+		return
+	}
+	for _, recorded := range p.errors {
+		if recorded.Pos == pe.Pos {
+			return
+		}
+	}
+	p.errors = append(p.errors, pe)
+}
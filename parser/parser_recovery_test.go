@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStmtsWithRecovery(t *testing.T) {
+	sql := "SELECT 1;\n" +
+		"CREATE TABLE t (a Int32 ENGINE = Memory;\n" +
+		"ALTER TABLE t UPDATE a = f(';', (1; 2)) WHERE 1;\n" +
+		"DROP TABLE IF foo;\n" +
+		"SELECT 2"
+	stmts, errs := NewParser(sql).ParseStmtsWithRecovery()
+	require.Len(t, stmts, 5)
+	require.Len(t, errs, 3)
+
+	require.Equal(t, "SELECT 1", Format(stmts[0]))
+	require.Equal(t, "SELECT 2", Format(stmts[4]))
+
+	bad, ok := stmts[1].(*BadStmt)
+	require.True(t, ok)
+	require.Equal(t, "CREATE TABLE t (a Int32 ENGINE = Memory", bad.Text)
+	require.Equal(t, bad.Text, sql[bad.Pos():bad.End()])
+	require.Equal(t, "ALTER TABLE t UPDATE a = f(';', (1; 2)) WHERE 1", stmts[2].(*BadStmt).Text)
+	require.Equal(t, "DROP TABLE IF foo", stmts[3].(*BadStmt).Text)
+
+	for i, line := range []int{2, 3, 4} {
+		require.Equal(t, line, errs[i].Line)
+	}
+	require.Equal(t, "EXISTS", errs[2].Keyword)
+}
+
+func TestParseStmtsWithRecoveryNoErrors(t *testing.T) {
+	stmts, errs := NewParser("SELECT 1; SELECT 2").ParseStmtsWithRecovery()
+	require.Nil(t, errs)
+	require.Len(t, stmts, 2)
+}
+
+func TestParseStmtsWithRecoveryLexerError(t *testing.T) {
+	stmts, errs := NewParser("SELECT 1 é 2; SELECT 3").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	require.Len(t, stmts, 2)
+	require.Equal(t, "SELECT 3", Format(stmts[1]))
+}
+
+func TestParseStmtsWithRecoveryRecoversClauses(t *testing.T) {
+	stmts, errs := NewParser("SELECT a FROM t WHERE a = ORDER BY a LIMIT 1").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	require.Len(t, stmts, 1)
+
+	query, ok := stmts[0].(*SelectQuery)
+	require.True(t, ok)
+	require.NotNil(t, query.From)
+	require.Nil(t, query.Where)
+	require.NotNil(t, query.OrderBy)
+	require.NotNil(t, query.Limit)
+	require.Equal(t, "SELECT a FROM t ORDER BY a LIMIT 1", Format(query))
+
+	// A clause of a subquery recovers inside the subquery.
+	stmts, errs = NewParser("SELECT * FROM (SELECT a FROM t WHERE a + GROUP BY a) WHERE b = 1").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	query = stmts[0].(*SelectQuery)
+	require.NotNil(t, query.Where)
+	require.Equal(t, "SELECT * FROM (SELECT a FROM t GROUP BY a) WHERE b = 1", Format(query))
+
+	// The bracket closing the subquery ends the broken clause.
+	stmts, errs = NewParser("SELECT * FROM (SELECT a FROM t WHERE a +) WHERE b = 1").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	require.Equal(t, "SELECT * FROM (SELECT a FROM t) WHERE b = 1", Format(stmts[0]))
+
+	// A stray closing bracket is skipped with the rest of the clause.
+	stmts, errs = NewParser("SELECT a FROM t WHERE ) ORDER BY a").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	require.Equal(t, 1, errs[0].Line)
+	require.Equal(t, 23, errs[0].Column)
+	require.Equal(t, "SELECT a FROM t ORDER BY a", Format(stmts[0]))
+}
+
+func TestParseStmtsWithRecoveryUnbalancedBrackets(t *testing.T) {
+	stmts, errs := NewParser("SELECT f(a FROM t WHERE b; SELECT 2").ParseStmtsWithRecovery()
+	require.Len(t, errs, 1)
+	require.Len(t, stmts, 2)
+	require.Equal(t, "SELECT 2", Format(stmts[1]))
+}
+
+func TestParseStmtsStopsAtFirstError(t *testing.T) {
+	_, err := NewParser("SELECT a FROM t WHERE a = ORDER BY a; SELECT (").ParseStmts()
+	require.Error(t, err)
+}
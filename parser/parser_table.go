@@ -1,8 +1,6 @@
 package parser
 
 import (
-	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -13,7 +11,7 @@ func (p *Parser) parseDDL(pos Pos) (DDL, error) {
 		_ = p.lexer.consumeToken()
 		orReplace := p.tryConsumeKeywords(KeywordOr, KeywordReplace)
 		if orReplace && !p.matchOneOfKeywords(KeywordTemporary, KeywordTable, KeywordView, KeywordFunction, KeywordDictionary) {
-			return nil, fmt.Errorf("expected keyword: TEMPORARY|TABLE|VIEW|FUNCTION|DICTIONARY, but got %q", p.currentTokenString())
+			return nil, p.expectedKeywordsError(KeywordTemporary, KeywordTable, KeywordView, KeywordFunction, KeywordDictionary)
 		}
 		switch {
 		case p.matchKeyword(KeywordNamed):
@@ -37,19 +35,32 @@ func (p *Parser) parseDDL(pos Pos) (DDL, error) {
 			return p.parseCreateRole(pos)
 		case p.matchKeyword(KeywordUser):
 			return p.parseCreateUser(pos)
+		case p.matchKeyword(KeywordQuota):
+			return p.parseCreateQuota(pos)
+		case p.matchOneOfKeywords(KeywordRow, KeywordPolicy):
+			return p.parseCreateRowPolicy(pos)
+		case p.matchOneOfKeywords(KeywordSettings, KeywordProfile):
+			return p.parseCreateSettingsProfile(pos)
 		default:
-			return nil, fmt.Errorf("expected keyword: NAMED|DATABASE|DICTIONARY|TABLE|VIEW|ROLE|USER|FUNCTION|MATERIALIZED, but got %q",
-				p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordNamed, KeywordDatabase, KeywordDictionary, KeywordTable, KeywordView, KeywordRole, KeywordUser, KeywordQuota, KeywordRow+" "+KeywordPolicy, KeywordSettings+" "+KeywordProfile, KeywordFunction, KeywordMaterialized)
 		}
 	case p.matchKeyword(KeywordAlter):
 		_ = p.lexer.consumeToken()
 		switch {
 		case p.matchKeyword(KeywordRole):
 			return p.parseAlterRole(pos)
+		case p.matchKeyword(KeywordUser):
+			return p.parseAlterUser(pos)
+		case p.matchKeyword(KeywordQuota):
+			return p.parseAlterQuota(pos)
+		case p.matchOneOfKeywords(KeywordRow, KeywordPolicy):
+			return p.parseAlterRowPolicy(pos)
+		case p.matchOneOfKeywords(KeywordSettings, KeywordProfile):
+			return p.parseAlterSettingsProfile(pos)
 		case p.matchKeyword(KeywordTable):
 			return p.parseAlterTable(pos)
 		default:
-			return nil, fmt.Errorf("expected keyword: TABLE|ROLE, but got %q", p.currentTokenString())
+			return nil, p.expectedKeywordsError(KeywordTable, KeywordRole, KeywordUser, KeywordQuota, KeywordRow+" "+KeywordPolicy, KeywordSettings+" "+KeywordProfile)
 		}
 	case p.matchKeyword(KeywordDrop),
 		p.matchKeyword(KeywordDetach):
@@ -63,10 +74,16 @@ func (p *Parser) parseDDL(pos Pos) (DDL, error) {
 			p.matchKeyword(KeywordTable):
 			return p.parseDropStmt(pos)
 		case p.matchKeyword(KeywordUser),
-			p.matchKeyword(KeywordRole):
+			p.matchKeyword(KeywordRole),
+			p.matchKeyword(KeywordQuota),
+			p.matchKeyword(KeywordSettings),
+			p.matchKeyword(KeywordProfile):
 			return p.parserDropUserOrRole(pos)
+		case p.matchKeyword(KeywordRow),
+			p.matchKeyword(KeywordPolicy):
+			return p.parseDropRowPolicy(pos)
 		default:
-			return nil, fmt.Errorf("expected keyword: DATABASE|TABLE, but got %q", p.currentTokenString())
+			return nil, p.expectedKeywordsError(KeywordDatabase, KeywordTable)
 		}
 	case p.matchKeyword(KeywordTruncate):
 		return p.parseTruncateTable(pos)
@@ -285,7 +302,7 @@ func (p *Parser) parseNamedCollectionParam(pos Pos) (*NamedCollectionParam, erro
 		}
 		value = ident
 	default:
-		return nil, fmt.Errorf("expected string, number or identifier in named collection parameter, got %s", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindString, TokenKindInt, TokenKindFloat, TokenKindIdent)
 	}
 
 	param := &NamedCollectionParam{
@@ -385,10 +402,10 @@ func (p *Parser) parseCreateTable(pos Pos, orReplace bool) (*CreateTable, error)
 				createTable.TableFunction = tableFunc
 				createTable.StatementEnd = tableFunc.End()
 			} else {
-				return nil, fmt.Errorf("expected ( after identifier in AS clause, got %q", p.currentTokenKind())
+				return nil, p.expectedTokenError(TokenKindLParen)
 			}
 		} else {
-			return nil, fmt.Errorf("expected SELECT, WITH or identifier after AS, got %q", p.currentTokenKind())
+			return nil, p.expectedError([]TokenKind{TokenKindIdent}, []string{KeywordSelect, KeywordWith})
 		}
 	}
 
@@ -453,7 +470,7 @@ func (p *Parser) parseIdentOrFunction(_ Pos) (Expr, error) {
 					return nil, err
 				}
 			default:
-				return nil, fmt.Errorf("expected window name or (, but got %q", p.currentTokenKind())
+				return nil, p.expectedTokenError(TokenKindIdent, TokenKindLParen)
 			}
 
 			if err != nil {
@@ -503,7 +520,7 @@ func (p *Parser) parseIdentOrFunction(_ Pos) (Expr, error) {
 				Index:     i,
 			}, nil
 		default:
-			return nil, fmt.Errorf("expected IDENT, NUMBER or *, but got %q", p.currentTokenKind())
+			return nil, p.expectedTokenError(TokenKindIdent, TokenKindInt, TokenKindMul)
 		}
 	}
 	return ident, nil
@@ -623,8 +640,13 @@ func (p *Parser) parseTableColumns() ([]Expr, error) {
 			if err != nil {
 				return nil, err
 			}
-			if err := p.expectKeyword(KeywordCheck); err != nil {
-				return nil, err
+			var assume bool
+			switch {
+			case p.tryConsumeKeywords(KeywordCheck):
+			case p.tryConsumeKeywords(KeywordAssume):
+				assume = true
+			default:
+				return nil, p.expectedKeywordsError(KeywordCheck, KeywordAssume)
 			}
 			expr, err := p.parseExpr(p.Pos())
 			if err != nil {
@@ -633,6 +655,7 @@ func (p *Parser) parseTableColumns() ([]Expr, error) {
 			columns = append(columns, &ConstraintClause{
 				ConstraintPos: constraintPos,
 				Constraint:    ident,
+				Assume:        assume,
 				Expr:          expr,
 			})
 		case p.matchKeyword(KeywordProjection):
@@ -807,7 +830,7 @@ func (p *Parser) parseTableArgPrimaryExpr(pos Pos) (Expr, error) {
 		p.matchTokenKind(TokenKindString), p.matchKeyword(KeywordNull):
 		return p.parseLiteral(p.Pos())
 	default:
-		return nil, fmt.Errorf("unexpected token: %q, expected <Name>, <literal>", p.currentTokenString())
+		return nil, p.expectedError([]TokenKind{TokenKindIdent, TokenKindLParen, TokenKindInt, TokenKindFloat, TokenKindString}, []string{KeywordNull})
 	}
 }
 
@@ -901,7 +924,7 @@ func (p *Parser) tryParseClusterClause(pos Pos) (*ClusterClause, error) {
 	case p.matchTokenKind(TokenKindString):
 		expr, err = p.parseString(p.Pos())
 	default:
-		return nil, fmt.Errorf("unexpected token: %q, expected <IDENT> or <STRING>", p.currentTokenString())
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindString)
 	}
 	if err != nil {
 		return nil, err
@@ -1161,7 +1184,7 @@ func (p *Parser) parseInterpolateClause(interpolatePos Pos) (*InterpolateClause,
 
 	rparen := p.tryConsumeTokenKind(TokenKindRParen)
 	if rparen == nil {
-		return nil, fmt.Errorf("expected ')' after INTERPOLATE column list")
+		return nil, p.expectedTokenError(TokenKindRParen)
 	}
 
 	interpolate.Items = items
@@ -1223,7 +1246,7 @@ func (p *Parser) tryParseTTLPolicy(pos Pos) (*TTLPolicy, error) {
 			}
 			rule = &TTLPolicyRule{RulePos: pos, ToVolume: value}
 		} else {
-			return nil, fmt.Errorf("unexpected token: %q, expected DISK or VOLUME", p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordDisk, KeywordVolume)
 		}
 	case p.matchKeyword(KeywordDelete), p.matchKeyword(KeywordRecompress):
 		token := p.current()
@@ -1309,7 +1332,7 @@ func (p *Parser) parseSettingsClause(pos Pos) (*SettingsClause, error) {
 	}
 
 	if len(items) == 0 {
-		return nil, fmt.Errorf("settings list is empty")
+		return nil, p.syntaxError(ErrCodeMissingClause, "settings list is empty")
 	}
 	settings.ListEnd = items[len(items)-1].End()
 	settings.Items = items
@@ -1373,7 +1396,7 @@ func (p *Parser) parseSettingsExpr(pos Pos) (*SettingExpr, error) {
 			Literal:    curToken.String,
 		}
 	default:
-		return nil, fmt.Errorf("unexpected token: %q, expected <number>, <bool> or <string>", p.currentTokenString())
+		return nil, p.expectedError([]TokenKind{TokenKindInt, TokenKindString, TokenKindLBrace}, []string{KeywordTrue, KeywordFalse})
 	}
 
 	return &SettingExpr{
@@ -1419,6 +1442,7 @@ func (p *Parser) parseEngineExpr(pos Pos) (*EngineExpr, error) {
 		if err != nil {
 			return nil, err
 		}
+		p.checkKnownName(ident, "engine", knownEngines)
 		engineExpr.Name = ident.Name
 		engineEnd = ident.End()
 		if p.matchTokenKind(TokenKindLParen) {
@@ -1430,7 +1454,7 @@ func (p *Parser) parseEngineExpr(pos Pos) (*EngineExpr, error) {
 			engineExpr.EngineEnd = params.End()
 		}
 	default:
-		return nil, fmt.Errorf("unexpected token: %s", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindIdent)
 	}
 
 	for !p.lexer.isEOF() {
@@ -1498,7 +1522,7 @@ func (p *Parser) parseStmt(pos Pos) (Expr, error) {
 		p.matchKeyword(KeywordTruncate),
 		p.matchKeyword(KeywordRename):
 		expr, err = p.parseDDL(pos)
-	case p.matchKeyword(KeywordSelect), p.matchKeyword(KeywordWith):
+	case p.matchKeyword(KeywordSelect), p.matchKeyword(KeywordWith), p.matchTokenKind(TokenKindLParen):
 		expr, err = p.parseSelectQuery(pos)
 	case p.matchKeyword(KeywordDelete):
 		expr, err = p.parseDeleteClause(pos)
@@ -1520,15 +1544,14 @@ func (p *Parser) parseStmt(pos Pos) (Expr, error) {
 		expr, err = p.parseExplainStmt(pos)
 	case p.matchKeyword(KeywordGrant):
 		expr, err = p.parseGrantPrivilegeStmt(pos)
+	case p.matchKeyword(KeywordRevoke):
+		expr, err = p.parseRevokeStmt(pos)
 	case p.matchKeyword(KeywordShow):
 		expr, err = p.parseShowStmt(pos)
 	case p.matchKeyword(KeywordDesc), p.matchKeyword(KeywordDescribe):
 		expr, err = p.parseDescribeStmt(pos)
 	default:
-		if p.current() == nil {
-			return nil, errors.New("unexpected end of input")
-		}
-		return nil, fmt.Errorf("unexpected token: %q", p.currentTokenString())
+		return nil, p.unexpectedTokenError()
 	}
 	if err != nil {
 		return nil, err
@@ -1540,7 +1563,7 @@ func (p *Parser) parseStmt(pos Pos) (Expr, error) {
 
 	// Statement can be terminated by ';' or EOF
 	if p.current() != nil && !p.matchTokenKind(";") {
-		return nil, fmt.Errorf("<EOF> or ';' was expected, but got: %q", p.currentTokenString())
+		return nil, p.expectedTokenError(TokenKindEOF, ";")
 	}
 	return expr, nil
 }
@@ -1563,6 +1586,9 @@ func (p *Parser) ParseStmts() ([]Expr, error) {
 		}
 		stmts = append(stmts, stmt)
 	}
+	if p.lexer.keepComments {
+		p.commentMap = attachComments(p.lexer.input, stmts, p.lexer.comments)
+	}
 	return stmts, nil
 }
 
@@ -1588,132 +1614,209 @@ func (p *Parser) parseShowStmt(pos Pos) (*ShowStmt, error) {
 		return nil, err
 	}
 
-	var showType string
-	var target *TableIdentifier
+	stmt := &ShowStmt{ShowPos: pos}
 
 	// Parse the type of SHOW statement
 	switch {
 	case p.matchKeyword(KeywordCreate):
-		// SHOW CREATE TABLE table_name
-		showType = "CREATE"
+		_ = p.lexer.consumeToken()
+		switch {
+		case p.matchKeyword(KeywordTable):
+			stmt.ShowType = ShowTypeCreateTable
+		case p.matchKeyword(KeywordView):
+			stmt.ShowType = ShowTypeCreateView
+		case p.matchKeyword(KeywordDictionary):
+			stmt.ShowType = ShowTypeCreateDictionary
+		case p.matchKeyword(KeywordDatabase):
+			stmt.ShowType = ShowTypeCreateDatabase
+		case p.matchKeyword(KeywordUser):
+			stmt.ShowType = ShowTypeCreateUser
+		case p.matchKeyword(KeywordRole):
+			stmt.ShowType = ShowTypeCreateRole
+		default:
+			return nil, p.expectedKeywordsError(KeywordTable, KeywordView, KeywordDictionary, KeywordDatabase, KeywordUser, KeywordRole)
+		}
 		_ = p.lexer.consumeToken()
 
-		if err := p.expectKeyword(KeywordTable); err != nil {
-			return nil, err
+		switch stmt.ShowType {
+		case ShowTypeCreateUser, ShowTypeCreateRole:
+			// SHOW CREATE USER without a name shows the current user
+			if (p.matchTokenKind(TokenKindIdent) || p.matchTokenKind(TokenKindString)) && !p.matchOneOfKeywords(KeywordInto, KeywordFormat) {
+				users, err := p.parseUserNames()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Users = users
+			}
+		case ShowTypeCreateDatabase:
+			database, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Database = database
+		default:
+			target, err := p.parseTableIdentifier(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+			stmt.Target = target
 		}
-		showType += " TABLE"
 
-		tableIdent, err := p.parseTableIdentifier(p.Pos())
+	case p.matchKeyword(KeywordColumns),
+		p.matchOneOfKeywords(KeywordIndex, KeywordIndexes, KeywordIndices, KeywordKeys):
+		// SHOW COLUMNS|INDEX {FROM | IN} table [{FROM | IN} db]
+		stmt.ShowType = ShowTypeIndex
+		if p.matchKeyword(KeywordColumns) {
+			stmt.ShowType = ShowTypeColumns
+		}
+		_ = p.lexer.consumeToken()
+		if !p.tryConsumeKeywords(KeywordFrom) && !p.tryConsumeKeywords(KeywordIn) {
+			return nil, p.expectedKeywordsError(KeywordFrom, KeywordIn)
+		}
+		target, err := p.parseTableIdentifier(p.Pos())
 		if err != nil {
 			return nil, err
 		}
-		target = tableIdent
+		if target.Database == nil && (p.tryConsumeKeywords(KeywordFrom) || p.tryConsumeKeywords(KeywordIn)) {
+			database, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			target.Database = database
+		}
+		stmt.Target = target
 
-	case p.matchKeyword(KeywordDatabases):
-		// SHOW DATABASES [optional clauses]
-		showType = "DATABASES"
+	case p.matchKeyword(KeywordDatabases),
+		p.matchKeyword(KeywordTables),
+		p.matchKeyword(KeywordDictionaries),
+		p.matchKeyword(KeywordProcesslist),
+		p.matchKeyword(KeywordGrants),
+		p.matchKeyword(KeywordSettings),
+		p.matchKeyword(KeywordFunctions),
+		p.matchKeyword(KeywordEngines):
+		stmt.ShowType = ShowType(strings.ToUpper(p.current().String))
 		_ = p.lexer.consumeToken()
 
-	case p.matchKeyword(KeywordTables):
-		// SHOW TABLES
-		showType = "TABLES"
-		_ = p.lexer.consumeToken()
+		switch stmt.ShowType {
+		case ShowTypeTables, ShowTypeDictionaries:
+			// SHOW TABLES|DICTIONARIES [{FROM | IN} db]
+			if p.tryConsumeKeywords(KeywordFrom) || p.tryConsumeKeywords(KeywordIn) {
+				database, err := p.parseIdent()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Database = database
+			}
+		case ShowTypeGrants:
+			// SHOW GRANTS [FOR user [, user ...]]
+			if p.tryConsumeKeywords(KeywordFor) {
+				users, err := p.parseUserNames()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Users = users
+			}
+		case ShowTypeSettings:
+			if !p.matchOneOfKeywords(KeywordLike, KeywordIlike) {
+				return nil, p.expectedKeywordsError(KeywordLike, KeywordIlike)
+			}
+		}
 
 	default:
-		return nil, fmt.Errorf("expected CREATE, DATABASES, or TABLES after SHOW, got %q", p.currentTokenString())
+		return nil, p.expectedKeywordsError(KeywordCreate, KeywordDatabases, KeywordTables, KeywordColumns, KeywordDictionaries, KeywordProcesslist, KeywordGrants, KeywordSettings, KeywordFunctions, KeywordIndex, KeywordEngines)
 	}
 
-	stmt := &ShowStmt{
-		ShowPos:  pos,
-		ShowType: showType,
-		Target:   target,
+	if err := p.parseShowOptionalClauses(stmt); err != nil {
+		return nil, err
 	}
+	stmt.StatementEnd = p.lexer.prevToken.End
+	return stmt, nil
+}
 
-	// Parse optional clauses for SHOW DATABASES
-	if showType == "DATABASES" {
-		// Parse [[NOT] LIKE | ILIKE '<pattern>']
-		if p.matchKeyword(KeywordNot) {
-			stmt.NotLike = true
-			_ = p.lexer.consumeToken()
+// parseShowOptionalClauses parses the [NOT] LIKE|ILIKE, LIMIT, INTO OUTFILE
+// and FORMAT clauses shared by the SHOW statements.
+func (p *Parser) parseShowOptionalClauses(stmt *ShowStmt) error {
+	// Parse [[NOT] LIKE | ILIKE '<pattern>']
+	if p.matchKeyword(KeywordNot) {
+		stmt.NotLike = true
+		_ = p.lexer.consumeToken()
+	}
+
+	if p.matchKeyword(KeywordLike) || p.matchKeyword(KeywordIlike) {
+		if p.matchKeyword(KeywordLike) {
+			stmt.LikeType = "LIKE"
+		} else {
+			stmt.LikeType = "ILIKE"
 		}
+		_ = p.lexer.consumeToken()
 
-		if p.matchKeyword(KeywordLike) || p.matchKeyword(KeywordIlike) {
-			if p.matchKeyword(KeywordLike) {
-				stmt.LikeType = "LIKE"
-			} else {
-				stmt.LikeType = "ILIKE"
-			}
-			_ = p.lexer.consumeToken()
+		// Parse pattern expression
+		pattern, err := p.parseExpr(p.Pos())
+		if err != nil {
+			return err
+		}
+		stmt.LikePattern = pattern
+	} else if stmt.NotLike {
+		return p.expectedKeywordsError(KeywordLike, KeywordIlike)
+	}
 
-			// Parse pattern expression
-			pattern, err := p.parseExpr(p.Pos())
-			if err != nil {
-				return nil, err
-			}
-			stmt.LikePattern = pattern
+	// Parse [LIMIT <N>]
+	if p.matchKeyword(KeywordLimit) {
+		_ = p.lexer.consumeToken()
+		limit, err := p.parseExpr(p.Pos())
+		if err != nil {
+			return err
 		}
+		stmt.Limit = limit
+	}
 
-		// Parse [LIMIT <N>]
-		if p.matchKeyword(KeywordLimit) {
-			_ = p.lexer.consumeToken()
-			limit, err := p.parseExpr(p.Pos())
-			if err != nil {
-				return nil, err
-			}
-			stmt.Limit = limit
+	// Parse [INTO OUTFILE filename]
+	if p.matchKeyword(KeywordInto) {
+		_ = p.lexer.consumeToken()
+		if err := p.expectKeyword(KeywordOutfile); err != nil {
+			return err
 		}
 
-		// Parse [INTO OUTFILE filename]
-		if p.matchKeyword(KeywordInto) {
-			_ = p.lexer.consumeToken()
-			if err := p.expectKeyword(KeywordOutfile); err != nil {
-				return nil, err
-			}
+		// Parse filename as a string literal
+		outFile, err := p.parseString(p.Pos())
+		if err != nil {
+			return err
+		}
+		stmt.OutFile = outFile
+	}
+
+	// Parse [FORMAT format]
+	if p.matchKeyword(KeywordFormat) {
+		_ = p.lexer.consumeToken()
 
-			// Parse filename as a string literal
-			outFile, err := p.parseString(p.Pos())
+		// Format can be an identifier or a string
+		if p.matchTokenKind(TokenKindString) {
+			format, err := p.parseString(p.Pos())
 			if err != nil {
-				return nil, err
+				return err
 			}
-			stmt.OutFile = outFile
-		}
-
-		// Parse [FORMAT format]
-		if p.matchKeyword(KeywordFormat) {
+			stmt.Format = format
+		} else if p.matchTokenKind(TokenKindIdent) {
+			// Handle format as identifier (like JSON, CSV, etc.)
+			token := p.current()
+			p.checkKnownName(&Ident{NamePos: token.Pos, NameEnd: token.End, Name: token.String, QuoteType: token.QuoteType}, "format", knownFormats)
 			_ = p.lexer.consumeToken()
-
-			// Format can be an identifier or a string
-			if p.matchTokenKind(TokenKindString) {
-				format, err := p.parseString(p.Pos())
-				if err != nil {
-					return nil, err
-				}
-				stmt.Format = format
-			} else if p.matchTokenKind(TokenKindIdent) {
-				// Handle format as identifier (like JSON, CSV, etc.)
-				token := p.current()
-				_ = p.lexer.consumeToken()
-				stmt.Format = &StringLiteral{
-					LiteralPos: token.Pos,
-					LiteralEnd: token.End,
-					Literal:    token.String,
-				}
-			} else {
-				return nil, fmt.Errorf("expected format specification after FORMAT, got %q", p.currentTokenString())
+			stmt.Format = &StringLiteral{
+				LiteralPos: token.Pos,
+				LiteralEnd: token.End,
+				Literal:    token.String,
 			}
+		} else {
+			return p.expectedTokenError(TokenKindString, TokenKindIdent)
 		}
 	}
-
-	// Set statement end position
-	stmt.StatementEnd = p.End()
-
-	return stmt, nil
+	return nil
 }
 
 func (p *Parser) parseDescribeStmt(pos Pos) (*DescribeStmt, error) {
 	// DESC and DESCRIBE are both supported
 	if !p.matchKeyword(KeywordDesc) && !p.matchKeyword(KeywordDescribe) {
-		return nil, fmt.Errorf("expected DESC or DESCRIBE")
+		return nil, p.expectedKeywordsError(KeywordDesc, KeywordDescribe)
 	}
 	_ = p.lexer.consumeToken()
 
@@ -1929,17 +2032,40 @@ func (p *Parser) parseInsertStmt(pos Pos) (*InsertStmt, error) {
 	}
 	insertExpr.Table = table
 
-	if p.matchTokenKind(TokenKindLParen) {
-		// parse column names
+	switch {
+	case p.matchTokenKind(TokenKindLParen) && p.peekTokenKind(TokenKindMul):
+		insertExpr.AllColumns, err = p.parseInsertAllColumnsExpr(p.Pos())
+	case p.matchTokenKind(TokenKindLParen):
 		insertExpr.ColumnNames, err = p.parseColumnNamesExpr(p.Pos())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tryConsumeKeywords(KeywordFrom, KeywordInfile) {
+		insertExpr.InFile, err = p.parseString(p.Pos())
 		if err != nil {
 			return nil, err
 		}
+		if p.tryConsumeKeywords(KeywordCompression) {
+			insertExpr.Compression, err = p.parseString(p.Pos())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	insertExpr.Settings, err = p.tryParseSettingsClause(p.Pos())
+	if err != nil {
+		return nil, err
 	}
 
 	switch {
 	case p.matchKeyword(KeywordFormat):
 		insertExpr.Format, err = p.parseFormat(p.Pos())
+		if err == nil && insertExpr.InFile == nil {
+			insertExpr.Data = p.tryParseDataPayload(insertExpr.Format)
+		}
 	case p.matchKeyword(KeywordValues):
 		// consume VALUES keyword
 		_ = p.lexer.consumeToken()
@@ -1955,7 +2081,7 @@ func (p *Parser) parseInsertStmt(pos Pos) (*InsertStmt, error) {
 			}
 		}
 		insertExpr.Values = values
-	case p.matchKeyword(KeywordSelect):
+	case p.matchKeyword(KeywordSelect), p.matchKeyword(KeywordWith):
 		insertExpr.SelectExpr, err = p.parseSelectQuery(p.Pos())
 	default:
 		// do nothing
@@ -1964,9 +2090,112 @@ func (p *Parser) parseInsertStmt(pos Pos) (*InsertStmt, error) {
 	if err != nil {
 		return nil, err
 	}
+	if insertExpr.InFile != nil && (len(insertExpr.Values) > 0 || insertExpr.SelectExpr != nil) {
+		return nil, errorAt(insertExpr.InFile.Pos(), ErrCodeInvalidCombination, "INSERT FROM INFILE cannot be combined with VALUES or SELECT")
+	}
 	return insertExpr, nil
 }
 
+// tryParseDataPayload captures the inline data following `INSERT ... FORMAT
+// <name>`. Like clickhouse-client, it takes the data to start after the blanks
+// following the format name and at most one line break, and to end at the end
+// of that line, as most formats cannot tell where their input stops; Values
+// data may also end at a semicolon outside a string. The lexer then resumes
+// after the data, so the next statement needs no separating semicolon.
+// A semicolon alone on the following line ends the statement without data.
+func (p *Parser) tryParseDataPayload(format *FormatClause) *DataPayload {
+	isValues := strings.EqualFold(format.Format.Name, "Values")
+	start, end, ok := dataPayloadRange(p.lexer.input, int(format.End()), isValues)
+	if !ok {
+		return nil
+	}
+
+	// The lookahead token was lexed from the data; drop it and resume lexing
+	// at the end of the data. A nil current token ends the statement.
+	p.lexer.offset = end
+	p.lexer.currentToken = nil
+
+	data := strings.TrimSuffix(p.lexer.input[start:end], "\r")
+	if data == "" {
+		return nil
+	}
+	return &DataPayload{
+		DataPos: Pos(start),
+		DataEnd: Pos(start + len(data)),
+		Format:  format.Format.Name,
+		Data:    data,
+	}
+}
+
+// dataPayloadRange finds the inline data of an INSERT whose FORMAT clause ends
+// at offset, following the rules of tryParseDataPayload. It reports false if
+// the statement goes on after the FORMAT clause on the same line. Otherwise
+// the statement ends at end and the data is input[start:end], which is empty
+// when a semicolon on the following line ends the statement without data.
+func dataPayloadRange(input string, offset int, isValues bool) (start, end int, ok bool) {
+	start = offset
+	for start < len(input) && (input[start] == ' ' || input[start] == '\t' || input[start] == '\f') {
+		start++
+	}
+	if start >= len(input) || input[start] == ';' {
+		return 0, 0, false
+	}
+	if input[start] == '\r' {
+		start++
+	}
+	if start < len(input) && input[start] == '\n' {
+		start++
+	}
+	if rest := strings.TrimLeft(input[start:], " \t\f\r\n"); rest == "" || rest[0] == ';' {
+		end = len(input) - len(rest)
+		return end, end, true
+	}
+
+	var quote byte
+	end = start
+scan:
+	for ; end < len(input) && input[end] != '\n'; end++ {
+		if !isValues {
+			continue
+		}
+		switch c := input[end]; {
+		case quote != 0 && c == '\\':
+			end++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"' || c == '`'):
+			quote = c
+		case quote == 0 && c == ';':
+			break scan
+		}
+	}
+	return start, min(end, len(input)), true
+}
+
+// parseInsertAllColumnsExpr parses the `(*)` or `(* EXCEPT (c1, ...))` column
+// list of an INSERT.
+func (p *Parser) parseInsertAllColumnsExpr(pos Pos) (*InsertAllColumnsExpr, error) {
+	if err := p.expectTokenKind(TokenKindLParen); err != nil {
+		return nil, err
+	}
+	if err := p.expectTokenKind(TokenKindMul); err != nil {
+		return nil, err
+	}
+	allColumns := &InsertAllColumnsExpr{LeftParenPos: pos}
+	if p.tryConsumeKeywords(KeywordExcept) {
+		except, err := p.parseColumnNamesExpr(p.Pos())
+		if err != nil {
+			return nil, err
+		}
+		allColumns.Except = except
+	}
+	allColumns.RightParenPos = p.Pos()
+	if err := p.expectTokenKind(TokenKindRParen); err != nil {
+		return nil, err
+	}
+	return allColumns, nil
+}
+
 func (p *Parser) parseRenameStmt(pos Pos) (*RenameStmt, error) {
 	if err := p.expectKeyword(KeywordRename); err != nil {
 		return nil, err
@@ -2135,11 +2364,11 @@ func (p *Parser) parseDictionaryAttribute(pos Pos) (*DictionaryAttribute, error)
 	for {
 		// end of the property keyword about to be consumed; flag-only
 		// properties (HIERARCHICAL, ...) end at the keyword itself
-		keywordEnd := p.End()
+		keywordPos, keywordEnd := p.Pos(), p.End()
 		switch {
 		case p.tryConsumeKeywords(KeywordDefault):
 			if attr.Default != nil {
-				return nil, fmt.Errorf("duplicate DEFAULT clause")
+				return nil, errorAt(keywordPos, ErrCodeDuplicateClause, "duplicate DEFAULT clause")
 			}
 			literal, err := p.parseLiteral(p.Pos())
 			if err != nil {
@@ -2149,7 +2378,7 @@ func (p *Parser) parseDictionaryAttribute(pos Pos) (*DictionaryAttribute, error)
 			attr.AttrEnd = literal.End()
 		case p.tryConsumeKeywords(KeywordExpression):
 			if attr.Expression != nil {
-				return nil, fmt.Errorf("duplicate EXPRESSION clause")
+				return nil, errorAt(keywordPos, ErrCodeDuplicateClause, "duplicate EXPRESSION clause")
 			}
 			expr, err := p.parseExpr(p.Pos())
 			if err != nil {
@@ -2159,19 +2388,19 @@ func (p *Parser) parseDictionaryAttribute(pos Pos) (*DictionaryAttribute, error)
 			attr.AttrEnd = expr.End()
 		case p.tryConsumeKeywords(KeywordHierarchical):
 			if attr.Hierarchical {
-				return nil, fmt.Errorf("duplicate HIERARCHICAL clause")
+				return nil, errorAt(keywordPos, ErrCodeDuplicateClause, "duplicate HIERARCHICAL clause")
 			}
 			attr.Hierarchical = true
 			attr.AttrEnd = keywordEnd
 		case p.tryConsumeKeywords(KeywordInjective):
 			if attr.Injective {
-				return nil, fmt.Errorf("duplicate INJECTIVE clause")
+				return nil, errorAt(keywordPos, ErrCodeDuplicateClause, "duplicate INJECTIVE clause")
 			}
 			attr.Injective = true
 			attr.AttrEnd = keywordEnd
 		case p.tryConsumeKeywords(KeywordIs_object_id):
 			if attr.IsObjectId {
-				return nil, fmt.Errorf("duplicate IS_OBJECT_ID clause")
+				return nil, errorAt(keywordPos, ErrCodeDuplicateClause, "duplicate IS_OBJECT_ID clause")
 			}
 			attr.IsObjectId = true
 			attr.AttrEnd = keywordEnd
@@ -2201,7 +2430,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 		switch {
 		case p.matchKeyword(KeywordSource):
 			if engine.Source != nil {
-				return nil, fmt.Errorf("duplicate SOURCE clause")
+				return nil, p.syntaxError(ErrCodeDuplicateClause, "duplicate SOURCE clause")
 			}
 			source, err := p.parseDictionarySourceClause(p.Pos())
 			if err != nil {
@@ -2210,7 +2439,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 			engine.Source = source
 		case p.matchKeyword(KeywordLifetime):
 			if engine.Lifetime != nil {
-				return nil, fmt.Errorf("duplicate LIFETIME clause")
+				return nil, p.syntaxError(ErrCodeDuplicateClause, "duplicate LIFETIME clause")
 			}
 			lifetime, err := p.parseDictionaryLifetimeClause(p.Pos())
 			if err != nil {
@@ -2219,7 +2448,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 			engine.Lifetime = lifetime
 		case p.matchKeyword(KeywordLayout):
 			if engine.Layout != nil {
-				return nil, fmt.Errorf("duplicate LAYOUT clause")
+				return nil, p.syntaxError(ErrCodeDuplicateClause, "duplicate LAYOUT clause")
 			}
 			layout, err := p.parseDictionaryLayoutClause(p.Pos())
 			if err != nil {
@@ -2228,7 +2457,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 			engine.Layout = layout
 		case p.matchKeyword(KeywordRange):
 			if engine.Range != nil {
-				return nil, fmt.Errorf("duplicate RANGE clause")
+				return nil, p.syntaxError(ErrCodeDuplicateClause, "duplicate RANGE clause")
 			}
 			rangeClause, err := p.parseDictionaryRangeClause(p.Pos())
 			if err != nil {
@@ -2237,7 +2466,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 			engine.Range = rangeClause
 		case p.matchKeyword(KeywordSettings):
 			if engine.Settings != nil {
-				return nil, fmt.Errorf("duplicate SETTINGS clause")
+				return nil, p.syntaxError(ErrCodeDuplicateClause, "duplicate SETTINGS clause")
 			}
 			settings, err := p.parseDictionarySettingsClause(p.Pos())
 			if err != nil {
@@ -2247,7 +2476,7 @@ func (p *Parser) parseDictionaryEngineClause(pos Pos) (*DictionaryEngineClause,
 		default:
 			// No more engine clauses
 			if engine.Source == nil {
-				return nil, fmt.Errorf("SOURCE clause is required for dictionary")
+				return nil, p.syntaxError(ErrCodeMissingClause, "SOURCE clause is required for dictionary")
 			}
 			return engine, nil
 		}
@@ -2395,7 +2624,7 @@ func (p *Parser) parseDictionaryArgExpr(pos Pos) (*DictionaryArgExpr, error) {
 			value = ident
 		}
 	default:
-		return nil, fmt.Errorf("expected identifier, string or number in dictionary argument, got %s", p.currentTokenKind())
+		return nil, p.expectedTokenError(TokenKindIdent, TokenKindString, TokenKindInt, TokenKindFloat)
 	}
 
 	return &DictionaryArgExpr{
@@ -2558,7 +2787,7 @@ func (p *Parser) parseDictionaryRangeClause(pos Pos) (*DictionaryRangeClause, er
 		rangeClause.Min = min
 		rangeClause.Max = max
 	} else {
-		return nil, fmt.Errorf("expected MIN or MAX in RANGE clause")
+		return nil, p.expectedKeywordsError(KeywordMin, KeywordMax)
 	}
 
 	rParenPos := p.Pos()
@@ -1,7 +1,5 @@
 package parser
 
-import "fmt"
-
 // parseCreateMaterializedView parses a CREATE MATERIALIZED VIEW statement.
 //
 // The syntax is as follows:
@@ -105,7 +103,7 @@ func (p *Parser) parseCreateMaterializedView(pos Pos) (*CreateMaterializedView,
 		}
 		createMaterializedView.TableSchema = tableSchema
 		if !p.matchKeyword(KeywordEngine) {
-			return nil, fmt.Errorf("unexpected token: %q, expected ENGINE after column list", p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordEngine)
 		}
 		engineExpr, err := p.parseEngineExpr(p.Pos())
 		if err != nil {
@@ -121,7 +119,7 @@ func (p *Parser) parseCreateMaterializedView(pos Pos) (*CreateMaterializedView,
 		createMaterializedView.Engine = engineExpr
 		createMaterializedView.StatementEnd = engineExpr.End()
 	default:
-		return nil, fmt.Errorf("unexpected token: %q, expected TO or ENGINE", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordTo, KeywordEngine)
 	}
 	createMaterializedView.HasEmpty = p.tryConsumeKeywords(KeywordEmpty)
 
@@ -140,19 +138,20 @@ func (p *Parser) parseCreateMaterializedView(pos Pos) (*CreateMaterializedView,
 	// Parse SQL SECURITY clause
 	if p.tryConsumeKeywords(KeywordSQL, KeywordSecurity) {
 		if !p.matchOneOfKeywords(KeywordDefiner, KeywordNone) {
-			return nil, fmt.Errorf("expected DEFINER or NONE after SQL SECURITY, got %q", p.currentTokenKind())
+			return nil, p.expectedKeywordsError(KeywordDefiner, KeywordNone)
 		}
 		createMaterializedView.SQLSecurity = p.current().String
 		_ = p.lexer.consumeToken()
 	}
 
 	// Check for POPULATE before AS SELECT - only valid with ENGINE and no Destination
+	populatePos := p.Pos()
 	if p.tryConsumeKeywords(KeywordPopulate) {
 		if createMaterializedView.Destination != nil {
-			return nil, fmt.Errorf("POPULATE is only allowed when using ENGINE, not with TO clause")
+			return nil, errorAt(populatePos, ErrCodeInvalidCombination, "POPULATE is only allowed when using ENGINE, not with TO clause")
 		}
 		if createMaterializedView.Engine == nil {
-			return nil, fmt.Errorf("POPULATE requires ENGINE to be specified")
+			return nil, errorAt(populatePos, ErrCodeMissingClause, "POPULATE requires ENGINE to be specified")
 		}
 		createMaterializedView.Populate = true
 		createMaterializedView.StatementEnd = p.Pos()
@@ -195,7 +194,7 @@ func (p *Parser) tryParseRefreshExpr(pos Pos) (*RefreshExpr, error) {
 	// REFRESH EVERY|AFTER interval
 	refreshExpr := &RefreshExpr{RefreshPos: pos}
 	if !p.matchOneOfKeywords(KeywordEvery, KeywordAfter) {
-		return nil, fmt.Errorf("expected EVERY or AFTER, but got %q", p.currentTokenKind())
+		return nil, p.expectedKeywordsError(KeywordEvery, KeywordAfter)
 	}
 	refreshExpr.Frequency = p.current().String
 	_ = p.lexer.consumeToken()
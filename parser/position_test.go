@@ -124,3 +124,22 @@ func TestJoinLocalityIsInsideTheJoinSpan(t *testing.T) {
 	require.Equal(t, Pos(17), join.Pos())
 	require.Equal(t, Pos(len(sql)), join.End())
 }
+
+func TestShowStmtEnd(t *testing.T) {
+	for _, tc := range []struct {
+		sql string
+		end Pos
+	}{
+		{"SHOW PROCESSLIST;", 16},
+		{"SHOW PROCESSLIST", 16},
+		{"SHOW GRANTS FOR alice, bob", 26},
+		{"SHOW CREATE DATABASE db;", 23},
+		{"SHOW DATABASES LIMIT 5", 22},
+		// a quoted token ends before its closing quote, like the literal
+		{"SHOW TABLES FROM db1 NOT LIKE 'x'", 32},
+	} {
+		stmt := parseOneStmt(t, tc.sql).(*ShowStmt)
+		require.Equal(t, tc.end, stmt.StatementEnd, tc.sql)
+		require.Equal(t, tc.end, stmt.End(), tc.sql)
+	}
+}
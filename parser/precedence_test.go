@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -325,12 +326,60 @@ func TestIntersect(t *testing.T) {
 	stmts, err := NewParser("SELECT 1 INTERSECT SELECT 2").ParseStmts()
 	require.NoError(t, err)
 	require.Len(t, stmts, 1)
-	selectQuery, ok := stmts[0].(*SelectQuery)
+	setOp, ok := stmts[0].(*SetOperationExpr)
 	require.True(t, ok)
-	require.NotNil(t, selectQuery.Intersect)
+	require.Equal(t, SetOperatorIntersect, setOp.Operator)
 	require.Equal(t, "SELECT 1 INTERSECT SELECT 2", Format(stmts[0]))
 }
 
+func TestIntersectBindsTighterThanUnionAndExcept(t *testing.T) {
+	setOp, ok := parseOneStmt(t, "SELECT 1 UNION ALL SELECT 2 INTERSECT SELECT 3 EXCEPT DISTINCT SELECT 4").(*SetOperationExpr)
+	require.True(t, ok)
+	// ((1 UNION ALL (2 INTERSECT 3)) EXCEPT DISTINCT 4)
+	require.Equal(t, SetOperatorExcept, setOp.Operator)
+	require.Equal(t, SetModifierDistinct, setOp.Modifier)
+	left, ok := setOp.Left.(*SetOperationExpr)
+	require.True(t, ok)
+	require.Equal(t, SetOperatorUnion, left.Operator)
+	require.Equal(t, SetModifierAll, left.Modifier)
+	right, ok := left.Right.(*SetOperationExpr)
+	require.True(t, ok)
+	require.Equal(t, SetOperatorIntersect, right.Operator)
+}
+
+func TestSetOperationsAreLeftAssociative(t *testing.T) {
+	setOp, ok := parseOneStmt(t, "SELECT 1 EXCEPT SELECT 2 UNION SELECT 3").(*SetOperationExpr)
+	require.True(t, ok)
+	require.Equal(t, SetOperatorUnion, setOp.Operator)
+	require.Equal(t, SetModifierNone, setOp.Modifier)
+	left, ok := setOp.Left.(*SetOperationExpr)
+	require.True(t, ok)
+	require.Equal(t, SetOperatorExcept, left.Operator)
+}
+
+func TestParenthesizedSetOperand(t *testing.T) {
+	sql := "(SELECT a FROM t ORDER BY a LIMIT 1) UNION ALL (SELECT b FROM u INTERSECT SELECT c FROM v)"
+	setOp, ok := parseOneStmt(t, sql).(*SetOperationExpr)
+	require.True(t, ok)
+	left, ok := setOp.Left.(*SubQuery)
+	require.True(t, ok)
+	require.True(t, left.HasParen)
+	require.NotNil(t, left.Select.(*SelectQuery).Limit)
+	right, ok := setOp.Right.(*SubQuery)
+	require.True(t, ok)
+	require.IsType(t, &SetOperationExpr{}, right.Select)
+	require.Equal(t, sql, Format(setOp))
+}
+
+func TestFormatParenthesizesRewrittenSetOperations(t *testing.T) {
+	one := parseOneStmt(t, "SELECT 1")
+	two := parseOneStmt(t, "SELECT 2")
+	three := parseOneStmt(t, "SELECT 3")
+	union := &SetOperationExpr{Left: two, Operator: SetOperatorUnion, Modifier: SetModifierAll, Right: three}
+	intersect := &SetOperationExpr{Left: one, Operator: SetOperatorIntersect, Right: union}
+	require.Equal(t, "SELECT 1 INTERSECT (SELECT 2 UNION ALL SELECT 3)", Format(intersect))
+}
+
 func TestGlobalJoinLocalityPrefixesTheJoinType(t *testing.T) {
 	// the locality prefixes the join type, and both have to reach the modifiers
 	for _, tc := range []struct {
@@ -355,6 +404,72 @@ func TestGlobalJoinLocalityPrefixesTheJoinType(t *testing.T) {
 	}
 }
 
+func TestJoinTypeIsTyped(t *testing.T) {
+	for _, tc := range []struct {
+		sql        string
+		locality   JoinLocality
+		strictness JoinStrictness
+		kind       JoinKind
+		hasOuter   bool
+		isArray    bool
+		constraint JoinConstraintType
+		formatted  string
+	}{
+		{"SELECT * FROM t1 JOIN t2 ON t1.a = t2.a", "", "", "", false, false, JoinConstraintOn, ""},
+		{"SELECT * FROM t1 GLOBAL ANY LEFT OUTER JOIN t2 USING a", JoinLocalityGlobal, JoinStrictnessAny, JoinKindLeft, true, false, JoinConstraintUsing, ""},
+		{"SELECT * FROM t1 left outer any join t2 USING a", "", JoinStrictnessAny, JoinKindLeft, true, false, JoinConstraintUsing, "SELECT * FROM t1 ANY LEFT OUTER JOIN t2 USING a"},
+		{"SELECT * FROM t1 ASOF LEFT JOIN t2 ON t1.a = t2.a AND t1.t >= t2.t", "", JoinStrictnessAsof, JoinKindLeft, false, false, JoinConstraintOn, ""},
+		{"SELECT * FROM t1 RIGHT ANTI JOIN t2 USING a", "", JoinStrictnessAnti, JoinKindRight, false, false, JoinConstraintUsing, "SELECT * FROM t1 ANTI RIGHT JOIN t2 USING a"},
+		{"SELECT * FROM t1 LOCAL PASTE JOIN t2", JoinLocalityLocal, "", JoinKindPaste, false, false, JoinConstraintNone, ""},
+		{"SELECT * FROM t1 left array join arr", "", "", JoinKindLeft, false, true, JoinConstraintNone, "SELECT * FROM t1 LEFT ARRAY JOIN arr"},
+		{"SELECT * FROM t1, t2", "", "", JoinKindComma, false, false, JoinConstraintNone, "SELECT * FROM t1,t2"},
+	} {
+		stmt := parseOneStmt(t, tc.sql)
+		right, ok := stmt.(*SelectQuery).From.Expr.(*JoinExpr).Right.(*JoinExpr)
+		require.True(t, ok, tc.sql)
+		require.Equal(t, tc.locality, right.Locality, tc.sql)
+		require.Equal(t, tc.strictness, right.Strictness, tc.sql)
+		require.Equal(t, tc.kind, right.Kind, tc.sql)
+		require.Equal(t, tc.hasOuter, right.HasOuter, tc.sql)
+		require.Equal(t, tc.isArray, right.IsArray, tc.sql)
+		require.Equal(t, tc.constraint, right.ConstraintType, tc.sql)
+
+		formatted := tc.formatted
+		if formatted == "" {
+			formatted = tc.sql
+		}
+		require.Equal(t, formatted, Format(stmt), tc.sql)
+	}
+}
+
+func TestImpossibleJoinTypesAreRejected(t *testing.T) {
+	for _, tc := range []struct {
+		sql    string
+		msg    string
+		column int
+	}{
+		{"SELECT * FROM t1 ANY ALL JOIN t2", "ALL cannot follow ANY in a join", 22},
+		{"SELECT * FROM t1 LEFT RIGHT JOIN t2", "RIGHT cannot follow LEFT in a join", 23},
+		{"SELECT * FROM t1 INNER OUTER JOIN t2", "OUTER requires a LEFT, RIGHT or FULL join", 24},
+		{"SELECT * FROM t1 ANY CROSS JOIN t2", "ANY cannot be combined with CROSS JOIN", 18},
+		{"SELECT * FROM t1 SEMI JOIN t2 USING a", "SEMI JOIN requires LEFT or RIGHT", 18},
+		{"SELECT * FROM t1 ASOF FULL JOIN t2 USING a", "ASOF JOIN cannot be combined with FULL", 18},
+		{"SELECT * FROM t1 RIGHT ARRAY JOIN arr", "RIGHT cannot be combined with ARRAY JOIN", 18},
+		{"SELECT * FROM t1 ANY ARRAY JOIN arr", "ANY cannot be combined with ARRAY JOIN", 18},
+		{"SELECT * FROM a CROSS JOIN b ON a = b", "ON cannot be combined with CROSS JOIN", 30},
+		{"SELECT * FROM a PASTE JOIN b ON a.x = b.x", "ON cannot be combined with PASTE JOIN", 30},
+		{"SELECT * FROM a PASTE JOIN b USING x", "USING cannot be combined with PASTE JOIN", 30},
+		{"SELECT * FROM a, b ON a.x = b.x", "ON cannot be combined with a comma join", 20},
+		{"SELECT * FROM a, b USING x", "USING cannot be combined with a comma join", 20},
+	} {
+		_, err := NewParser(tc.sql).ParseStmts()
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe), "%s: expected a *ParseError, got %v", tc.sql, err)
+		require.Equal(t, tc.msg, pe.Msg, tc.sql)
+		require.Equal(t, tc.column, pe.Column, tc.sql)
+	}
+}
+
 func TestLocalityWithoutAJoinKeepsItsKeyword(t *testing.T) {
 	// a GLOBAL with no join used to be consumed and dropped from Format(); the
 	// error now has to name it rather than the end of the statement
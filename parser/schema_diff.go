@@ -0,0 +1,565 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaChange is a statement of the migration computed by DiffSchemas.
+type SchemaChange struct {
+	// Stmt is an *AlterTable for a table both schemas have, a *CreateTable
+	// for a table only the desired schema has or that has to be recreated,
+	// and a *DropStmt for a table only the current schema has.
+	Stmt Expr
+	// Destructive reports that Stmt loses data: it drops a table or columns
+	// of a table, or recreates a table with CREATE OR REPLACE because ALTER
+	// cannot apply the change in place. Reason says why.
+	Destructive bool
+	Reason      string
+}
+
+// DiffSchemas returns the statements that turn the tables of from, the
+// current schema, into the tables of to, the desired schema, in the order
+// they have to run. Tables are matched by their name as written, so `t` and
+// `default.t` are different tables.
+//
+// A table both schemas have is migrated with ALTER TABLE statements: ADD,
+// DROP, MODIFY and RENAME COLUMN, ADD and DROP of indexes, projections and
+// constraints, MODIFY ORDER BY, SAMPLE BY, TTL, SETTING and COMMENT. A
+// dropped and an added column are taken for a rename when they follow the
+// same column and their definitions only differ by name. The columns a table
+// drops are dropped by an ALTER TABLE of their own, reported as Destructive.
+// Column order is ignored: a new column is added at its place in the desired
+// schema, but a column both schemas have is not moved. A change ALTER
+// cannot apply, such as a new engine, partition key or primary key, or a
+// sorting key change other than appending new columns, recreates the table
+// instead. Definitions are compared as written, ignoring positions and
+// quoting: type aliases are not resolved, so changing a column from `INT` to
+// `Int32` yields a MODIFY COLUMN even though the type stays the same.
+//
+// It returns a *DuplicateTableError if a schema has two tables with the same
+// name.
+func DiffSchemas(from, to []*CreateTable) ([]SchemaChange, error) {
+	current, err := tablesByName(from, "current")
+	if err != nil {
+		return nil, err
+	}
+	desired, err := tablesByName(to, "desired")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []SchemaChange
+	for _, table := range to {
+		old, ok := current[tableName(table.Name)]
+		if !ok {
+			changes = append(changes, SchemaChange{Stmt: Clone(table)})
+			continue
+		}
+		changes = append(changes, diffTable(old, table)...)
+	}
+	for _, table := range from {
+		if _, ok := desired[tableName(table.Name)]; ok {
+			continue
+		}
+		drop := &DropStmt{DropTarget: "TABLE", Name: table.Name, OnCluster: table.OnCluster}
+		changes = append(changes, SchemaChange{
+			Stmt:        Clone(drop),
+			Destructive: true,
+			Reason:      "the table is not in the desired schema",
+		})
+	}
+	return changes, nil
+}
+
+// DuplicateTableError reports a table declared twice in a schema given to
+// DiffSchemas.
+type DuplicateTableError struct {
+	Table  *TableIdentifier // the second declaration
+	Schema string           // "current" or "desired"
+}
+
+func (e *DuplicateTableError) Error() string {
+	return fmt.Sprintf("duplicate table %s in the %s schema", tableName(e.Table), e.Schema)
+}
+
+func tablesByName(tables []*CreateTable, schema string) (map[string]*CreateTable, error) {
+	byName := make(map[string]*CreateTable, len(tables))
+	for _, table := range tables {
+		name := tableName(table.Name)
+		if _, ok := byName[name]; ok {
+			return nil, &DuplicateTableError{Table: table.Name, Schema: schema}
+		}
+		byName[name] = table
+	}
+	return byName, nil
+}
+
+func tableName(name *TableIdentifier) string {
+	if name.Database != nil {
+		return name.Database.Name + "." + name.Table.Name
+	}
+	return name.Table.Name
+}
+
+func sameExpr(a, b Expr) bool {
+	return Equal(a, b, IgnorePositions(), IgnoreQuoteStyle())
+}
+
+// tableElements holds the elements of the column list of a CREATE TABLE.
+type tableElements struct {
+	columns     []*ColumnDef
+	indexes     []*TableIndex
+	projections []*TableProjection
+	constraints []*ConstraintClause
+}
+
+func elementsOf(table *CreateTable) tableElements {
+	var elements tableElements
+	for _, element := range table.TableSchema.Columns {
+		switch element := element.(type) {
+		case *ColumnDef:
+			elements.columns = append(elements.columns, element)
+		case *TableIndex:
+			elements.indexes = append(elements.indexes, element)
+		case *TableProjection:
+			elements.projections = append(elements.projections, element)
+		case *ConstraintClause:
+			elements.constraints = append(elements.constraints, element)
+		}
+	}
+	return elements
+}
+
+// hasColumnList reports whether table declares its columns, rather than
+// taking them from another table, a table function or a query.
+func hasColumnList(table *CreateTable) bool {
+	return table.TableSchema != nil && table.TableSchema.AliasTable == nil &&
+		table.TableSchema.TableFunction == nil && table.TableFunction == nil && table.SubQuery == nil
+}
+
+func engineOf(table *CreateTable) *EngineExpr {
+	if table.Engine != nil {
+		return table.Engine
+	}
+	return &EngineExpr{}
+}
+
+// tableDiff collects the clauses migrating a table, grouped into the ALTER
+// statements they have to run in: ClickHouse refuses, for example, to drop
+// and add an index of the same name in one statement, and this parser cannot
+// read clauses following MODIFY TTL or MODIFY SETTING.
+type tableDiff struct {
+	drops    []AlterTableClause // indexes, projections and constraints
+	renames  []AlterTableClause
+	dropped  []AlterTableClause // DROP COLUMN, a destructive change of its own
+	columns  []AlterTableClause // ADD and MODIFY COLUMN, MODIFY ORDER BY
+	removals []AlterTableClause // MODIFY COLUMN ... REMOVE, one per statement
+	adds     []AlterTableClause // indexes, projections, constraints, SAMPLE BY and COMMENT
+	ttl      []AlterTableClause
+	settings []AlterTableClause
+	resets   []AlterTableClause
+}
+
+func diffTable(from, to *CreateTable) []SchemaChange {
+	recreate := func(reason string) []SchemaChange {
+		stmt := Clone(to).(*CreateTable)
+		stmt.OrReplace = true
+		stmt.IfNotExists = false
+		return []SchemaChange{{Stmt: stmt, Destructive: true, Reason: reason}}
+	}
+	if !hasColumnList(from) || !hasColumnList(to) {
+		a, b := *from, *to
+		a.OrReplace, b.OrReplace = false, false
+		a.IfNotExists, b.IfNotExists = false, false
+		if sameExpr(&a, &b) {
+			return nil
+		}
+		return recreate("the table is not defined by a column list")
+	}
+
+	d := &tableDiff{}
+	added := d.diffColumns(elementsOf(from).columns, elementsOf(to).columns)
+	if reason := d.diffEngine(engineOf(from), engineOf(to), added); reason != "" {
+		return recreate(reason)
+	}
+	d.diffIndexes(elementsOf(from).indexes, elementsOf(to).indexes)
+	d.diffProjections(elementsOf(from).projections, elementsOf(to).projections)
+	d.diffConstraints(elementsOf(from).constraints, elementsOf(to).constraints)
+	if !sameExpr(from.Comment, to.Comment) {
+		comment := to.Comment
+		if comment == nil {
+			comment = &StringLiteral{}
+		}
+		d.adds = append(d.adds, &AlterTableModifyComment{Comment: comment})
+	}
+
+	var changes []SchemaChange
+	alter := func(clauses ...AlterTableClause) {
+		if len(clauses) == 0 {
+			return
+		}
+		stmt := &AlterTable{TableIdentifier: to.Name, OnCluster: to.OnCluster, AlterExprs: clauses}
+		changes = append(changes, SchemaChange{Stmt: Clone(stmt)})
+	}
+	alter(d.drops...)
+	alter(d.renames...)
+	if len(d.dropped) > 0 {
+		alter(d.dropped...)
+		changes[len(changes)-1].Destructive = true
+		changes[len(changes)-1].Reason = droppedColumnsReason(d.dropped)
+	}
+	alter(d.columns...)
+	for _, removal := range d.removals {
+		alter(removal)
+	}
+	alter(d.adds...)
+	alter(d.ttl...)
+	alter(d.settings...)
+	alter(d.resets...)
+	return changes
+}
+
+// diffColumns adds the clauses migrating the columns from to the columns to,
+// and returns the names of the added columns.
+func (d *tableDiff) diffColumns(from, to []*ColumnDef) map[string]bool {
+	current := make(map[string]*ColumnDef, len(from))
+	for _, column := range from {
+		current[columnName(column.Name)] = column
+	}
+	desired := make(map[string]*ColumnDef, len(to))
+	for _, column := range to {
+		desired[columnName(column.Name)] = column
+	}
+
+	// a dropped column following the same column as an added one with the
+	// same definition is taken for a rename
+	renamed := make(map[string]bool)
+	for i, column := range from {
+		if _, ok := desired[columnName(column.Name)]; ok {
+			continue
+		}
+		for j, other := range to {
+			name := columnName(other.Name)
+			if _, ok := current[name]; ok || renamed[name] || previousColumn(from, i) != previousColumn(to, j) {
+				continue
+			}
+			if sameColumnDef(column, other) {
+				d.renames = append(d.renames, &AlterTableRenameColumn{
+					OldColumnName: column.Name,
+					NewColumnName: other.Name,
+				})
+				renamed[columnName(column.Name)] = true
+				renamed[name] = true
+				break
+			}
+		}
+	}
+
+	for _, column := range from {
+		name := columnName(column.Name)
+		if _, ok := desired[name]; !ok && !renamed[name] {
+			d.dropped = append(d.dropped, &AlterTableDropColumn{ColumnName: column.Name})
+		}
+	}
+	added := make(map[string]bool)
+	for i, column := range to {
+		name := columnName(column.Name)
+		if _, ok := current[name]; ok || renamed[name] {
+			continue
+		}
+		add := &AlterTableAddColumn{Column: column, First: i == 0}
+		if i > 0 {
+			add.After = to[i-1].Name
+		}
+		d.columns = append(d.columns, add)
+		added[name] = true
+	}
+	for _, column := range to {
+		if old, ok := current[columnName(column.Name)]; ok {
+			d.modifyColumn(old, column)
+		}
+	}
+	return added
+}
+
+// droppedColumnsReason says which columns the DROP COLUMN clauses drop.
+func droppedColumnsReason(clauses []AlterTableClause) string {
+	names := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		names = append(names, columnName(clause.(*AlterTableDropColumn).ColumnName))
+	}
+	if len(names) == 1 {
+		return "drops column " + names[0]
+	}
+	return "drops columns " + strings.Join(names, ", ")
+}
+
+// columnName returns the name of a column, index or projection, regardless of
+// how it is quoted.
+func columnName(name *NestedIdentifier) string {
+	if name.DotIdent != nil {
+		return name.Ident.Name + "." + name.DotIdent.Name
+	}
+	return name.Ident.Name
+}
+
+// previousColumn returns the name of the column before columns[i], or an
+// empty string for the first column.
+func previousColumn(columns []*ColumnDef, i int) string {
+	if i == 0 {
+		return ""
+	}
+	return columnName(columns[i-1].Name)
+}
+
+// sameColumnDef reports whether a and b only differ by their names.
+func sameColumnDef(a, b *ColumnDef) bool {
+	x, y := *a, *b
+	x.Name, y.Name = nil, nil
+	return sameExpr(&x, &y)
+}
+
+// modifyColumn adds the clauses turning the column from into to: a MODIFY
+// COLUMN with the definition of to, as MODIFY COLUMN keeps the properties it
+// leaves out, followed by a REMOVE for each of those to no longer has.
+func (d *tableDiff) modifyColumn(from, to *ColumnDef) {
+	kept := *from
+	remove := func(property string) {
+		d.removals = append(d.removals, &AlterTableModifyColumn{
+			Column: &ColumnDef{Name: to.Name},
+			RemovePropertyType: &RemovePropertyType{
+				PropertyType: &PropertyType{Name: &Ident{Name: property}},
+			},
+		})
+	}
+	if to.DefaultExpr == nil && to.MaterializedExpr == nil && to.AliasExpr == nil {
+		switch {
+		case from.DefaultExpr != nil:
+			remove("DEFAULT")
+		case from.MaterializedExpr != nil:
+			remove("MATERIALIZED")
+		case from.AliasExpr != nil:
+			remove("ALIAS")
+		}
+		kept.DefaultExpr, kept.MaterializedExpr, kept.AliasExpr = nil, nil, nil
+	}
+	if from.Comment != nil && to.Comment == nil {
+		remove("COMMENT")
+		kept.Comment = nil
+	}
+	if from.Codec != nil && to.Codec == nil {
+		remove("CODEC")
+		kept.Codec = nil
+	}
+	if from.TTL != nil && to.TTL == nil {
+		remove("TTL")
+		kept.TTL = nil
+	}
+	if !sameColumnDef(&kept, to) {
+		d.columns = append(d.columns, &AlterTableModifyColumn{Column: to})
+	}
+}
+
+// diffEngine adds the clauses migrating the engine from to the engine to. It
+// returns why the table has to be recreated instead, if it has to.
+func (d *tableDiff) diffEngine(from, to *EngineExpr, added map[string]bool) string {
+	switch {
+	case from.Name != to.Name || !sameExpr(from.Params, to.Params):
+		return fmt.Sprintf("ENGINE changes from %s to %s", engineName(from), engineName(to))
+	case !sameExpr(from.PartitionBy, to.PartitionBy):
+		return "PARTITION BY changes"
+	case !sameExpr(from.PrimaryKey, to.PrimaryKey):
+		return "PRIMARY KEY changes"
+	case from.SampleBy != nil && to.SampleBy == nil:
+		return "SAMPLE BY is removed"
+	}
+
+	if !sameExpr(from.OrderBy, to.OrderBy) {
+		if !appendsKey(from.OrderBy, to.OrderBy, added) {
+			return "ORDER BY changes other than appending new columns to it"
+		}
+		orderBy := to.OrderBy.Items[0]
+		if len(to.OrderBy.Items) > 1 {
+			orderBy = &ParamExprList{Items: &ColumnExprList{Items: to.OrderBy.Items}}
+		}
+		d.columns = append(d.columns, &AlterTableModifyOrderBy{OrderBy: orderBy})
+	}
+	if to.SampleBy != nil && !sameExpr(from.SampleBy, to.SampleBy) {
+		d.adds = append(d.adds, &AlterTableModifySampleBy{SampleBy: to.SampleBy.Expr})
+	}
+
+	switch {
+	case from.TTL != nil && to.TTL == nil:
+		d.ttl = append(d.ttl, &AlterTableRemoveTTL{})
+	case to.TTL != nil && !sameExpr(from.TTL, to.TTL):
+		d.ttl = append(d.ttl, &AlterTableModifyTTL{TTL: to.TTL})
+	}
+	d.diffSettings(from.Settings, to.Settings)
+	return ""
+}
+
+func engineName(engine *EngineExpr) string {
+	if engine.Name == "" {
+		return "the default engine"
+	}
+	if engine.Params != nil {
+		return engine.Name + Format(engine.Params)
+	}
+	return engine.Name
+}
+
+// appendsKey reports whether the sorting key to is the sorting key from
+// with added columns appended, the only change MODIFY ORDER BY accepts.
+func appendsKey(from, to *OrderByClause, added map[string]bool) bool {
+	if from == nil || to == nil {
+		return false
+	}
+	old, keys := sortingKey(from), sortingKey(to)
+	if len(keys) < len(old) {
+		return false
+	}
+	for i, key := range keys {
+		if i < len(old) {
+			if !sameExpr(old[i], key) {
+				return false
+			}
+			continue
+		}
+		if ident, ok := key.(*Ident); !ok || !added[ident.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortingKey returns the expressions of an ORDER BY clause, unwrapping the
+// tuple of ORDER BY (a, b).
+func sortingKey(orderBy *OrderByClause) []Expr {
+	var keys []Expr
+	for _, item := range orderBy.Items {
+		if order, ok := item.(*OrderExpr); ok && order.Direction == OrderDirectionNone && order.Alias == nil && order.Fill == nil {
+			item = order.Expr
+		}
+		if list, ok := item.(*ParamExprList); ok && list.ColumnArgList == nil {
+			for _, key := range list.Items.Items {
+				if column, ok := key.(*ColumnExpr); ok && column.Alias == nil {
+					key = column.Expr
+				}
+				keys = append(keys, key)
+			}
+			continue
+		}
+		keys = append(keys, item)
+	}
+	return keys
+}
+
+func (d *tableDiff) diffSettings(from, to *SettingsClause) {
+	current := make(map[string]*SettingExpr)
+	if from != nil {
+		for _, setting := range from.Items {
+			current[setting.Name.Name] = setting
+		}
+	}
+	desired := make(map[string]bool)
+	var modified []*SettingExpr
+	if to != nil {
+		for _, setting := range to.Items {
+			desired[setting.Name.Name] = true
+			if old, ok := current[setting.Name.Name]; !ok || !sameExpr(old.Expr, setting.Expr) {
+				modified = append(modified, setting)
+			}
+		}
+	}
+	if len(modified) > 0 {
+		d.settings = append(d.settings, &AlterTableModifySetting{Settings: modified})
+	}
+	var reset []*Ident
+	if from != nil {
+		for _, setting := range from.Items {
+			if !desired[setting.Name.Name] {
+				reset = append(reset, setting.Name)
+			}
+		}
+	}
+	if len(reset) > 0 {
+		d.resets = append(d.resets, &AlterTableResetSetting{Settings: reset})
+	}
+}
+
+func (d *tableDiff) diffIndexes(from, to []*TableIndex) {
+	current := make(map[string]*TableIndex, len(from))
+	for _, index := range from {
+		current[columnName(index.Name)] = index
+	}
+	desired := make(map[string]*TableIndex, len(to))
+	for _, index := range to {
+		desired[columnName(index.Name)] = index
+	}
+	for _, index := range from {
+		if other, ok := desired[columnName(index.Name)]; !ok || !sameExpr(index, other) {
+			d.drops = append(d.drops, &AlterTableDropIndex{IndexName: index.Name})
+		}
+	}
+	for _, index := range to {
+		if other, ok := current[columnName(index.Name)]; !ok || !sameExpr(index, other) {
+			d.adds = append(d.adds, &AlterTableAddIndex{Index: index})
+		}
+	}
+}
+
+func (d *tableDiff) diffProjections(from, to []*TableProjection) {
+	current := make(map[string]*TableProjection, len(from))
+	for _, projection := range from {
+		current[columnName(projection.Identifier)] = projection
+	}
+	desired := make(map[string]*TableProjection, len(to))
+	for _, projection := range to {
+		desired[columnName(projection.Identifier)] = projection
+	}
+	for _, projection := range from {
+		if other, ok := desired[columnName(projection.Identifier)]; !ok || !sameProjection(projection, other) {
+			d.drops = append(d.drops, &AlterTableDropProjection{ProjectionName: projection.Identifier})
+		}
+	}
+	for _, projection := range to {
+		if other, ok := current[columnName(projection.Identifier)]; !ok || !sameProjection(projection, other) {
+			added := *projection
+			added.IncludeProjectionKeyword = false
+			d.adds = append(d.adds, &AlterTableAddProjection{TableProjection: &added})
+		}
+	}
+}
+
+// sameProjection compares a and b regardless of how they were declared.
+func sameProjection(a, b *TableProjection) bool {
+	x, y := *a, *b
+	x.IncludeProjectionKeyword, y.IncludeProjectionKeyword = false, false
+	return sameExpr(&x, &y)
+}
+
+func (d *tableDiff) diffConstraints(from, to []*ConstraintClause) {
+	current := make(map[string]*ConstraintClause, len(from))
+	for _, constraint := range from {
+		current[constraint.Constraint.Name] = constraint
+	}
+	desired := make(map[string]*ConstraintClause, len(to))
+	for _, constraint := range to {
+		desired[constraint.Constraint.Name] = constraint
+	}
+	for _, constraint := range from {
+		if other, ok := desired[constraint.Constraint.Name]; !ok || !sameExpr(constraint, other) {
+			d.drops = append(d.drops, &AlterTableDropConstraint{Name: constraint.Constraint})
+		}
+	}
+	for _, constraint := range to {
+		if other, ok := current[constraint.Constraint.Name]; !ok || !sameExpr(constraint, other) {
+			d.adds = append(d.adds, &AlterTableAddConstraint{
+				Name:   constraint.Constraint,
+				Assume: constraint.Assume,
+				Expr:   constraint.Expr,
+			})
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseTables(t *testing.T, sql string) []*CreateTable {
+	stmts, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+	tables := make([]*CreateTable, 0, len(stmts))
+	for _, stmt := range stmts {
+		tables = append(tables, stmt.(*CreateTable))
+	}
+	return tables
+}
+
+func diffSchemas(t *testing.T, from, to string) []string {
+	changes, err := DiffSchemas(parseTables(t, from), parseTables(t, to))
+	require.NoError(t, err)
+	var stmts []string
+	for _, change := range changes {
+		stmt := Format(change.Stmt)
+		if change.Destructive {
+			stmt += " -- destructive: " + change.Reason
+		} else {
+			// the statements can be read back
+			_, err := NewParser(stmt).ParseStmts()
+			require.NoError(t, err, stmt)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+func TestDiffSchemas(t *testing.T) {
+	from := `
+CREATE TABLE events
+(
+    id UInt64,
+    ts DateTime,
+    url String,
+    name String DEFAULT '' COMMENT 'the name',
+    legacy UInt8,
+    INDEX name_idx name TYPE bloom_filter GRANULARITY 4,
+    INDEX ts_idx ts TYPE minmax GRANULARITY 1,
+    PROJECTION by_name (SELECT * ORDER BY name),
+    CONSTRAINT id_positive CHECK id > 0
+)
+ENGINE = MergeTree
+PARTITION BY toYYYYMM(ts)
+ORDER BY (id, ts)
+TTL ts + INTERVAL 1 YEAR
+SETTINGS index_granularity = 8192, merge_with_ttl_timeout = 3600;
+CREATE TABLE sessions (id UInt64) ENGINE = Memory;
+CREATE TABLE same (id UInt64) ENGINE = Memory;`
+	to := `
+CREATE TABLE events
+(
+    id UInt64,
+    user_id UInt64,
+    ts DateTime,
+    link String,
+    name LowCardinality(String),
+    INDEX name_idx name TYPE bloom_filter GRANULARITY 8,
+    INDEX user_idx user_id TYPE minmax GRANULARITY 1,
+    PROJECTION by_user (SELECT * ORDER BY user_id),
+    CONSTRAINT id_positive CHECK id > 0
+)
+ENGINE = MergeTree
+PARTITION BY toYYYYMM(ts)
+ORDER BY (id, ts, user_id)
+TTL ts + INTERVAL 2 YEAR
+SETTINGS index_granularity = 8192, min_bytes_for_wide_part = 0
+COMMENT 'all events';
+CREATE TABLE users (id UInt64) ENGINE = Memory;
+CREATE TABLE same (` + "`id`" + ` UInt64) ENGINE = Memory;`
+
+	require.Equal(t, []string{
+		"ALTER TABLE events DROP INDEX name_idx, DROP INDEX ts_idx, DROP PROJECTION by_name",
+		"ALTER TABLE events RENAME COLUMN url TO link",
+		"ALTER TABLE events DROP COLUMN legacy -- destructive: drops column legacy",
+		"ALTER TABLE events ADD COLUMN user_id UInt64 AFTER id, MODIFY COLUMN name LowCardinality(String), MODIFY ORDER BY (id, ts, user_id)",
+		"ALTER TABLE events MODIFY COLUMN name REMOVE DEFAULT",
+		"ALTER TABLE events MODIFY COLUMN name REMOVE COMMENT",
+		"ALTER TABLE events ADD INDEX name_idx name TYPE bloom_filter GRANULARITY 8, ADD INDEX user_idx user_id TYPE minmax GRANULARITY 1, ADD PROJECTION by_user (SELECT * ORDER BY user_id), MODIFY COMMENT 'all events'",
+		"ALTER TABLE events MODIFY TTL ts + INTERVAL 2 YEAR",
+		"ALTER TABLE events MODIFY SETTING min_bytes_for_wide_part=0",
+		"ALTER TABLE events RESET SETTING merge_with_ttl_timeout",
+		"CREATE TABLE users (id UInt64) ENGINE = Memory",
+		"DROP TABLE sessions -- destructive: the table is not in the desired schema",
+	}, diffSchemas(t, from, to))
+
+	require.Empty(t, diffSchemas(t, from, from))
+}
+
+func TestDiffSchemasRecreate(t *testing.T) {
+	for _, test := range []struct {
+		from, to string
+		reason   string
+	}{
+		{
+			"CREATE TABLE t (a UInt8) ENGINE = MergeTree ORDER BY a",
+			"CREATE TABLE t (a UInt8) ENGINE = ReplacingMergeTree(a) ORDER BY a",
+			"ENGINE changes from MergeTree to ReplacingMergeTree(a)",
+		},
+		{
+			"CREATE TABLE t (a UInt8, d Date) ENGINE = MergeTree ORDER BY a PARTITION BY d",
+			"CREATE TABLE t (a UInt8, d Date) ENGINE = MergeTree ORDER BY a PARTITION BY toYYYYMM(d)",
+			"PARTITION BY changes",
+		},
+		{
+			"CREATE TABLE t (a UInt8, b UInt8) ENGINE = MergeTree ORDER BY (a, b)",
+			"CREATE TABLE t (a UInt8, b UInt8) ENGINE = MergeTree ORDER BY (b, a)",
+			"ORDER BY changes other than appending new columns to it",
+		},
+		{
+			"CREATE TABLE t (a UInt8, b UInt8) ENGINE = MergeTree ORDER BY a",
+			"CREATE TABLE t (a UInt8, b UInt8) ENGINE = MergeTree ORDER BY (a, b)",
+			"ORDER BY changes other than appending new columns to it",
+		},
+		{
+			"CREATE TABLE t (a UInt8) ENGINE = MergeTree ORDER BY a SAMPLE BY a",
+			"CREATE TABLE t (a UInt8) ENGINE = MergeTree ORDER BY a",
+			"SAMPLE BY is removed",
+		},
+	} {
+		stmts := diffSchemas(t, test.from, test.to)
+		require.Len(t, stmts, 1, test.to)
+		require.Equal(t, "CREATE OR REPLACE "+test.to[len("CREATE "):]+" -- destructive: "+test.reason, stmts[0])
+	}
+
+	changes, err := DiffSchemas(parseTables(t, "CREATE TABLE t AS other"), parseTables(t, "CREATE TABLE t AS another"))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.True(t, changes[0].Destructive)
+	require.Equal(t, "the table is not defined by a column list", changes[0].Reason)
+	require.True(t, changes[0].Stmt.(*CreateTable).OrReplace)
+	require.Empty(t, diffSchemas(t, "CREATE TABLE t AS other", "CREATE TABLE IF NOT EXISTS t AS other"))
+}
+
+func TestDiffSchemasColumns(t *testing.T) {
+	// appending a new column to the sorting key is done in place
+	require.Equal(t, []string{
+		"ALTER TABLE t ADD COLUMN b UInt8 AFTER a, MODIFY ORDER BY (a, b)",
+	}, diffSchemas(t,
+		"CREATE TABLE t (a UInt8) ENGINE = MergeTree ORDER BY a",
+		"CREATE TABLE t (a UInt8, b UInt8) ENGINE = MergeTree ORDER BY (a, b)"))
+
+	// a changed column is not taken for a rename
+	require.Equal(t, []string{
+		"ALTER TABLE t DROP COLUMN a -- destructive: drops column a",
+		"ALTER TABLE t ADD COLUMN b UInt16 FIRST",
+	}, diffSchemas(t,
+		"CREATE TABLE t (a UInt8) ENGINE = Memory",
+		"CREATE TABLE t (b UInt16) ENGINE = Memory"))
+
+	// a column that does not follow the same column is not taken for a
+	// rename either, and each dropped column is reported
+	require.Equal(t, []string{
+		"ALTER TABLE t DROP COLUMN b, DROP COLUMN d -- destructive: drops columns b, d",
+		"ALTER TABLE t ADD COLUMN c String AFTER a, ADD COLUMN bb UInt8 AFTER c",
+	}, diffSchemas(t,
+		"CREATE TABLE t (a UInt8, b UInt8, d UInt8) ENGINE = Memory",
+		"CREATE TABLE t (a UInt8, c String, bb UInt8) ENGINE = Memory"))
+
+	// column order is ignored
+	require.Empty(t, diffSchemas(t,
+		"CREATE TABLE t (a Int32, b Int32) ENGINE = Memory",
+		"CREATE TABLE t (b Int32, a Int32) ENGINE = Memory"))
+
+	// a new first column is added before the others
+	require.Equal(t, []string{
+		"ALTER TABLE t ADD COLUMN id UInt64 FIRST",
+	}, diffSchemas(t,
+		"CREATE TABLE t (a UInt8) ENGINE = Memory",
+		"CREATE TABLE t (id UInt64, a UInt8) ENGINE = Memory"))
+
+	// type aliases are not resolved
+	require.Equal(t, []string{
+		"ALTER TABLE t MODIFY COLUMN a Int32",
+	}, diffSchemas(t,
+		"CREATE TABLE t (a INT) ENGINE = Memory",
+		"CREATE TABLE t (a Int32) ENGINE = Memory"))
+
+	// only the removed properties are removed
+	require.Equal(t, []string{
+		"ALTER TABLE db.t ON CLUSTER main MODIFY COLUMN a REMOVE CODEC",
+		"ALTER TABLE db.t ON CLUSTER main MODIFY COLUMN a REMOVE TTL",
+	}, diffSchemas(t,
+		"CREATE TABLE db.t ON CLUSTER main (a DateTime CODEC(ZSTD) TTL a + INTERVAL 1 DAY) ENGINE = MergeTree ORDER BY tuple()",
+		"CREATE TABLE db.t ON CLUSTER main (a DateTime) ENGINE = MergeTree ORDER BY tuple()"))
+
+	// a table without TTL or settings gains them
+	require.Equal(t, []string{
+		"ALTER TABLE t MODIFY TTL d + INTERVAL 1 DAY",
+		"ALTER TABLE t MODIFY SETTING ttl_only_drop_parts=1",
+	}, diffSchemas(t,
+		"CREATE TABLE t (d Date) ENGINE = MergeTree ORDER BY d",
+		"CREATE TABLE t (d Date) ENGINE = MergeTree ORDER BY d TTL d + INTERVAL 1 DAY SETTINGS ttl_only_drop_parts = 1"))
+}
+
+func TestDiffSchemasErrors(t *testing.T) {
+	tables := parseTables(t, "CREATE TABLE t (a UInt8) ENGINE = Memory; CREATE TABLE t (b UInt8) ENGINE = Memory")
+	_, err := DiffSchemas(tables, nil)
+	require.EqualError(t, err, "duplicate table t in the current schema")
+	var duplicate *DuplicateTableError
+	require.True(t, errors.As(err, &duplicate))
+	require.Same(t, tables[1].Name, duplicate.Table)
+	_, err = DiffSchemas(nil, tables)
+	require.EqualError(t, err, "duplicate table t in the desired schema")
+}
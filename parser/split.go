@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// StatementSpan locates a statement of a script split by SplitStatements.
+type StatementSpan struct {
+	Start Pos // byte offset of the first token of the statement
+	End   Pos // byte offset just past its last token or its inline data
+	// Line and Column are the 1-based position of Start.
+	Line   int
+	Column int
+	Text   string // the statement, without the `;` that ends it
+}
+
+// SplitStatements cuts input into statements without parsing them, using the
+// lexer alone, so that a script can be split even if some of its statements
+// use syntax this parser does not support. A statement ends at a `;` outside
+// of brackets, strings, quoted identifiers, comments and heredocs, or at the
+// end of the inline data of an INSERT such as `INSERT INTO t FORMAT CSV 1,2`.
+// Empty statements are left out.
+//
+// It returns an error only if the input cannot be tokenized, for example
+// because a string is not closed.
+func SplitStatements(input string) ([]StatementSpan, error) {
+	starts := newLineStarts(input)
+	var spans []StatementSpan
+	for offset := 0; offset < len(input); {
+		bounds, _ := scanStatement(input[offset:], true)
+		if bounds.err != nil {
+			bounds.err.Pos += Pos(offset)
+			return nil, NewParser(input).wrapError(bounds.err)
+		}
+		if bounds.start < bounds.end {
+			start, end := offset+bounds.start, offset+bounds.end
+			line, column := starts.position(start)
+			spans = append(spans, StatementSpan{
+				Start:  Pos(start),
+				End:    Pos(end),
+				Line:   line,
+				Column: column,
+				Text:   input[start:end],
+			})
+		}
+		offset += bounds.next
+	}
+	return spans, nil
+}
+
+// stmtBounds locates a statement found by scanStatement.
+type stmtBounds struct {
+	start, end int // the statement is input[start:end], without surrounding blanks and comments
+	next       int // where the next statement starts, past the `;`
+	err        *ParseError
+}
+
+// scanStatement finds the statement at the start of input using the lexer
+// alone. It ends at the first `;` outside brackets, or at the end of the
+// inline data of an INSERT (see tryParseDataPayload). If the brackets of the
+// statement are not balanced, it settles for the first `;`. Input that cannot
+// be tokenized is skipped a character at a time, recording the first error.
+// A statement holding nothing but blanks and comments has start == end.
+//
+// If atEOF is false, input is a prefix of a longer text; scanStatement then
+// reports more when the end of the statement is not in input yet.
+func scanStatement(input string, atEOF bool) (bounds stmtBounds, more bool) {
+	lexer := NewLexer(input)
+	depth := 0
+	bounds.start = -1
+	// insertData is set while the statement is an INSERT that may still end
+	// with a FORMAT clause followed by inline data.
+	insertData := false
+	var prev *Token
+	var unbalanced *stmtBounds
+	done := func(next int) stmtBounds {
+		if bounds.start < 0 {
+			bounds.start = bounds.end
+		}
+		bounds.next = next
+		return bounds
+	}
+	for {
+		offset := lexer.offset
+		if err := lexer.consumeToken(); err != nil {
+			if !atEOF {
+				return stmtBounds{}, true
+			}
+			if bounds.err == nil {
+				bounds.err, _ = err.(*ParseError)
+			}
+			// Step over what cannot be tokenized; parsing the statement
+			// reports it.
+			if lexer.offset == offset {
+				_, size := utf8.DecodeRuneInString(input[offset:])
+				lexer.offset += max(size, 1)
+			}
+			lexer.currentToken = nil
+			if bounds.start < 0 {
+				bounds.start = offset
+			}
+			bounds.end = lexer.offset
+			continue
+		}
+		token := lexer.currentToken
+		if token == nil {
+			if !atEOF {
+				return stmtBounds{}, true
+			}
+			if depth != 0 && unbalanced != nil {
+				return *unbalanced, false
+			}
+			return done(len(input)), false
+		}
+		if token.Kind == ";" {
+			if depth == 0 {
+				return done(int(token.End)), false
+			}
+			if unbalanced == nil {
+				bounds := done(int(token.End))
+				unbalanced = &bounds
+			}
+		}
+		depth = nestingDepth(depth, token.Kind)
+
+		keyword := ""
+		if token.Kind == TokenKindKeyword {
+			keyword = strings.ToUpper(token.String)
+		}
+		switch {
+		case bounds.start < 0:
+			insertData = keyword == KeywordInsert
+		case depth > 0 || !insertData:
+		case keyword == KeywordSelect, keyword == KeywordWith, keyword == KeywordValues:
+			insertData = false
+		case prev.Kind == TokenKindKeyword && strings.EqualFold(prev.String, KeywordFrom) &&
+			token.Kind == TokenKindIdent && token.QuoteType == Unquoted && strings.EqualFold(token.String, KeywordInfile):
+			// INFILE is a context keyword, read as an identifier
+			insertData = false
+		case prev.Kind == TokenKindKeyword && strings.EqualFold(prev.String, KeywordFormat) &&
+			(token.Kind == TokenKindIdent || token.Kind == TokenKindKeyword):
+			if !atEOF && strings.TrimLeft(input[lexer.offset:], " \t\f\r\n") == "" {
+				return stmtBounds{}, true
+			}
+			isValues := strings.EqualFold(token.String, "Values")
+			if dataStart, dataEnd, ok := dataPayloadRange(input, lexer.offset, isValues); ok {
+				if !atEOF && dataEnd == len(input) {
+					return stmtBounds{}, true
+				}
+				bounds.end = lexer.offset
+				if dataStart < dataEnd {
+					bounds.end = dataEnd
+				}
+				return done(dataEnd), false
+			}
+		}
+		if bounds.start < 0 {
+			bounds.start = tokenStart(input, offset)
+		}
+		bounds.end = lexer.offset
+		prev = token
+	}
+}
+
+// tokenStart returns where the token after offset starts, past any blanks
+// and comments. Unlike Token.Pos, it includes the quotes of a string or a
+// quoted identifier.
+func tokenStart(input string, offset int) int {
+	lexer := NewLexer(input)
+	lexer.offset = offset
+	_ = lexer.skipComments()
+	lexer.skipSpace()
+	return lexer.offset
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	sql := "-- header; not a statement\n" +
+		"SELECT ';', `a;b`, \"c;d\" /* ; */ FROM t;\n" +
+		"CREATE FUNCTION f AS (x) -> concat(x, $$;'$$, $body$;$$;$body$);;\n" +
+		"SYSTEM FLUSH LOGS ON SOMETHING NEW(1; 2);\n" +
+		"  INSERT INTO t FORMAT CSV 1,'x;y'\n" +
+		"INSERT INTO t FORMAT TSV\n" +
+		";\n" +
+		"SELECT 1 -- trailing comment"
+	spans, err := SplitStatements(sql)
+	require.NoError(t, err)
+
+	texts := make([]string, 0, len(spans))
+	for _, span := range spans {
+		require.Equal(t, span.Text, sql[span.Start:span.End])
+		texts = append(texts, span.Text)
+	}
+	require.Equal(t, []string{
+		"SELECT ';', `a;b`, \"c;d\" /* ; */ FROM t",
+		"CREATE FUNCTION f AS (x) -> concat(x, $$;'$$, $body$;$$;$body$)",
+		"SYSTEM FLUSH LOGS ON SOMETHING NEW(1; 2)",
+		"INSERT INTO t FORMAT CSV 1,'x;y'",
+		"INSERT INTO t FORMAT TSV",
+		"SELECT 1",
+	}, texts)
+
+	require.Equal(t, 2, spans[0].Line)
+	require.Equal(t, 1, spans[0].Column)
+	require.Equal(t, 5, spans[3].Line)
+	require.Equal(t, 3, spans[3].Column)
+
+	// FROM INFILE reads no inline data, and infile is a table name otherwise
+	spans, err = SplitStatements("INSERT INTO t FROM INFILE 'a.csv' FORMAT CSV; INSERT INTO infile FORMAT CSV 1;2")
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+	require.Equal(t, "INSERT INTO t FROM INFILE 'a.csv' FORMAT CSV", spans[0].Text)
+	require.Equal(t, "INSERT INTO infile FORMAT CSV 1;2", spans[1].Text)
+}
+
+func TestSplitStatementsUnbalancedBrackets(t *testing.T) {
+	spans, err := SplitStatements("SELECT f(a FROM t; SELECT 2")
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+	require.Equal(t, "SELECT f(a FROM t", spans[0].Text)
+	require.Equal(t, "SELECT 2", spans[1].Text)
+
+	// brackets that are balanced by the end keep the `;` inside them
+	spans, err = SplitStatements("SELECT (1;2)")
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	require.Equal(t, "SELECT (1;2)", spans[0].Text)
+}
+
+func TestSplitStatementsErrors(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT 1;\nSELECT 'unclosed",
+		"SELECT 1;\nSELECT $tag$unclosed$$",
+		"SELECT 1;\nSELECT 1 /* unclosed",
+	} {
+		_, err := SplitStatements(sql)
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe), sql)
+		require.Equal(t, ErrCodeInvalidToken, pe.Code)
+		require.Equal(t, 2, pe.Line)
+	}
+}
@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// streamReadSize is the least a StreamParser reads from its reader at a time.
+const streamReadSize = 64 << 10
+
+// StreamParser parses statements one at a time from an io.Reader, so that a
+// SQL dump far larger than memory can be parsed: only the statement being
+// parsed is buffered, and nothing refers to a statement's AST once Next has
+// returned it.
+//
+// Positions in the returned ASTs and errors are byte offsets, lines and
+// columns in the whole stream.
+type StreamParser struct {
+	reader  io.Reader
+	buf     []byte // read but not yet parsed
+	eof     bool
+	readErr error
+
+	// the position of buf[0] in the stream
+	offset int
+	line   int
+	column int
+}
+
+// NewStreamParser returns a StreamParser that reads statements from r.
+func NewStreamParser(r io.Reader) *StreamParser {
+	return &StreamParser{
+		reader: r,
+		line:   1,
+		column: 1,
+	}
+}
+
+// Next parses and returns the next statement in the stream. It returns
+// io.EOF once the stream is exhausted, and the error of the reader if reading
+// fails. A statement that fails to parse is reported as a *ParseError; the
+// following call continues with the statement after it.
+func (s *StreamParser) Next() (Expr, error) {
+	for {
+		bounds, err := s.scan()
+		if err != nil {
+			return nil, err
+		}
+		text := string(s.buf[:bounds.end])
+		offset, line, column := s.offset, s.line, s.column
+		s.advance(bounds.next)
+		if bounds.start == bounds.end {
+			continue
+		}
+		return parseStreamStmt(text, offset, line, column)
+	}
+}
+
+// scan finds the end of the statement at the start of the buffer, reading
+// more of the stream until it is buffered whole.
+func (s *StreamParser) scan() (stmtBounds, error) {
+	for {
+		// after a read error, a statement is complete only if it ends
+		// before the error
+		atEOF := s.eof && s.readErr == nil
+		if len(s.buf) > 0 {
+			if bounds, more := scanStatement(string(s.buf), atEOF); !more {
+				return bounds, nil
+			}
+		} else if atEOF {
+			return stmtBounds{}, io.EOF
+		}
+		if s.eof {
+			return stmtBounds{}, s.readErr
+		}
+		// read at least as much as is buffered, so that rescanning the
+		// buffer takes linear time overall
+		s.fill(max(streamReadSize, len(s.buf)))
+	}
+}
+
+// fill reads at least n more bytes into the buffer, unless the stream ends
+// first.
+func (s *StreamParser) fill(n int) {
+	want := len(s.buf) + n
+	if cap(s.buf) < want {
+		buf := make([]byte, len(s.buf), want)
+		copy(buf, s.buf)
+		s.buf = buf
+	}
+	for len(s.buf) < want && !s.eof {
+		read, err := s.reader.Read(s.buf[len(s.buf):want])
+		s.buf = s.buf[:len(s.buf)+read]
+		if err != nil {
+			s.eof = true
+			if !errors.Is(err, io.EOF) {
+				s.readErr = err
+			}
+		}
+	}
+}
+
+// advance drops the first n bytes of the buffer, keeping track of the
+// position of what remains.
+func (s *StreamParser) advance(n int) {
+	consumed := string(s.buf[:n])
+	if i := strings.LastIndexByte(consumed, '\n'); i >= 0 {
+		s.line += strings.Count(consumed, "\n")
+		s.column = n - i
+	} else {
+		s.column += n
+	}
+	s.offset += n
+	// copy the rest, so that the buffer does not keep growing
+	s.buf = s.buf[:copy(s.buf, s.buf[n:])]
+}
+
+// parseStreamStmt parses text, a single statement at the given stream offset,
+// line and column, and moves the positions of its AST or error there.
+func parseStreamStmt(text string, offset, line, column int) (Expr, error) {
+	// The text is parsed after a blank, so that no node starts at offset 0,
+	// which the AST uses for positions of absent tokens.
+	p := NewParser(" " + text)
+	delta := Pos(offset - 1)
+	if err := p.lexer.consumeToken(); err != nil {
+		return nil, shiftError(p.wrapError(err), delta, line, column)
+	}
+	stmt, err := p.parseStmt(p.Pos())
+	if err != nil {
+		return nil, shiftError(p.wrapError(err), delta, line, column)
+	}
+	shiftPositions(reflect.ValueOf(stmt), delta, map[uintptr]bool{})
+	return stmt, nil
+}
+
+func shiftError(err error, delta Pos, line, column int) error {
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return err
+	}
+	if pe.Line == 1 {
+		// minus the blank parseStreamStmt put before the statement
+		pe.columnOffset = column - 2
+		pe.Column += pe.columnOffset
+	}
+	pe.lineOffset = line - 1
+	pe.Line += pe.lineOffset
+	pe.Pos += delta
+	if pe.Got != nil {
+		got := *pe.Got
+		got.Pos += delta
+		got.End += delta
+		pe.Got = &got
+	}
+	return pe
+}
+
+// shiftPositions adds delta to every position in the tree at v other than
+// the zero positions of absent tokens, visiting each node once even if it is
+// shared.
+func shiftPositions(v reflect.Value, delta Pos, visited map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+		shiftPositions(v.Elem(), delta, visited)
+	case reflect.Interface:
+		if !v.IsNil() {
+			shiftPositions(v.Elem(), delta, visited)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			switch {
+			case !field.CanSet():
+			case field.Type() == posType:
+				if field.Int() != 0 {
+					field.SetInt(field.Int() + int64(delta))
+				}
+			default:
+				shiftPositions(field, delta, visited)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			shiftPositions(v.Index(i), delta, visited)
+		}
+	}
+}
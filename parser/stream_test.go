@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readStream(t *testing.T, s *StreamParser) ([]Expr, []error) {
+	t.Helper()
+	var stmts []Expr
+	var errs []error
+	for {
+		stmt, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return stmts, errs
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+}
+
+func TestStreamParser(t *testing.T) {
+	sql := "-- leading comment\n" +
+		"SELECT a, [1, 2] FROM t WHERE b = ';';\n" +
+		"CREATE TABLE t (a Int32, b String) ENGINE = MergeTree ORDER BY a; ;\n" +
+		"  INSERT INTO t FORMAT CSV 1,'x;y'\n" +
+		"SELECT toDate('2024-01-01') AS d; /* trailing comment */\n" +
+		"INSERT INTO t FORMAT JSONEachRow\n" +
+		"{\"a\": 1} {\"a\": 2}\n" +
+		"INSERT INTO t FORMAT TSV\n" +
+		";\n" +
+		"ALTER TABLE t DROP COLUMN b"
+	expected, err := NewParser(sql).ParseStmts()
+	require.NoError(t, err)
+
+	// Reading a byte at a time splits every token across reads.
+	stmts, errs := readStream(t, NewStreamParser(iotest.OneByteReader(strings.NewReader(sql))))
+	require.Empty(t, errs)
+	require.Equal(t, expected, stmts)
+	require.Equal(t, "ALTER TABLE t DROP COLUMN b", sql[stmts[len(stmts)-1].Pos():stmts[len(stmts)-1].End()])
+
+	insert := stmts[2].(*InsertStmt)
+	require.Equal(t, "1,'x;y'", insert.Data.Data)
+}
+
+func TestStreamParserErrors(t *testing.T) {
+	sql := "SELECT 1;\n" +
+		"SELECT 2; SELECT (1 FROM t;\n" +
+		"SELECT 3"
+	s := NewStreamParser(strings.NewReader(sql))
+
+	stmt, err := s.Next()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 1", Format(stmt))
+	_, err = s.Next()
+	require.NoError(t, err)
+
+	_, err = s.Next()
+	var pe *ParseError
+	require.True(t, errors.As(err, &pe))
+	require.Equal(t, 2, pe.Line)
+	require.Equal(t, 21, pe.Column)
+	require.Equal(t, Pos(strings.Index(sql, "FROM t")), pe.Pos)
+	require.Equal(t, "line 2:21 expected ')', but got '<keyword>'\n"+
+		"          SELECT (1 FROM t\n"+
+		"                    ^^^^\n", pe.Error())
+
+	stmt, err = s.Next()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 3", Format(stmt))
+	require.Equal(t, Pos(strings.Index(sql, "SELECT 3")), stmt.Pos())
+
+	_, err = s.Next()
+	require.ErrorIs(t, err, io.EOF)
+	_, err = s.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamParserReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	s := NewStreamParser(io.MultiReader(strings.NewReader("SELECT 1; SELECT"), iotest.ErrReader(readErr)))
+
+	stmt, err := s.Next()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 1", Format(stmt))
+	_, err = s.Next()
+	require.ErrorIs(t, err, readErr)
+}
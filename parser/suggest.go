@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the "did you mean" candidates attached to an error.
+const maxSuggestions = 3
+
+// knownFormats are the input and output formats of ClickHouse, including
+// their aliases, as spelled in the documentation.
+var knownFormats = []string{
+	"Arrow", "ArrowStream", "Avro", "AvroConfluent", "BSONEachRow", "Buffers",
+	"CapnProto", "CSV", "CSVWithNames", "CSVWithNamesAndTypes",
+	"CustomSeparated", "CustomSeparatedIgnoreSpaces",
+	"CustomSeparatedIgnoreSpacesWithNames", "CustomSeparatedIgnoreSpacesWithNamesAndTypes",
+	"CustomSeparatedWithNames", "CustomSeparatedWithNamesAndTypes",
+	"DWARF", "Form", "Hash", "JSON", "JSONAsObject", "JSONAsString",
+	"JSONColumns", "JSONColumnsWithMetadata", "JSONCompact", "JSONCompactColumns",
+	"JSONCompactEachRow", "JSONCompactEachRowWithNames", "JSONCompactEachRowWithNamesAndTypes",
+	"JSONCompactEachRowWithProgress", "JSONCompactStrings", "JSONCompactStringsEachRow",
+	"JSONCompactStringsEachRowWithNames", "JSONCompactStringsEachRowWithNamesAndTypes",
+	"JSONCompactStringsEachRowWithProgress", "JSONEachRow", "JSONEachRowWithProgress",
+	"JSONLines", "JSONObjectEachRow", "JSONStrings", "JSONStringsEachRow",
+	"JSONStringsEachRowWithProgress", "LineAsString", "LineAsStringWithNames",
+	"LineAsStringWithNamesAndTypes", "Markdown", "MD", "MsgPack", "MySQLDump",
+	"MySQLWire", "Native", "NDJSON", "Npy", "Null", "ODBCDriver2", "One", "ORC",
+	"Parquet", "ParquetMetadata", "PostgreSQLWire", "Pretty", "PrettyCompact",
+	"PrettyCompactMonoBlock", "PrettyCompactNoEscapes", "PrettyCompactNoEscapesMonoBlock",
+	"PrettyJSONEachRow", "PrettyJSONLines", "PrettyMonoBlock", "PrettyNDJSON",
+	"PrettyNoEscapes", "PrettyNoEscapesMonoBlock", "PrettySpace", "PrettySpaceMonoBlock",
+	"PrettySpaceNoEscapes", "PrettySpaceNoEscapesMonoBlock", "Prometheus", "Protobuf",
+	"ProtobufList", "ProtobufSingle", "Raw", "RawBLOB", "Regexp", "RowBinary",
+	"RowBinaryWithDefaults", "RowBinaryWithNames", "RowBinaryWithNamesAndTypes",
+	"SQLInsert", "TabSeparated", "TabSeparatedRaw", "TabSeparatedRawWithNames",
+	"TabSeparatedRawWithNamesAndTypes", "TabSeparatedWithNames",
+	"TabSeparatedWithNamesAndTypes", "Template", "TemplateIgnoreSpaces", "TSKV", "TSV",
+	"TSVRaw", "TSVRawWithNames", "TSVRawWithNamesAndTypes", "TSVWithNames",
+	"TSVWithNamesAndTypes", "Values", "Vertical", "XML",
+}
+
+// knownEngines are the table and database engines of ClickHouse.
+var knownEngines = []string{
+	"AggregatingMergeTree", "Atomic", "AzureBlobStorage", "AzureQueue", "Backup",
+	"Buffer", "CoalescingMergeTree", "CollapsingMergeTree", "DeltaLake", "Dictionary",
+	"Distributed", "EmbeddedRocksDB", "Executable", "ExecutablePool",
+	"ExternalDistributed", "File", "FileLog", "GenerateRandom", "GraphiteMergeTree",
+	"HDFS", "Hive", "Hudi", "Iceberg", "IcebergS3", "JDBC", "Join", "Kafka",
+	"KeeperMap", "Lazy", "Log", "Loop", "MaterializedMySQL", "MaterializedPostgreSQL",
+	"MaterializedView", "Memory", "Merge", "MergeTree", "MongoDB", "MySQL", "NATS",
+	"Null", "ODBC", "Ordinary", "PostgreSQL", "RabbitMQ", "Redis", "Replicated",
+	"ReplicatedAggregatingMergeTree", "ReplicatedCoalescingMergeTree",
+	"ReplicatedCollapsingMergeTree", "ReplicatedGraphiteMergeTree", "ReplicatedMergeTree",
+	"ReplicatedReplacingMergeTree", "ReplicatedSummingMergeTree",
+	"ReplicatedVersionedCollapsingMergeTree", "ReplacingMergeTree", "S3", "S3Queue",
+	"Set", "SQLite", "StripeLog", "SummingMergeTree", "TimeSeries", "TinyLog", "URL",
+	"VersionedCollapsingMergeTree", "View",
+}
+
+// WithNameHints makes the parser check the format and engine names it reads
+// against the ones ClickHouse documents. A name that is not listed but is a
+// near miss of one that is, such as JSONEachRows, is recorded as a hint with
+// the code ErrCodeUnknownName and its suggestions; NameHints returns them.
+// Parsing goes on either way, so names this list does not know keep parsing.
+func (p *Parser) WithNameHints() *Parser {
+	p.nameHints = true
+	return p
+}
+
+// NameHints returns the hints recorded so far, in input order. It is empty
+// unless WithNameHints was called before parsing.
+func (p *Parser) NameHints() []*ParseError {
+	return p.hints
+}
+
+// checkKnownName records a hint if a format or engine name is not known but
+// is a near miss of one that is.
+func (p *Parser) checkKnownName(ident *Ident, kind string, known []string) {
+	if !p.nameHints || ident.QuoteType != Unquoted {
+		return
+	}
+	for _, hint := range p.hints {
+		if hint.Pos == ident.Pos() {
+			// read again after backtracking
+			return
+		}
+	}
+	for _, name := range known {
+		if strings.EqualFold(ident.Name, name) {
+			return
+		}
+	}
+	suggestions := suggest(ident.Name, known)
+	if len(suggestions) == 0 {
+		return
+	}
+	pe := errorAt(ident.Pos(), ErrCodeUnknownName, "unknown %s %s", kind, ident.Name)
+	pe.Got = &Token{Pos: ident.NamePos, End: ident.NameEnd, Kind: TokenKindIdent, String: ident.Name}
+	pe.Suggestions = suggestions
+	p.hints = append(p.hints, p.wrapError(pe).(*ParseError))
+}
+
+// addSuggestions fills in the suggestions of an error at a misspelled word.
+// The word the error stopped at is matched against the expected keywords and
+// token kinds, including the clause keywords where the statement may end, or
+// against the whole keyword table if nothing in particular was expected. The
+// name just before it, which the parser may have taken for an alias (as in
+// `GRUOP BY`), is matched against the clause keywords.
+func (p *Parser) addSuggestions(pe *ParseError) {
+	if pe.Suggestions != nil {
+		return
+	}
+	if pe.Got != nil && pe.Got.Kind != TokenKindString {
+		candidates := append([]string(nil), pe.Keywords...)
+		for _, kind := range pe.Expected {
+			switch {
+			case kind == TokenKindEOF:
+				// the statement may end here, so the next clause may start here
+				candidates = append(candidates, recoveryClauseKeywords...)
+			case !strings.HasPrefix(string(kind), "<"):
+				candidates = append(candidates, string(kind))
+			}
+		}
+		if len(pe.Expected) == 0 && len(pe.Keywords) == 0 {
+			candidates = keywords.Members()
+		}
+		if pe.Suggestions = suggest(pe.Got.String, candidates); pe.Suggestions != nil {
+			return
+		}
+	}
+	if prev := p.tokenBefore(pe.Pos); prev != nil && prev.Kind == TokenKindIdent && prev.QuoteType == Unquoted {
+		pe.Suggestions = suggest(prev.String, recoveryClauseKeywords)
+	}
+}
+
+// tokenBefore returns the token the lexer read before the one at pos, if the
+// error at pos was raised at the current token.
+func (p *Parser) tokenBefore(pos Pos) *Token {
+	if current := p.current(); current != nil && current.Pos != pos {
+		return nil
+	}
+	if prev := p.lexer.prevToken; prev != nil && prev.End <= pos {
+		return prev
+	}
+	return nil
+}
+
+// suggest returns the candidates closest to word, ignoring case, if they are
+// close enough to be a likely misspelling of it. Words shorter than three
+// characters are too ambiguous to suggest anything for.
+func suggest(word string, candidates []string) []string {
+	if len(word) < 3 {
+		return nil
+	}
+	maxDistance := min(max(len(word)/4, 1), 3)
+	upper := strings.ToUpper(word)
+
+	best := maxDistance + 1
+	var suggestions []string
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, word) {
+			// the word is spelled right; the error is about something else
+			return nil
+		}
+		distance := editDistance(upper, strings.ToUpper(candidate))
+		switch {
+		case distance < best:
+			best = distance
+			suggestions = []string{candidate}
+		case distance == best:
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	sort.Strings(suggestions)
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// the number of single-character insertions, deletions, substitutions and
+// transpositions of adjacent characters that turn one into the other.
+func editDistance(a, b string) int {
+	// three rows of the dynamic-programming table are enough for transpositions
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
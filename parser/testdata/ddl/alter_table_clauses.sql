@@ -0,0 +1,13 @@
+ALTER TABLE db.t MOVE PARTITION 2024 TO DISK 'fast_ssd';
+ALTER TABLE db.t MOVE PARTITION ID '202401' TO VOLUME 'cold';
+ALTER TABLE db.t ON CLUSTER cluster_1 MOVE PARTITION tuple() TO TABLE db.t_archive;
+ALTER TABLE db.t FETCH PARTITION 2024 FROM '/clickhouse/tables/01-01/visits';
+ALTER TABLE db.t ADD CONSTRAINT IF NOT EXISTS id_positive CHECK id > 0, ADD CONSTRAINT c_assume ASSUME host = domain(url);
+ALTER TABLE db.t DROP CONSTRAINT IF EXISTS id_positive, DROP CONSTRAINT c_assume;
+ALTER TABLE db.t COMMENT COLUMN IF EXISTS id 'The user id', COMMENT COLUMN name 'The display name';
+ALTER TABLE db.t MODIFY COMMENT 'Visits of the site';
+ALTER TABLE db.t MODIFY SAMPLE BY intHash32(user_id);
+ALTER TABLE db.t APPLY DELETED MASK;
+ALTER TABLE db.t APPLY DELETED MASK IN PARTITION 2024;
+ALTER TABLE db.t DELETE IN PARTITION ID '202401' WHERE id = 1;
+ALTER TABLE db.t UPDATE name = 'x' IN PARTITION 2024 WHERE id = 1;
@@ -0,0 +1,12 @@
+ALTER USER u1;
+ALTER USER IF EXISTS u1 ON CLUSTER cluster_1 RENAME TO u2;
+ALTER USER u1 RENAME TO u2, u3 RENAME TO u4;
+ALTER USER u1 IDENTIFIED WITH sha256_password BY 'qwerty';
+ALTER USER u1 NOT IDENTIFIED;
+ALTER USER u1 HOST LOCAL, HOST IP '192.168.0.0/16';
+ALTER USER u1 ADD HOST NAME 'example.com' DROP HOST LOCAL;
+ALTER USER u1 VALID UNTIL '2026-01-01';
+ALTER USER u1 DEFAULT ROLE r1, r2;
+ALTER USER u1 DEFAULT DATABASE NONE;
+ALTER USER u1 GRANTEES ANY EXCEPT u2;
+ALTER USER u1 SETTINGS max_memory_usage=10000000 READONLY;
@@ -0,0 +1,11 @@
+CREATE TABLE db.visits
+(
+    user_id UInt64,
+    url String,
+    host String,
+    CONSTRAINT user_known CHECK user_id != 0,
+    CONSTRAINT host_is_domain ASSUME host = domain(url),
+    CONSTRAINT url_not_empty ASSUME notEmpty(url)
+)
+ENGINE = MergeTree
+ORDER BY user_id;
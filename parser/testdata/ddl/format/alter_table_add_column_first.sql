@@ -0,0 +1,8 @@
+-- Origin SQL:
+ALTER TABLE db.events ADD COLUMN id UInt64 FIRST;
+ALTER TABLE db.events ON CLUSTER main ADD COLUMN IF NOT EXISTS tenant String DEFAULT '' FIRST SETTINGS mutations_sync = 2;
+
+
+-- Format SQL:
+ALTER TABLE db.events ADD COLUMN id UInt64 FIRST;
+ALTER TABLE db.events ON CLUSTER main ADD COLUMN IF NOT EXISTS tenant String DEFAULT '' FIRST SETTINGS mutations_sync=2;
@@ -0,0 +1,39 @@
+-- Origin SQL:
+CREATE QUOTA q1;
+CREATE QUOTA IF NOT EXISTS q1 ON CLUSTER cluster_1 IN local_directory;
+CREATE QUOTA OR REPLACE q1 KEYED BY client_key, user_name FOR INTERVAL 30 minute MAX queries = 123 TO r1, r2;
+CREATE QUOTA q2 NOT KEYED FOR RANDOMIZED INTERVAL 1 hour MAX queries = 100, errors = 10, FOR INTERVAL 1 day NO LIMITS TO ALL EXCEPT r1;
+CREATE QUOTA q3 FOR INTERVAL 1 week MAX queries = 10, MAX execution_time = 0.5, FOR INTERVAL 1 year TRACKING ONLY TO NONE;
+ALTER QUOTA q1;
+ALTER QUOTA IF EXISTS q1 RENAME TO q2 KEYED BY ip_address FOR INTERVAL 1 hour MAX result_rows = 1000 TO ALL;
+ALTER QUOTA q1, q2 NOT KEYED FOR INTERVAL 1 minute NO LIMITS;
+DROP QUOTA q1;
+DROP QUOTA IF EXISTS q1, q2 ON CLUSTER cluster_1;
+
+
+-- Beautify SQL:
+CREATE QUOTA q1;
+CREATE QUOTA IF NOT EXISTS q1 ON CLUSTER cluster_1 IN local_directory;
+CREATE QUOTA OR REPLACE q1
+KEYED BY client_key, user_name
+FOR INTERVAL 30 minute MAX queries=123
+TO r1, r2;
+CREATE QUOTA q2
+NOT KEYED
+FOR RANDOMIZED INTERVAL 1 hour MAX queries=100, errors=10,
+FOR INTERVAL 1 day NO LIMITS
+TO ALL EXCEPT r1;
+CREATE QUOTA q3
+FOR INTERVAL 1 week MAX queries=10, execution_time=0.5,
+FOR INTERVAL 1 year TRACKING ONLY
+TO NONE;
+ALTER QUOTA q1;
+ALTER QUOTA IF EXISTS q1 RENAME TO q2
+KEYED BY ip_address
+FOR INTERVAL 1 hour MAX result_rows=1000
+TO ALL;
+ALTER QUOTA q1, q2
+NOT KEYED
+FOR INTERVAL 1 minute NO LIMITS;
+DROP QUOTA q1;
+DROP QUOTA IF EXISTS q1, q2 ON CLUSTER cluster_1;
@@ -0,0 +1,13 @@
+-- Origin SQL:
+CREATE TABLE db.metrics
+(
+    ts DateTime COMMENT 'sample time' CODEC(DoubleDelta, ZSTD(1)),
+    value Float64 DEFAULT 0 COMMENT 'sample value' CODEC(Gorilla) TTL ts + INTERVAL 30 DAY,
+    host LowCardinality(String) COMMENT 'reporting host'
+)
+ENGINE = MergeTree
+ORDER BY ts;
+
+
+-- Format SQL:
+CREATE TABLE db.metrics (ts DateTime COMMENT 'sample time' CODEC(DoubleDelta, ZSTD(1)), value Float64 DEFAULT 0 COMMENT 'sample value' CODEC(Gorilla) TTL ts + INTERVAL 30 DAY, host LowCardinality(String) COMMENT 'reporting host') ENGINE = MergeTree ORDER BY ts;
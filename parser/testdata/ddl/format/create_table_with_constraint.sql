@@ -0,0 +1,15 @@
+-- Origin SQL:
+CREATE TABLE IF NOT EXISTS db.hits
+(
+    id UInt64,
+    url String COMMENT 'the page' CODEC(ZSTD(1)),
+    host String,
+    CONSTRAINT id_positive CHECK id > 0,
+    CONSTRAINT host_from_url ASSUME host = domain(url)
+)
+ENGINE = MergeTree
+ORDER BY id;
+
+
+-- Format SQL:
+CREATE TABLE IF NOT EXISTS db.hits (id UInt64, url String COMMENT 'the page' CODEC(ZSTD(1)), host String, CONSTRAINT id_positive CHECK id > 0, CONSTRAINT host_from_url ASSUME host = domain(url)) ENGINE = MergeTree ORDER BY id;
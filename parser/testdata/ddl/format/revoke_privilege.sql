@@ -0,0 +1,28 @@
+-- Origin SQL:
+REVOKE SELECT(x,y) ON db.table FROM john;
+REVOKE ON CLUSTER 'default' SELECT, INSERT ON db.* FROM john, mary;
+REVOKE ALL ON *.* FROM ALL;
+REVOKE SELECT ON accounts.* FROM ALL EXCEPT admin_role, CURRENT_USER;
+REVOKE dictGet ON *.* FROM select_all_role;
+REVOKE GRANT OPTION FOR SELECT(x, y, z), INSERT ON database.table_1 FROM table_1_select_role;
+REVOKE ADMIN OPTION ON *.* FROM select_all_role;
+REVOKE accountant FROM john;
+REVOKE ADMIN OPTION FOR accountant, auditor FROM john, mary;
+REVOKE ON CLUSTER my_cluster accountant FROM ALL EXCEPT john;
+REVOKE admin FROM john;
+REVOKE admin, accountant FROM john;
+
+
+-- Format SQL:
+REVOKE SELECT(x, y) ON db.table FROM john;
+REVOKE ON CLUSTER 'default' SELECT, INSERT ON db.* FROM john, mary;
+REVOKE ALL ON *.* FROM ALL;
+REVOKE SELECT ON accounts.* FROM ALL EXCEPT admin_role, CURRENT_USER;
+REVOKE dictGet ON *.* FROM select_all_role;
+REVOKE GRANT OPTION FOR SELECT(x, y, z), INSERT ON database.table_1 FROM table_1_select_role;
+REVOKE ADMIN OPTION ON *.* FROM select_all_role;
+REVOKE accountant FROM john;
+REVOKE ADMIN OPTION FOR accountant, auditor FROM john, mary;
+REVOKE ON CLUSTER my_cluster accountant FROM ALL EXCEPT john;
+REVOKE admin FROM john;
+REVOKE admin, accountant FROM john;
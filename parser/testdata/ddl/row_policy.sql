@@ -0,0 +1,8 @@
+CREATE ROW POLICY p1 ON db.t1 USING a = 1 TO r1;
+CREATE ROW POLICY IF NOT EXISTS p1 ON CLUSTER cluster_1 ON db.t1, p2 ON db.* IN local_directory FOR SELECT USING b > 10 AND c != 'x' AS RESTRICTIVE TO ALL EXCEPT r1, r2;
+CREATE POLICY OR REPLACE p1 ON t1 USING 1 AS PERMISSIVE TO NONE;
+ALTER ROW POLICY p1 ON db.t1 RENAME TO p2;
+ALTER POLICY IF EXISTS p1 ON db.t1, p2 ON db.t2 AS PERMISSIVE FOR SELECT USING NONE TO r1;
+ALTER ROW POLICY p1 ON t1 USING id IN (1, 2, 3);
+DROP ROW POLICY p1 ON db.t1;
+DROP POLICY IF EXISTS p1, p2 ON db.t1, p3 ON db.t2 ON CLUSTER cluster_1 FROM local_directory;
@@ -0,0 +1,7 @@
+CREATE SETTINGS PROFILE s1;
+CREATE SETTINGS PROFILE IF NOT EXISTS s1, s2 ON CLUSTER cluster_1 IN local_directory SETTINGS max_memory_usage=10000000 MIN 5000000 MAX 20000000 READONLY TO r1;
+CREATE PROFILE OR REPLACE s3 SETTINGS INHERIT 'default', readonly=1 TO ALL EXCEPT r1;
+ALTER SETTINGS PROFILE s1 RENAME TO s2 SETTINGS max_threads=8 TO NONE;
+ALTER PROFILE IF EXISTS s1, s2 SETTINGS NONE;
+DROP SETTINGS PROFILE s1;
+DROP PROFILE IF EXISTS s1, s2 ON CLUSTER cluster_1;
@@ -0,0 +1,24 @@
+SHOW CREATE VIEW db.v1;
+SHOW CREATE DICTIONARY dict1 FORMAT TabSeparatedRaw;
+SHOW CREATE DATABASE db1;
+SHOW CREATE USER;
+SHOW CREATE USER u1, u2@'%';
+SHOW CREATE ROLE r1;
+SHOW TABLES FROM db1 NOT LIKE 'tmp%' LIMIT 10;
+SHOW TABLES IN system ILIKE '%log';
+SHOW COLUMNS FROM t1 FROM db1 LIKE 'id%';
+SHOW COLUMNS IN db1.t1 INTO OUTFILE '/tmp/columns.txt';
+SHOW DICTIONARIES FROM db1 LIKE 'dict%' LIMIT 2;
+SHOW PROCESSLIST;
+SHOW PROCESSLIST INTO OUTFILE '/tmp/processes.txt' FORMAT CSV;
+SHOW GRANTS;
+SHOW GRANTS FOR u1, r1;
+SHOW SETTINGS LIKE 'send_timeout';
+SHOW SETTINGS ILIKE '%CONNECT_timeout%';
+SHOW FUNCTIONS;
+SHOW FUNCTIONS ILIKE 'to%';
+SHOW INDEX FROM t1;
+SHOW INDEXES IN t1 IN db1 FORMAT JSON;
+SHOW KEYS FROM db1.t1;
+SHOW ENGINES;
+SHOW ENGINES INTO OUTFILE '/tmp/engines.txt' FORMAT TabSeparated;
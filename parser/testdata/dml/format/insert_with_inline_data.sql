@@ -0,0 +1,26 @@
+-- Origin SQL:
+INSERT INTO t FORMAT CSV
+1,"a,b"
+INSERT INTO t FORMAT JSONEachRow {"id": 1, "name": "x"} {"id": 2, "name": "y; z"}
+INSERT INTO t (id, name) FORMAT Values (1, 'a;b'), (2, NULL);
+INSERT INTO t FORMAT TabSeparated
+1	a
+SELECT 1;
+INSERT INTO t SETTINGS async_insert=1 FORMAT JSONEachRow;
+
+
+-- Format SQL:
+INSERT INTO t FORMAT CSV
+1,"a,b"
+;
+INSERT INTO t FORMAT JSONEachRow
+{"id": 1, "name": "x"} {"id": 2, "name": "y; z"}
+;
+INSERT INTO t (id, name) FORMAT Values
+(1, 'a;b'), (2, NULL)
+;
+INSERT INTO t FORMAT TabSeparated
+1	a
+;
+SELECT 1;
+INSERT INTO t SETTINGS async_insert=1 FORMAT JSONEachRow;
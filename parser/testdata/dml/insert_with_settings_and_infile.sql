@@ -0,0 +1,8 @@
+INSERT INTO t SETTINGS async_insert=1, wait_for_async_insert=0 VALUES (1, 'a'), (2, 'b');
+INSERT INTO t (id, name) SETTINGS async_insert=1 FORMAT JSONEachRow;
+INSERT INTO t FROM INFILE 'data.csv.gz' COMPRESSION 'gzip' FORMAT CSV;
+INSERT INTO TABLE db.t (a, b) FROM INFILE 'input_*.csv' SETTINGS input_format_allow_errors_num=10 FORMAT CSVWithNames;
+INSERT INTO t (* EXCEPT (id)) VALUES ('a', 1);
+INSERT INTO t (*) SELECT * FROM src;
+INSERT INTO t WITH recent AS (SELECT * FROM events WHERE ts > now() - INTERVAL 1 DAY) SELECT id, count() FROM recent GROUP BY id;
+INSERT INTO t (a, b) SETTINGS max_insert_threads=4 WITH 1 AS x SELECT x, 2 UNION ALL SELECT 3, 4;
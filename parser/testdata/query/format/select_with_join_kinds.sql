@@ -0,0 +1,13 @@
+-- Origin SQL:
+SELECT *
+FROM t0, t1
+    left any join t2 ON t1.a = t2.a
+    GLOBAL SEMI LEFT JOIN t3 USING (a)
+    RIGHT OUTER ALL JOIN t4 ON t1.b = t4.b
+    ASOF JOIN t5 ON t1.a = t5.a AND t1.ts >= t5.ts
+    PASTE JOIN t6
+    LEFT ARRAY JOIN arr AS x;
+
+
+-- Format SQL:
+SELECT * FROM t0,t1 ANY LEFT JOIN t2 ON t1.a = t2.a GLOBAL SEMI LEFT JOIN t3 USING a ALL RIGHT OUTER JOIN t4 ON t1.b = t4.b ASOF JOIN t5 ON t1.a = t5.a AND t1.ts >= t5.ts PASTE JOIN t6 LEFT ARRAY JOIN arr AS x;
@@ -0,0 +1,18 @@
+-- Origin SQL:
+SELECT a FROM t1 UNION ALL SELECT b FROM t2 INTERSECT SELECT c FROM t3 EXCEPT DISTINCT SELECT d FROM t4;
+SELECT 1 EXCEPT SELECT 2 UNION SELECT 3;
+SELECT x FROM t1 INTERSECT DISTINCT SELECT x FROM t2;
+SELECT * EXCEPT (id) FROM t1 EXCEPT ALL SELECT * EXCEPT (id) FROM t2;
+(SELECT a FROM t1 ORDER BY a DESC LIMIT 10) UNION ALL (SELECT a FROM t2 ORDER BY a LIMIT 5);
+SELECT a FROM t1 UNION DISTINCT (SELECT a FROM t2 UNION ALL SELECT a FROM t3);
+SELECT * FROM (SELECT 1 AS x UNION ALL SELECT 2 AS x INTERSECT SELECT 2) WHERE x IN (SELECT 1 UNION ALL SELECT 2);
+
+
+-- Format SQL:
+SELECT a FROM t1 UNION ALL SELECT b FROM t2 INTERSECT SELECT c FROM t3 EXCEPT DISTINCT SELECT d FROM t4;
+SELECT 1 EXCEPT SELECT 2 UNION SELECT 3;
+SELECT x FROM t1 INTERSECT DISTINCT SELECT x FROM t2;
+SELECT * EXCEPT(id) FROM t1 EXCEPT ALL SELECT * EXCEPT(id) FROM t2;
+(SELECT a FROM t1 ORDER BY a DESC LIMIT 10) UNION ALL (SELECT a FROM t2 ORDER BY a LIMIT 5);
+SELECT a FROM t1 UNION DISTINCT (SELECT a FROM t2 UNION ALL SELECT a FROM t3);
+SELECT * FROM (SELECT 1 AS x UNION ALL SELECT 2 AS x INTERSECT SELECT 2) WHERE x IN (SELECT 1 UNION ALL SELECT 2);
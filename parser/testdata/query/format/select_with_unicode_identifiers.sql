@@ -0,0 +1,12 @@
+-- Origin SQL:
+SELECT
+    имя,
+    count() AS 合计,
+    sum(сумма_2) AS итог
+FROM заказы AS з
+WHERE з.статус = 'done'
+GROUP BY имя;
+
+
+-- Format SQL:
+SELECT имя, count() AS 合计, sum(сумма_2) AS итог FROM заказы AS з WHERE з.статус = 'done' GROUP BY имя;
@@ -12,12 +12,13 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// The package has two independent traversal engines: Accept/ASTVisitor and
-// Walk/WalkFunc. Each encodes every node's children separately, so a new AST
-// node type added to one can silently be forgotten in the other. This test
-// statically asserts that every type with an Accept method also has a Visit
-// method on the ASTVisitor interface and a case in Walk's type switch (and
-// vice versa), so the engines cannot drift.
+// The package has three independent traversal engines: Accept/ASTVisitor,
+// Walk/WalkFunc and the applier behind Rewrite. Each encodes every node's
+// children separately, so a new AST node type added to one can silently be
+// forgotten in another. This test statically asserts that every type with an
+// Accept method also has a Visit method on the ASTVisitor interface and a case
+// in the type switches of Walk and applyChildren (and vice versa), so the
+// engines cannot drift.
 func TestTraversalEnginesCoverSameNodeTypes(t *testing.T) {
 	entries, err := os.ReadDir(".")
 	require.NoError(t, err)
@@ -26,6 +27,7 @@ func TestTraversalEnginesCoverSameNodeTypes(t *testing.T) {
 	acceptTypes := map[string]bool{}
 	visitorTypes := map[string]bool{}
 	walkTypes := map[string]bool{}
+	applyTypes := map[string]bool{}
 
 	for _, entry := range entries {
 		name := entry.Name()
@@ -45,20 +47,9 @@ func TestTraversalEnginesCoverSameNodeTypes(t *testing.T) {
 						}
 					}
 				case d.Name.Name == "Walk" && d.Recv == nil:
-					ast.Inspect(d.Body, func(n ast.Node) bool {
-						cc, ok := n.(*ast.CaseClause)
-						if !ok {
-							return true
-						}
-						for _, expr := range cc.List {
-							if star, ok := expr.(*ast.StarExpr); ok {
-								if ident, ok := star.X.(*ast.Ident); ok {
-									walkTypes[ident.Name] = true
-								}
-							}
-						}
-						return true
-					})
+					collectCaseTypes(d.Body, walkTypes)
+				case d.Name.Name == "applyChildren" && d.Recv != nil:
+					collectCaseTypes(d.Body, applyTypes)
 				}
 			case *ast.GenDecl:
 				for _, spec := range d.Specs {
@@ -98,6 +89,29 @@ func TestTraversalEnginesCoverSameNodeTypes(t *testing.T) {
 		"types with an Accept method but no case in Walk's type switch")
 	require.Empty(t, diffSet(walkTypes, acceptTypes),
 		"types with a case in Walk's type switch but no Accept method")
+	require.Empty(t, diffSet(walkTypes, applyTypes),
+		"types with a case in Walk's type switch but none in applyChildren's")
+	require.Empty(t, diffSet(applyTypes, walkTypes),
+		"types with a case in applyChildren's type switch but none in Walk's")
+}
+
+// collectCaseTypes records the pointer types named by the case clauses of the
+// type switches in body, into out.
+func collectCaseTypes(body *ast.BlockStmt, out map[string]bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		cc, ok := n.(*ast.CaseClause)
+		if !ok {
+			return true
+		}
+		for _, expr := range cc.List {
+			if star, ok := expr.(*ast.StarExpr); ok {
+				if ident, ok := star.X.(*ast.Ident); ok {
+					out[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
 }
 
 // diffSet returns the members of a that are not in b, sorted.
@@ -138,9 +152,11 @@ func TestVisitJoinTableExprWithSampleRatio(t *testing.T) {
 
 // TestTraversalEnginesVisitSameFields statically asserts that, for every node
 // type, the set of child fields referenced by its Accept method matches the
-// set referenced by its case in Walk's type switch. The type-level test above
-// cannot catch a child field that one engine traverses and the other forgot
-// (e.g. Walk missing InsertStmt.Values while Accept visits it).
+// set referenced by its case in Walk's type switch, and that applyChildren
+// names the same fields (as selectors or as apply/applyList arguments). The
+// type-level test above cannot catch a child field that one engine traverses
+// and the other forgot (e.g. Walk missing InsertStmt.Values while Accept
+// visits it).
 func TestTraversalEnginesVisitSameFields(t *testing.T) {
 	entries, err := os.ReadDir(".")
 	require.NoError(t, err)
@@ -148,6 +164,7 @@ func TestTraversalEnginesVisitSameFields(t *testing.T) {
 	fset := token.NewFileSet()
 	acceptFields := map[string]map[string]bool{}
 	walkFields := map[string]map[string]bool{}
+	applyFields := map[string]map[string]bool{}
 
 	// collectSelectors records every selector `<base>.<Field>` in node whose
 	// base is the identifier baseName, into out.
@@ -191,25 +208,13 @@ func TestTraversalEnginesVisitSameFields(t *testing.T) {
 				collectSelectors(d.Body, recvName, fields)
 				acceptFields[typeIdent.Name] = fields
 			case d.Name.Name == "Walk" && d.Recv == nil:
-				ast.Inspect(d.Body, func(n ast.Node) bool {
-					cc, ok := n.(*ast.CaseClause)
-					if !ok {
-						return true
-					}
-					fields := map[string]bool{}
-					for _, stmt := range cc.Body {
-						collectSelectors(stmt, "n", fields)
-					}
-					for _, expr := range cc.List {
-						star, ok := expr.(*ast.StarExpr)
-						if !ok {
-							continue
-						}
-						if ident, ok := star.X.(*ast.Ident); ok {
-							walkFields[ident.Name] = fields
-						}
-					}
-					return true
+				collectCaseFields(d.Body, walkFields, func(stmt ast.Stmt, fields map[string]bool) {
+					collectSelectors(stmt, "n", fields)
+				})
+			case d.Name.Name == "applyChildren" && d.Recv != nil:
+				collectCaseFields(d.Body, applyFields, func(stmt ast.Stmt, fields map[string]bool) {
+					collectSelectors(stmt, "n", fields)
+					collectApplyFieldNames(stmt, fields)
 				})
 			}
 		}
@@ -217,6 +222,7 @@ func TestTraversalEnginesVisitSameFields(t *testing.T) {
 
 	require.NotEmpty(t, acceptFields)
 	require.NotEmpty(t, walkFields)
+	require.NotEmpty(t, applyFields)
 
 	var problems []string
 	for typeName, aFields := range acceptFields {
@@ -233,8 +239,70 @@ func TestTraversalEnginesVisitSameFields(t *testing.T) {
 				typeName+"."+field+" is traversed by Walk but not by Accept")
 		}
 	}
+	for typeName, wFields := range walkFields {
+		aFields, ok := applyFields[typeName]
+		if !ok {
+			continue
+		}
+		for _, field := range diffSet(wFields, aFields) {
+			problems = append(problems,
+				typeName+"."+field+" is traversed by Walk but not by applyChildren")
+		}
+		for _, field := range diffSet(aFields, wFields) {
+			problems = append(problems,
+				typeName+"."+field+" is traversed by applyChildren but not by Walk")
+		}
+	}
 	sort.Strings(problems)
-	require.Empty(t, problems, "child-field traversal drift between Accept and Walk")
+	require.Empty(t, problems, "child-field traversal drift between traversal engines")
+}
+
+// collectCaseFields records, for every case clause of the type switches in
+// body, the fields that collect finds in the clause's statements, keyed by
+// the pointer type the clause names.
+func collectCaseFields(body *ast.BlockStmt, out map[string]map[string]bool, collect func(ast.Stmt, map[string]bool)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		cc, ok := n.(*ast.CaseClause)
+		if !ok {
+			return true
+		}
+		fields := map[string]bool{}
+		for _, stmt := range cc.Body {
+			collect(stmt, fields)
+		}
+		for _, expr := range cc.List {
+			star, ok := expr.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if ident, ok := star.X.(*ast.Ident); ok {
+				out[ident.Name] = fields
+			}
+		}
+		return true
+	})
+}
+
+// collectApplyFieldNames records the field names passed as string literals to
+// the applier's apply and applyList calls on n.
+func collectApplyFieldNames(node ast.Node, out map[string]bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "apply" && sel.Sel.Name != "applyList") {
+			return true
+		}
+		if ident, ok := call.Args[0].(*ast.Ident); !ok || ident.Name != "n" {
+			return true
+		}
+		if lit, ok := call.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			out[strings.Trim(lit.Value, `"`)] = true
+		}
+		return true
+	})
 }
 
 // TestInsertStmtEndWithoutValues guards that End() does not panic on
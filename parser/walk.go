@@ -63,19 +63,14 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.Settings, fn) {
 			return false
 		}
-		if !Walk(n.UnionAll, fn) {
-			return false
-		}
-		if !Walk(n.UnionDistinct, fn) {
-			return false
-		}
-		if !Walk(n.Except, fn) {
+		if !Walk(n.Format, fn) {
 			return false
 		}
-		if !Walk(n.Intersect, fn) {
+	case *SetOperationExpr:
+		if !Walk(n.Left, fn) {
 			return false
 		}
-		if !Walk(n.Format, fn) {
+		if !Walk(n.Right, fn) {
 			return false
 		}
 	case *SubQuery:
@@ -326,6 +321,18 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.ColumnNames, fn) {
 			return false
 		}
+		if !Walk(n.AllColumns, fn) {
+			return false
+		}
+		if !Walk(n.InFile, fn) {
+			return false
+		}
+		if !Walk(n.Compression, fn) {
+			return false
+		}
+		if !Walk(n.Settings, fn) {
+			return false
+		}
 		if !Walk(n.Format, fn) {
 			return false
 		}
@@ -337,12 +344,19 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.SelectExpr, fn) {
 			return false
 		}
+		if !Walk(n.Data, fn) {
+			return false
+		}
 	case *ColumnNamesExpr:
 		for i := range n.ColumnNames {
 			if !Walk(&n.ColumnNames[i], fn) {
 				return false
 			}
 		}
+	case *InsertAllColumnsExpr:
+		if !Walk(n.Except, fn) {
+			return false
+		}
 	case *AssignmentValues:
 		for _, value := range n.Values {
 			if !Walk(value, fn) {
@@ -506,6 +520,10 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.OnCluster, fn) {
 			return false
 		}
+	case *DataPayload:
+		// Leaf node
+	case *BadStmt:
+		// Leaf node
 	case *CheckStmt:
 		if !Walk(n.Table, fn) {
 			return false
@@ -587,6 +605,33 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.OnCluster, fn) {
 			return false
 		}
+	case *RevokeStmt:
+		if !Walk(n.OnCluster, fn) {
+			return false
+		}
+		for _, privilege := range n.Privileges {
+			if !Walk(privilege, fn) {
+				return false
+			}
+		}
+		if !Walk(n.On, fn) {
+			return false
+		}
+		for _, role := range n.Roles {
+			if !Walk(role, fn) {
+				return false
+			}
+		}
+		for _, from := range n.From {
+			if !Walk(from, fn) {
+				return false
+			}
+		}
+		for _, except := range n.Except {
+			if !Walk(except, fn) {
+				return false
+			}
+		}
 	case *PrivilegeClause:
 		if !Walk(n.Params, fn) {
 			return false
@@ -983,6 +1028,9 @@ func Walk(node Expr, fn WalkFunc) bool {
 			return false
 		}
 	case *AlterTableDelete:
+		if !Walk(n.InPartition, fn) {
+			return false
+		}
 		if !Walk(n.WhereClause, fn) {
 			return false
 		}
@@ -998,6 +1046,56 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.WhereClause, fn) {
 			return false
 		}
+	case *AlterTableMovePartition:
+		if !Walk(n.Partition, fn) {
+			return false
+		}
+		if !Walk(n.ToDisk, fn) {
+			return false
+		}
+		if !Walk(n.ToVolume, fn) {
+			return false
+		}
+		if !Walk(n.ToTable, fn) {
+			return false
+		}
+	case *AlterTableFetchPartition:
+		if !Walk(n.Partition, fn) {
+			return false
+		}
+		if !Walk(n.From, fn) {
+			return false
+		}
+	case *AlterTableAddConstraint:
+		if !Walk(n.Name, fn) {
+			return false
+		}
+		if !Walk(n.Expr, fn) {
+			return false
+		}
+	case *AlterTableDropConstraint:
+		if !Walk(n.Name, fn) {
+			return false
+		}
+	case *AlterTableCommentColumn:
+		if !Walk(n.ColumnName, fn) {
+			return false
+		}
+		if !Walk(n.Comment, fn) {
+			return false
+		}
+	case *AlterTableModifyComment:
+		if !Walk(n.Comment, fn) {
+			return false
+		}
+	case *AlterTableModifySampleBy:
+		if !Walk(n.SampleBy, fn) {
+			return false
+		}
+	case *AlterTableApplyDeletedMask:
+		if !Walk(n.InPartition, fn) {
+			return false
+		}
 	case *UpdateAssignment:
 		if !Walk(n.Column, fn) {
 			return false
@@ -1023,6 +1121,194 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.NewName, fn) {
 			return false
 		}
+	case *AlterUser:
+		for _, pair := range n.UserRenamePairs {
+			if !Walk(pair, fn) {
+				return false
+			}
+		}
+		if !Walk(n.Authentication, fn) {
+			return false
+		}
+		if !Walk(n.ValidUntil, fn) {
+			return false
+		}
+		for _, host := range n.Hosts {
+			if !Walk(host, fn) {
+				return false
+			}
+		}
+		for _, host := range n.AddHosts {
+			if !Walk(host, fn) {
+				return false
+			}
+		}
+		for _, host := range n.DropHosts {
+			if !Walk(host, fn) {
+				return false
+			}
+		}
+		if !Walk(n.DefaultRole, fn) {
+			return false
+		}
+		if !Walk(n.DefaultDatabase, fn) {
+			return false
+		}
+		if !Walk(n.Grantees, fn) {
+			return false
+		}
+		for _, setting := range n.Settings {
+			if !Walk(setting, fn) {
+				return false
+			}
+		}
+	case *ToRolesClause:
+		for _, role := range n.Roles {
+			if !Walk(role, fn) {
+				return false
+			}
+		}
+		for _, except := range n.Except {
+			if !Walk(except, fn) {
+				return false
+			}
+		}
+	case *QuotaLimit:
+		if !Walk(n.Interval, fn) {
+			return false
+		}
+		if !Walk(n.Unit, fn) {
+			return false
+		}
+		for _, limit := range n.Limits {
+			if !Walk(limit, fn) {
+				return false
+			}
+		}
+	case *CreateQuota:
+		for _, name := range n.Names {
+			if !Walk(name, fn) {
+				return false
+			}
+		}
+		if !Walk(n.AccessStorageType, fn) {
+			return false
+		}
+		for _, key := range n.KeyedBy {
+			if !Walk(key, fn) {
+				return false
+			}
+		}
+		for _, limit := range n.Limits {
+			if !Walk(limit, fn) {
+				return false
+			}
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
+	case *AlterQuota:
+		for _, pair := range n.RenamePairs {
+			if !Walk(pair, fn) {
+				return false
+			}
+		}
+		for _, key := range n.KeyedBy {
+			if !Walk(key, fn) {
+				return false
+			}
+		}
+		for _, limit := range n.Limits {
+			if !Walk(limit, fn) {
+				return false
+			}
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
+	case *RowPolicyName:
+		if !Walk(n.Name, fn) {
+			return false
+		}
+		if !Walk(n.OnCluster, fn) {
+			return false
+		}
+		if !Walk(n.On, fn) {
+			return false
+		}
+		if !Walk(n.RenameTo, fn) {
+			return false
+		}
+	case *CreateRowPolicy:
+		for _, policy := range n.Policies {
+			if !Walk(policy, fn) {
+				return false
+			}
+		}
+		if !Walk(n.AccessStorageType, fn) {
+			return false
+		}
+		if !Walk(n.Using, fn) {
+			return false
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
+	case *AlterRowPolicy:
+		for _, policy := range n.Policies {
+			if !Walk(policy, fn) {
+				return false
+			}
+		}
+		if !Walk(n.Using, fn) {
+			return false
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
+	case *DropRowPolicy:
+		for _, policy := range n.Policies {
+			if !Walk(policy, fn) {
+				return false
+			}
+		}
+		if !Walk(n.OnCluster, fn) {
+			return false
+		}
+		if !Walk(n.From, fn) {
+			return false
+		}
+	case *CreateSettingsProfile:
+		for _, name := range n.Names {
+			if !Walk(name, fn) {
+				return false
+			}
+		}
+		if !Walk(n.AccessStorageType, fn) {
+			return false
+		}
+		for _, setting := range n.Settings {
+			if !Walk(setting, fn) {
+				return false
+			}
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
+	case *AlterSettingsProfile:
+		for _, pair := range n.RenamePairs {
+			if !Walk(pair, fn) {
+				return false
+			}
+		}
+		for _, setting := range n.Settings {
+			if !Walk(setting, fn) {
+				return false
+			}
+		}
+		if !Walk(n.To, fn) {
+			return false
+		}
 	case *TableSchemaClause:
 		for _, column := range n.Columns {
 			if !Walk(column, fn) {
@@ -1382,11 +1668,12 @@ func Walk(node Expr, fn WalkFunc) bool {
 			return false
 		}
 	case *MapLiteral:
-		for _, kv := range n.KeyValues {
-			if !Walk(&kv.Key, fn) {
+		for i := range n.KeyValues {
+			// Walk the key in place rather than a copy so that edits stick.
+			if !Walk(&n.KeyValues[i].Key, fn) {
 				return false
 			}
-			if !Walk(kv.Value, fn) {
+			if !Walk(n.KeyValues[i].Value, fn) {
 				return false
 			}
 		}
@@ -1561,6 +1848,14 @@ func Walk(node Expr, fn WalkFunc) bool {
 		if !Walk(n.Target, fn) {
 			return false
 		}
+		if !Walk(n.Database, fn) {
+			return false
+		}
+		for _, user := range n.Users {
+			if !Walk(user, fn) {
+				return false
+			}
+		}
 		if !Walk(n.LikePattern, fn) {
 			return false
 		}
@@ -1698,21 +1993,10 @@ func FindAll(root Expr, predicate func(Expr) bool) []Expr {
 	return matches
 }
 
-// Transform applies a transformation function to all nodes in the tree.
-// The transformation function receives a node and should return the transformed node.
-// Note: This modifies the tree in place for mutable fields.
+// Transform rewrites the tree rooted at root bottom-up: transformer is called
+// for every node after its children have been transformed, and the node it
+// returns is installed in place of the original (see Rewrite). It returns the
+// possibly replaced root.
 func Transform(root Expr, transformer func(Expr) Expr) Expr {
-	transformed := transformer(root)
-	if transformed == nil {
-		return nil
-	}
-
-	// Apply transformations to children
-	Walk(transformed, func(node Expr) bool {
-		// The actual in-place transformation would need to be implemented
-		// based on the specific needs and mutability of the AST nodes
-		return true
-	})
-
-	return transformed
+	return Rewrite(root, nil, transformer)
 }
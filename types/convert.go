@@ -0,0 +1,516 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AfterShip/clickhouse-sql-parser/parser"
+)
+
+// Parse parses s as a column type, such as LowCardinality(Nullable(String)),
+// and converts it with FromColumnType.
+func Parse(s string) (*Type, error) {
+	columnType, err := parser.NewParser(s).ParseColumnType()
+	if err != nil {
+		return nil, err
+	}
+	return FromColumnType(columnType)
+}
+
+// FromColumnType converts a column type of the AST into a normalized Type.
+// It resolves aliases, such as INT for Int32 or TEXT for String, fills in
+// default parameters, such as the precision of Decimal or the size of an
+// Enum, and reports an error for an unknown type or for parameters ClickHouse
+// would reject, such as Decimal(80, 2) or Map(Nullable(String), UInt8).
+func FromColumnType(columnType parser.ColumnType) (*Type, error) {
+	t, err := fromColumnType(columnType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %s: %w", parser.Format(columnType), err)
+	}
+	return t, nil
+}
+
+func fromColumnType(columnType parser.ColumnType) (*Type, error) {
+	switch columnType := columnType.(type) {
+	case *parser.ScalarType:
+		return fromName(columnType.Name.Name, nil, nil)
+	case *parser.TypeWithParams:
+		return fromName(columnType.Name.Name, columnType.Params, nil)
+	case *parser.ComplexType:
+		return fromName(columnType.Name.Name, nil, columnType.Params)
+	case *parser.NestedType:
+		return fromNestedType(columnType)
+	case *parser.EnumType:
+		return fromEnumType(columnType)
+	case *parser.JSONType:
+		t := &Type{Kind: JSON}
+		if columnType.Options != nil {
+			// the options are formatted with the type, as in JSON(a.b UInt32)
+			formatted := parser.Format(columnType)
+			options := formatted[strings.IndexByte(formatted, '(')+1:]
+			t.Options = strings.TrimSuffix(options, ")")
+		}
+		return t, nil
+	case nil:
+		return nil, fmt.Errorf("missing type")
+	}
+	return nil, fmt.Errorf("%T is not a data type", columnType)
+}
+
+// kindsByName maps the canonical names of the kinds to them.
+var kindsByName = func() map[string]Kind {
+	kinds := make(map[string]Kind, len(kindNames))
+	for kind, name := range kindNames {
+		if Kind(kind) != Invalid {
+			kinds[name] = Kind(kind)
+		}
+	}
+	return kinds
+}()
+
+// aliases maps the upper-cased aliases ClickHouse accepts for types, mostly
+// for compatibility with other databases, to the canonical names.
+var aliases = map[string]string{
+	"BOOLEAN": "Bool",
+	"TINYINT": "Int8", "INT1": "Int8", "BYTE": "Int8",
+	"SMALLINT": "Int16", "SHORT": "Int16",
+	"INT": "Int32", "INTEGER": "Int32", "MEDIUMINT": "Int32",
+	"BIGINT": "Int64", "SIGNED": "Int64", "UNSIGNED": "UInt64",
+	"FLOAT": "Float32", "REAL": "Float32", "SINGLE": "Float32", "DOUBLE": "Float64",
+	"DEC": "Decimal", "NUMERIC": "Decimal", "FIXED": "Decimal",
+	"TEXT": "String", "TINYTEXT": "String", "MEDIUMTEXT": "String", "LONGTEXT": "String",
+	"BLOB": "String", "TINYBLOB": "String", "MEDIUMBLOB": "String", "LONGBLOB": "String",
+	"CHAR": "String", "VARCHAR": "String", "CHARACTER": "String", "NCHAR": "String",
+	"NVARCHAR": "String", "VARCHAR2": "String", "CLOB": "String", "BYTEA": "String",
+	"VARBINARY": "String", "BINARY": "FixedString", "TIMESTAMP": "DateTime",
+	"INET4": "IPv4", "INET6": "IPv6", "ENUM": "Enum",
+}
+
+// decimalPrecisions are the precisions of the fixed-size Decimal types.
+var decimalPrecisions = map[string]int{
+	"Decimal32": 9, "Decimal64": 18, "Decimal128": 38, "Decimal256": 76,
+}
+
+const maxDecimalPrecision = 76
+
+// resolveName returns the canonical name of a type name or alias.
+func resolveName(name string) string {
+	if _, ok := kindsByName[name]; ok {
+		return name
+	}
+	if _, ok := decimalPrecisions[name]; ok || name == "Enum" {
+		return name
+	}
+	upper := strings.ToUpper(name)
+	if canonical, ok := aliases[upper]; ok {
+		return canonical
+	}
+	for canonical := range kindsByName {
+		if strings.ToUpper(canonical) == upper {
+			return canonical
+		}
+	}
+	for canonical := range decimalPrecisions {
+		if strings.ToUpper(canonical) == upper {
+			return canonical
+		}
+	}
+	return name
+}
+
+// fromName converts the type called name, with the literal parameters
+// params, as in Decimal(10, 2), or the type parameters typeParams, as in
+// Map(String, UInt8).
+func fromName(name string, params []parser.Literal, typeParams []parser.ColumnType) (*Type, error) {
+	canonical := resolveName(name)
+	if precision, ok := decimalPrecisions[canonical]; ok {
+		if len(params) != 1 || len(typeParams) > 0 {
+			return nil, fmt.Errorf("%s needs a scale", canonical)
+		}
+		scale, err := intParam(params[0])
+		if err != nil {
+			return nil, err
+		}
+		return decimalType(precision, scale)
+	}
+	if canonical == "Enum" {
+		return enumFromStrings(params)
+	}
+	kind, ok := kindsByName[canonical]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+	if canonical == "String" && len(typeParams) == 0 {
+		// the length of VARCHAR(255) and the like is ignored
+		return &Type{Kind: String}, nil
+	}
+
+	switch kind {
+	case Array, Nullable, LowCardinality:
+		if len(typeParams) != 1 {
+			return nil, fmt.Errorf("%s needs one type", kind)
+		}
+		elem, err := fromColumnType(typeParams[0])
+		if err != nil {
+			return nil, err
+		}
+		return wrap(kind, elem)
+	case Map:
+		if len(typeParams) != 2 {
+			return nil, fmt.Errorf("Map needs a key and a value type")
+		}
+		key, err := fromColumnType(typeParams[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := fromColumnType(typeParams[1])
+		if err != nil {
+			return nil, err
+		}
+		return mapType(key, value)
+	case Variant:
+		if len(typeParams) == 0 {
+			return nil, fmt.Errorf("Variant needs at least one type")
+		}
+		t := &Type{Kind: Variant}
+		for _, param := range typeParams {
+			variant, err := fromColumnType(param)
+			if err != nil {
+				return nil, err
+			}
+			if variant.Kind == Nullable || variant.Kind == LowCardinality && variant.Elem.Kind == Nullable {
+				return nil, fmt.Errorf("Variant cannot contain %s", variant)
+			}
+			t.Variants = append(t.Variants, variant)
+		}
+		return t, nil
+	case AggregateFunction, SimpleAggregateFunction:
+		if len(typeParams) == 0 {
+			return nil, fmt.Errorf("%s needs a function", kind)
+		}
+		t := &Type{Kind: kind, Function: parser.Format(typeParams[0])}
+		for _, param := range typeParams[1:] {
+			arg, err := fromColumnType(param)
+			if err != nil {
+				return nil, err
+			}
+			t.Args = append(t.Args, arg)
+		}
+		return t, nil
+	case Tuple:
+		if len(params)+len(typeParams) > 0 {
+			return nil, fmt.Errorf("invalid Tuple")
+		}
+		return &Type{Kind: Tuple}, nil
+	}
+	if len(typeParams) > 0 {
+		return nil, fmt.Errorf("%s takes no type parameters", kind)
+	}
+
+	switch kind {
+	case Decimal:
+		precision, scale := 10, 0
+		var err error
+		if len(params) > 2 {
+			return nil, fmt.Errorf("Decimal takes a precision and a scale")
+		}
+		if len(params) > 0 {
+			if precision, err = intParam(params[0]); err != nil {
+				return nil, err
+			}
+		}
+		if len(params) > 1 {
+			if scale, err = intParam(params[1]); err != nil {
+				return nil, err
+			}
+		}
+		return decimalType(precision, scale)
+	case FixedString:
+		if len(params) != 1 {
+			return nil, fmt.Errorf("FixedString needs a size")
+		}
+		size, err := intParam(params[0])
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 {
+			return nil, fmt.Errorf("FixedString size %d must be positive", size)
+		}
+		return &Type{Kind: FixedString, Size: size}, nil
+	case DateTime:
+		if len(params) > 1 {
+			return nil, fmt.Errorf("DateTime takes a time zone only")
+		}
+		t := &Type{Kind: DateTime}
+		if len(params) == 1 {
+			timeZone, err := stringParam(params[0])
+			if err != nil {
+				return nil, err
+			}
+			t.TimeZone = timeZone
+		}
+		return t, nil
+	case DateTime64, Time64:
+		if len(params) == 0 || len(params) > 2 || kind == Time64 && len(params) > 1 {
+			return nil, fmt.Errorf("%s needs a precision", kind)
+		}
+		scale, err := intParam(params[0])
+		if err != nil {
+			return nil, err
+		}
+		if scale < 0 || scale > 9 {
+			return nil, fmt.Errorf("%s precision %d out of range [0, 9]", kind, scale)
+		}
+		t := &Type{Kind: kind, Scale: scale}
+		if len(params) == 2 {
+			if t.TimeZone, err = stringParam(params[1]); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case Enum8, Enum16:
+		t, err := enumFromStrings(params)
+		if err != nil {
+			return nil, err
+		}
+		return t, checkEnum(kind, t.Values)
+	case JSON, Object, Dynamic:
+		t := &Type{Kind: kind}
+		if len(params) > 0 {
+			options := make([]string, 0, len(params))
+			for _, param := range params {
+				options = append(options, parser.Format(param))
+			}
+			t.Options = strings.Join(options, ", ")
+		}
+		return t, nil
+	}
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%s takes no parameters", kind)
+	}
+	return &Type{Kind: kind}, nil
+}
+
+func decimalType(precision, scale int) (*Type, error) {
+	if precision < 1 || precision > maxDecimalPrecision {
+		return nil, fmt.Errorf("Decimal precision %d out of range [1, %d]", precision, maxDecimalPrecision)
+	}
+	if scale < 0 || scale > precision {
+		return nil, fmt.Errorf("Decimal scale %d out of range [0, %d]", scale, precision)
+	}
+	return &Type{Kind: Decimal, Precision: precision, Scale: scale}, nil
+}
+
+// wrap returns Array(elem), Nullable(elem) or LowCardinality(elem), checking
+// that ClickHouse allows elem there.
+func wrap(kind Kind, elem *Type) (*Type, error) {
+	switch kind {
+	case Nullable:
+		if !canBeInsideNullable(elem) {
+			return nil, fmt.Errorf("%s cannot be inside Nullable", elem)
+		}
+	case LowCardinality:
+		inner := elem
+		if inner.Kind == Nullable {
+			inner = inner.Elem
+		}
+		if !canBeInsideLowCardinality(inner) {
+			return nil, fmt.Errorf("%s cannot be inside LowCardinality", elem)
+		}
+	}
+	return &Type{Kind: kind, Elem: elem}, nil
+}
+
+func canBeInsideNullable(t *Type) bool {
+	switch t.Kind {
+	case Array, Tuple, Map, Nested, Nullable, LowCardinality, Variant, Dynamic, JSON, Object,
+		AggregateFunction, SimpleAggregateFunction:
+		return false
+	}
+	return true
+}
+
+func canBeInsideLowCardinality(t *Type) bool {
+	switch {
+	case t.IsNumeric() && t.Kind != Decimal:
+		return true
+	}
+	switch t.Kind {
+	case String, FixedString, Date, Date32, DateTime, UUID, IPv4, IPv6:
+		return true
+	}
+	return false
+}
+
+func mapType(key, value *Type) (*Type, error) {
+	inner := key
+	if inner.Kind == LowCardinality {
+		inner = inner.Elem
+	}
+	switch {
+	case inner.IsInteger():
+	case inner.Kind == String, inner.Kind == FixedString, inner.Kind == UUID, inner.Kind == Date,
+		inner.Kind == Date32, inner.Kind == DateTime, inner.Kind == Enum8, inner.Kind == Enum16,
+		inner.Kind == IPv4, inner.Kind == IPv6, inner.Kind == Bool:
+	default:
+		return nil, fmt.Errorf("%s cannot be a Map key", key)
+	}
+	return &Type{Kind: Map, Key: key, Value: value}, nil
+}
+
+func fromNestedType(nested *parser.NestedType) (*Type, error) {
+	t := &Type{Kind: Tuple}
+	if resolveName(nested.Name.Name) == "Nested" {
+		t.Kind = Nested
+	}
+	for _, column := range nested.Columns {
+		var field Field
+		var err error
+		switch column := column.(type) {
+		case *parser.ColumnDef:
+			field.Name = parser.Format(column.Name)
+			field.Type, err = fromColumnType(column.Type)
+		case parser.ColumnType:
+			if t.Kind == Nested {
+				return nil, fmt.Errorf("Nested columns need names")
+			}
+			field.Type, err = fromColumnType(column)
+		default:
+			return nil, fmt.Errorf("%s is not a %s element", parser.Format(column), t.Kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return t, nil
+}
+
+func fromEnumType(enum *parser.EnumType) (*Type, error) {
+	values := make([]EnumValue, 0, len(enum.Values))
+	for _, value := range enum.Values {
+		n, err := intParam(value.Value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, EnumValue{Name: unescape(value.Name.Literal), Value: n})
+	}
+	kind := enumKind(values)
+	switch resolveName(enum.Name.Name) {
+	case "Enum8":
+		kind = Enum8
+	case "Enum16":
+		kind = Enum16
+	case "Enum":
+	default:
+		return nil, fmt.Errorf("unknown type %s", enum.Name.Name)
+	}
+	if err := checkEnum(kind, values); err != nil {
+		return nil, err
+	}
+	return &Type{Kind: kind, Values: values}, nil
+}
+
+// enumFromStrings converts the values of an Enum without numbers, such as
+// Enum('a', 'b'), which ClickHouse numbers from 1.
+func enumFromStrings(params []parser.Literal) (*Type, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("Enum needs values")
+	}
+	values := make([]EnumValue, 0, len(params))
+	for i, param := range params {
+		name, err := stringParam(param)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, EnumValue{Name: name, Value: i + 1})
+	}
+	kind := enumKind(values)
+	return &Type{Kind: kind, Values: values}, checkEnum(kind, values)
+}
+
+// enumKind returns Enum8 if all of values fit in it, or else Enum16.
+func enumKind(values []EnumValue) Kind {
+	for _, value := range values {
+		if value.Value < -128 || value.Value > 127 {
+			return Enum16
+		}
+	}
+	return Enum8
+}
+
+func checkEnum(kind Kind, values []EnumValue) error {
+	low, high := -128, 127
+	if kind == Enum16 {
+		low, high = -32768, 32767
+	}
+	names := map[string]bool{}
+	numbers := map[int]bool{}
+	for _, value := range values {
+		if value.Value < low || value.Value > high {
+			return fmt.Errorf("%s value %d out of range [%d, %d]", kind, value.Value, low, high)
+		}
+		if names[value.Name] {
+			return fmt.Errorf("duplicate %s name %s", kind, quote(value.Name))
+		}
+		if numbers[value.Value] {
+			return fmt.Errorf("duplicate %s value %d", kind, value.Value)
+		}
+		names[value.Name] = true
+		numbers[value.Value] = true
+	}
+	return nil
+}
+
+func intParam(param parser.Literal) (int, error) {
+	number, ok := param.(*parser.NumberLiteral)
+	if !ok {
+		return 0, fmt.Errorf("%s is not an integer", parser.Format(param))
+	}
+	n, err := strconv.Atoi(number.Literal)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not an integer", number.Literal)
+	}
+	return n, nil
+}
+
+func stringParam(param parser.Literal) (string, error) {
+	s, ok := param.(*parser.StringLiteral)
+	if !ok {
+		return "", fmt.Errorf("%s is not a string", parser.Format(param))
+	}
+	return unescape(s.Literal), nil
+}
+
+// unescape returns the value of a string literal as written between the
+// quotes, resolving backslash escapes and doubled quotes.
+func unescape(literal string) string {
+	if !strings.ContainsAny(literal, `\'`) {
+		return literal
+	}
+	var b strings.Builder
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '\\' && i+1 < len(literal):
+			i++
+			switch literal[i] {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'r':
+				c = '\r'
+			case '0':
+				c = 0
+			default:
+				c = literal[i]
+			}
+		case c == '\'' && i+1 < len(literal) && literal[i+1] == '\'':
+			i++
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
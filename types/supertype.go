@@ -0,0 +1,235 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supertype returns the least common type of types that ClickHouse would
+// infer for them, e.g. for the branches of if() or the elements of an array:
+// Int16 for UInt8 and Int8, Float64 for Int32 and Float32, or
+// Nullable(String) for String and Nullable(FixedString(2)). Nothing, e.g. the
+// element type of an empty array, is skipped, while Nullable(Nothing), the
+// type of NULL, makes the result Nullable. It returns an error if the types
+// have no common type, e.g. String and UInt8.
+func Supertype(types ...*Type) (*Type, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no types")
+	}
+	result := types[0]
+	for _, t := range types[1:] {
+		var err error
+		if result, err = supertype(result, t); err != nil {
+			return nil, fmt.Errorf("no supertype for types %s", joinTypes(types))
+		}
+	}
+	return result, nil
+}
+
+func joinTypes(types []*Type) string {
+	return strings.Join(typeStrings(types), ", ")
+}
+
+func supertype(t, u *Type) (*Type, error) {
+	if t.Equal(u) {
+		return t, nil
+	}
+	if t.Kind == Nothing {
+		return u, nil
+	}
+	if u.Kind == Nothing {
+		return t, nil
+	}
+	t, u = stripLowCardinality(t), stripLowCardinality(u)
+	if t.Kind == Nullable || u.Kind == Nullable {
+		result, err := supertype(stripNullable(t), stripNullable(u))
+		if err != nil {
+			return nil, err
+		}
+		return nullableIfPossible(result, nil), nil
+	}
+	if t.Equal(u) {
+		return t, nil
+	}
+
+	switch {
+	case t.IsInteger() && u.IsInteger():
+		return integerSupertype(t, u)
+	case t.IsNumeric() && u.IsNumeric():
+		return numericSupertype(t, u)
+	}
+	switch {
+	case isString(t) && isString(u):
+		return &Type{Kind: String}, nil
+	case isDate(t) && isDate(u):
+		return dateSupertype(t, u)
+	}
+	if t.Kind != u.Kind {
+		return nil, fmt.Errorf("no supertype")
+	}
+	switch t.Kind {
+	case Array:
+		elem, err := supertype(t.Elem, u.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &Type{Kind: Array, Elem: elem}, nil
+	case Map:
+		key, err := supertype(t.Key, u.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := supertype(t.Value, u.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &Type{Kind: Map, Key: key, Value: value}, nil
+	case Tuple:
+		if len(t.Fields) != len(u.Fields) {
+			return nil, fmt.Errorf("no supertype")
+		}
+		result := &Type{Kind: Tuple}
+		for i, field := range t.Fields {
+			fieldType, err := supertype(field.Type, u.Fields[i].Type)
+			if err != nil {
+				return nil, err
+			}
+			name := field.Name
+			if name != u.Fields[i].Name {
+				name = ""
+			}
+			result.Fields = append(result.Fields, Field{Name: name, Type: fieldType})
+		}
+		// names are kept only if all of them agree
+		for _, field := range result.Fields {
+			if field.Name == "" {
+				for i := range result.Fields {
+					result.Fields[i].Name = ""
+				}
+				break
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("no supertype")
+}
+
+// nullableIfPossible returns Nullable(t) if t can be inside Nullable, or else
+// fallback, or t itself if fallback is nil.
+func nullableIfPossible(t, fallback *Type) *Type {
+	if canBeInsideNullable(t) {
+		return &Type{Kind: Nullable, Elem: t}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return t
+}
+
+func stripNullable(t *Type) *Type {
+	if t.Kind == Nullable {
+		return t.Elem
+	}
+	return t
+}
+
+func stripLowCardinality(t *Type) *Type {
+	if t.Kind == LowCardinality {
+		return t.Elem
+	}
+	return t
+}
+
+var signedBySize = map[int]Kind{8: Int8, 16: Int16, 32: Int32, 64: Int64, 128: Int128, 256: Int256}
+var unsignedBySize = map[int]Kind{8: UInt8, 16: UInt16, 32: UInt32, 64: UInt64, 128: UInt128, 256: UInt256}
+
+func integerSupertype(t, u *Type) (*Type, error) {
+	bits := max(t.Bits(), u.Bits())
+	if t.IsUnsigned() == u.IsUnsigned() {
+		if t.IsUnsigned() {
+			return &Type{Kind: unsignedBySize[bits]}, nil
+		}
+		return &Type{Kind: signedBySize[bits]}, nil
+	}
+	// a signed integer holding the unsigned one needs twice its size
+	unsigned := t
+	if !unsigned.IsUnsigned() {
+		unsigned = u
+	}
+	bits = max(bits, 2*unsigned.Bits())
+	if bits > 256 {
+		return nil, fmt.Errorf("no supertype")
+	}
+	return &Type{Kind: signedBySize[bits]}, nil
+}
+
+func numericSupertype(t, u *Type) (*Type, error) {
+	if t.Kind == Decimal || u.Kind == Decimal {
+		if t.IsFloat() || u.IsFloat() {
+			return nil, fmt.Errorf("no supertype")
+		}
+		return decimalSupertype(t, u)
+	}
+	// one is a float, the other a float or an integer
+	if t.Kind == Float64 || u.Kind == Float64 {
+		return &Type{Kind: Float64}, nil
+	}
+	other := u
+	if !t.IsFloat() {
+		other = t
+	}
+	if other.IsFloat() {
+		// Float32 and BFloat16
+		return &Type{Kind: Float32}, nil
+	}
+	if other.Bits() <= 16 {
+		return &Type{Kind: Float32}, nil
+	}
+	return &Type{Kind: Float64}, nil
+}
+
+// integerDigits are the numbers of decimal digits of the integer types.
+var integerDigits = map[int]int{8: 3, 16: 5, 32: 10, 64: 20, 128: 39, 256: 78}
+
+func decimalSupertype(t, u *Type) (*Type, error) {
+	digits := func(t *Type) (int, int) {
+		if t.Kind == Decimal {
+			return t.Precision - t.Scale, t.Scale
+		}
+		return integerDigits[t.Bits()], 0
+	}
+	tInt, tScale := digits(t)
+	uInt, uScale := digits(u)
+	scale := max(tScale, uScale)
+	precision := max(tInt, uInt) + scale
+	if precision > maxDecimalPrecision {
+		return nil, fmt.Errorf("no supertype")
+	}
+	return &Type{Kind: Decimal, Precision: precision, Scale: scale}, nil
+}
+
+func isString(t *Type) bool {
+	return t.Kind == String || t.Kind == FixedString
+}
+
+func isDate(t *Type) bool {
+	return t.Kind == Date || t.Kind == Date32 || t.Kind == DateTime || t.Kind == DateTime64
+}
+
+func dateSupertype(t, u *Type) (*Type, error) {
+	if t.Kind > u.Kind {
+		t, u = u, t
+	}
+	switch {
+	case u.Kind == Date32:
+		return u, nil
+	case u.Kind == DateTime && t.Kind == Date:
+		return u, nil
+	case t.Kind == Date || t.Kind == Date32:
+		// u is a DateTime64, or a DateTime too small for a Date32
+		return &Type{Kind: DateTime64, Scale: u.Scale, TimeZone: u.TimeZone}, nil
+	case t.TimeZone != u.TimeZone:
+		return nil, fmt.Errorf("no supertype")
+	}
+	return &Type{Kind: u.Kind, Scale: max(t.Scale, u.Scale), TimeZone: u.TimeZone}, nil
+}
@@ -0,0 +1,260 @@
+// Package types models ClickHouse data types semantically. The parser
+// represents a type as written, e.g. INT, Decimal32(2) or
+// Tuple(a Int32, b String); FromColumnType turns such a parser.ColumnType
+// into a *Type, resolving aliases, normalizing parameters and validating
+// them, so that types can be compared and combined.
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kind is the family of a Type. The kinds of the types without parameters
+// are the types themselves.
+type Kind int
+
+const (
+	Invalid Kind = iota
+	Nothing
+	Bool
+	Int8
+	Int16
+	Int32
+	Int64
+	Int128
+	Int256
+	UInt8
+	UInt16
+	UInt32
+	UInt64
+	UInt128
+	UInt256
+	BFloat16
+	Float32
+	Float64
+	Decimal // Decimal(Precision, Scale)
+	String
+	FixedString // FixedString(Size)
+	UUID
+	IPv4
+	IPv6
+	Date
+	Date32
+	DateTime   // DateTime or DateTime(TimeZone)
+	DateTime64 // DateTime64(Scale) or DateTime64(Scale, TimeZone)
+	Time
+	Time64 // Time64(Scale)
+	Enum8  // Enum8(Values)
+	Enum16 // Enum16(Values)
+	Array  // Array(Elem)
+	Tuple  // Tuple(Fields)
+	Map    // Map(Key, Value)
+	Nested // Nested(Fields)
+	Nullable
+	LowCardinality
+	Variant // Variant(Variants)
+	Dynamic
+	JSON // JSON or JSON(Options)
+	Object
+	AggregateFunction       // AggregateFunction(Function, Args)
+	SimpleAggregateFunction // SimpleAggregateFunction(Function, Args)
+	Point
+	Ring
+	LineString
+	MultiLineString
+	Polygon
+	MultiPolygon
+)
+
+var kindNames = [...]string{
+	Invalid: "Invalid", Nothing: "Nothing", Bool: "Bool",
+	Int8: "Int8", Int16: "Int16", Int32: "Int32", Int64: "Int64", Int128: "Int128", Int256: "Int256",
+	UInt8: "UInt8", UInt16: "UInt16", UInt32: "UInt32", UInt64: "UInt64", UInt128: "UInt128", UInt256: "UInt256",
+	BFloat16: "BFloat16", Float32: "Float32", Float64: "Float64", Decimal: "Decimal",
+	String: "String", FixedString: "FixedString", UUID: "UUID", IPv4: "IPv4", IPv6: "IPv6",
+	Date: "Date", Date32: "Date32", DateTime: "DateTime", DateTime64: "DateTime64", Time: "Time", Time64: "Time64",
+	Enum8: "Enum8", Enum16: "Enum16", Array: "Array", Tuple: "Tuple", Map: "Map", Nested: "Nested",
+	Nullable: "Nullable", LowCardinality: "LowCardinality", Variant: "Variant", Dynamic: "Dynamic",
+	JSON: "JSON", Object: "Object", AggregateFunction: "AggregateFunction",
+	SimpleAggregateFunction: "SimpleAggregateFunction", Point: "Point", Ring: "Ring",
+	LineString: "LineString", MultiLineString: "MultiLineString", Polygon: "Polygon", MultiPolygon: "MultiPolygon",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// Type is a normalized ClickHouse data type. Only the fields its Kind
+// documents are set.
+type Type struct {
+	Kind Kind
+
+	// Precision is the number of digits of a Decimal.
+	Precision int
+	// Scale is the number of fractional digits of a Decimal, DateTime64 or
+	// Time64.
+	Scale int
+	// Size is the length in bytes of a FixedString.
+	Size int
+	// TimeZone is the time zone of a DateTime or DateTime64, if given.
+	TimeZone string
+
+	// Elem is the element type of an Array, Nullable or LowCardinality.
+	Elem *Type
+	// Key and Value are the types of a Map.
+	Key, Value *Type
+	// Fields are the elements of a Tuple, whose names may be empty, or the
+	// columns of a Nested.
+	Fields []Field
+	// Values are the values of an Enum8 or Enum16, in the order declared.
+	Values []EnumValue
+	// Variants are the types of a Variant.
+	Variants []*Type
+
+	// Function is the aggregate function of an AggregateFunction or
+	// SimpleAggregateFunction, with its parameters, e.g. quantiles(0.5, 0.9),
+	// and Args are the types of its arguments.
+	Function string
+	Args     []*Type
+
+	// Options are the parameters of a JSON, Object or Dynamic type as
+	// written, e.g. max_dynamic_paths=10.
+	Options string
+}
+
+// Field is an element of a Tuple or a column of a Nested.
+type Field struct {
+	Name string
+	Type *Type
+}
+
+// EnumValue is a value of an Enum8 or Enum16.
+type EnumValue struct {
+	Name  string
+	Value int
+}
+
+// String returns the canonical form of t, e.g. Nullable(Decimal(9, 2)).
+func (t *Type) String() string {
+	var b strings.Builder
+	t.write(&b)
+	return b.String()
+}
+
+func (t *Type) write(b *strings.Builder) {
+	b.WriteString(t.Kind.String())
+	var params []string
+	switch t.Kind {
+	case Decimal:
+		params = []string{strconv.Itoa(t.Precision), strconv.Itoa(t.Scale)}
+	case FixedString:
+		params = []string{strconv.Itoa(t.Size)}
+	case DateTime:
+		if t.TimeZone != "" {
+			params = []string{quote(t.TimeZone)}
+		}
+	case DateTime64:
+		params = []string{strconv.Itoa(t.Scale)}
+		if t.TimeZone != "" {
+			params = append(params, quote(t.TimeZone))
+		}
+	case Time64:
+		params = []string{strconv.Itoa(t.Scale)}
+	case Enum8, Enum16:
+		for _, value := range t.Values {
+			params = append(params, quote(value.Name)+" = "+strconv.Itoa(value.Value))
+		}
+	case Array, Nullable, LowCardinality:
+		params = []string{t.Elem.String()}
+	case Map:
+		params = []string{t.Key.String(), t.Value.String()}
+	case Tuple, Nested:
+		for _, field := range t.Fields {
+			if field.Name != "" {
+				params = append(params, field.Name+" "+field.Type.String())
+			} else {
+				params = append(params, field.Type.String())
+			}
+		}
+	case Variant:
+		params = typeStrings(t.Variants)
+	case AggregateFunction, SimpleAggregateFunction:
+		params = append([]string{t.Function}, typeStrings(t.Args)...)
+	case JSON, Object, Dynamic:
+		if t.Options != "" {
+			params = []string{t.Options}
+		}
+	}
+	if params == nil && t.Kind != Tuple {
+		return
+	}
+	b.WriteByte('(')
+	b.WriteString(strings.Join(params, ", "))
+	b.WriteByte(')')
+}
+
+func typeStrings(types []*Type) []string {
+	strs := make([]string, 0, len(types))
+	for _, t := range types {
+		strs = append(strs, t.String())
+	}
+	return strs
+}
+
+// quote writes s as a single-quoted string.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// Equal reports whether t and u are the same type. Aliases and equivalent
+// spellings compare equal once normalized, e.g. INT and Int32, or
+// Decimal32(2) and Decimal(9, 2).
+func (t *Type) Equal(u *Type) bool {
+	if t == nil || u == nil {
+		return t == u
+	}
+	return t.String() == u.String()
+}
+
+// IsInteger reports whether t is a signed or unsigned integer type.
+func (t *Type) IsInteger() bool {
+	return t.Kind >= Int8 && t.Kind <= UInt256
+}
+
+// IsUnsigned reports whether t is an unsigned integer type.
+func (t *Type) IsUnsigned() bool {
+	return t.Kind >= UInt8 && t.Kind <= UInt256
+}
+
+// IsFloat reports whether t is a floating-point type.
+func (t *Type) IsFloat() bool {
+	return t.Kind == BFloat16 || t.Kind == Float32 || t.Kind == Float64
+}
+
+// IsNumeric reports whether t is an integer, floating-point or Decimal type.
+func (t *Type) IsNumeric() bool {
+	return t.IsInteger() || t.IsFloat() || t.Kind == Decimal
+}
+
+// Bits returns the size of an integer or floating-point type in bits, or 0.
+func (t *Type) Bits() int {
+	switch t.Kind {
+	case Int8, UInt8:
+		return 8
+	case Int16, UInt16, BFloat16:
+		return 16
+	case Int32, UInt32, Float32:
+		return 32
+	case Int64, UInt64, Float64:
+		return 64
+	case Int128, UInt128:
+		return 128
+	case Int256, UInt256:
+		return 256
+	}
+	return 0
+}
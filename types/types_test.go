@@ -0,0 +1,178 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for input, expected := range map[string]string{
+		"INT":                              "Int32",
+		"bigint":                           "Int64",
+		"TEXT":                             "String",
+		"VARCHAR(255)":                     "String",
+		"BINARY(16)":                       "FixedString(16)",
+		"TIMESTAMP":                        "DateTime",
+		"boolean":                          "Bool",
+		"Decimal":                          "Decimal(10, 0)",
+		"Decimal32(2)":                     "Decimal(9, 2)",
+		"NUMERIC(20, 4)":                   "Decimal(20, 4)",
+		"DateTime('UTC')":                  "DateTime('UTC')",
+		"DateTime64(3, 'Asia/Shanghai')":   "DateTime64(3, 'Asia/Shanghai')",
+		"LowCardinality(Nullable(String))": "LowCardinality(Nullable(String))",
+		"Array(Nullable(INT))":             "Array(Nullable(Int32))",
+		"Map(String, Array(UInt8))":        "Map(String, Array(UInt8))",
+		"Tuple(a Int32, b String)":         "Tuple(a Int32, b String)",
+		"Tuple(Int32, TEXT)":               "Tuple(Int32, String)",
+		"Tuple()":                          "Tuple()",
+		"Nested(id UInt64, name String)":   "Nested(id UInt64, name String)",
+		"Enum('a', 'b')":                   "Enum8('a' = 1, 'b' = 2)",
+		"Enum('a' = 1, 'b' = 1000)":        "Enum16('a' = 1, 'b' = 1000)",
+		"Enum8('it''s' = -1)":              "Enum8('it\\'s' = -1)",
+		"Variant(String, UInt64)":          "Variant(String, UInt64)",
+		"AggregateFunction(quantiles(0.5, 0.9), UInt64)": "AggregateFunction(quantiles(0.5, 0.9), UInt64)",
+		"SimpleAggregateFunction(sum, Double)":           "SimpleAggregateFunction(sum, Float64)",
+		"JSON":                                           "JSON",
+		"JSON(max_dynamic_paths=10)":                     "JSON(max_dynamic_paths=10)",
+	} {
+		typ, err := Parse(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, typ.String(), input)
+	}
+}
+
+func TestParseStructure(t *testing.T) {
+	typ, err := Parse("Map(LowCardinality(String), Tuple(a Int32, b Array(String)))")
+	require.NoError(t, err)
+	require.Equal(t, Map, typ.Kind)
+	require.Equal(t, LowCardinality, typ.Key.Kind)
+	require.Equal(t, String, typ.Key.Elem.Kind)
+	require.Equal(t, Tuple, typ.Value.Kind)
+	require.Equal(t, "b", typ.Value.Fields[1].Name)
+	require.Equal(t, String, typ.Value.Fields[1].Type.Elem.Kind)
+
+	typ, err = Parse("DateTime64(6)")
+	require.NoError(t, err)
+	require.Equal(t, 6, typ.Scale)
+	require.Empty(t, typ.TimeZone)
+}
+
+func TestParseErrors(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Foo":                           "invalid type Foo: unknown type Foo",
+		"Decimal(80, 2)":                "Decimal precision 80 out of range [1, 76]",
+		"Decimal(5, 6)":                 "Decimal scale 6 out of range [0, 5]",
+		"Decimal32":                     "Decimal32 needs a scale",
+		"DateTime64(10)":                "DateTime64 precision 10 out of range [0, 9]",
+		"DateTime64":                    "DateTime64 needs a precision",
+		"FixedString(0)":                "FixedString size 0 must be positive",
+		"Int32(5)":                      "Int32 takes no parameters",
+		"Nullable(Array(String))":       "Array(String) cannot be inside Nullable",
+		"Nullable(Nullable(String))":    "Nullable(String) cannot be inside Nullable",
+		"LowCardinality(Array(String))": "Array(String) cannot be inside LowCardinality",
+		"Map(Nullable(String), UInt8)":  "Nullable(String) cannot be a Map key",
+		"Map(String)":                   "Map needs a key and a value type",
+		"Enum8('a' = 1, 'b' = 1)":       "duplicate Enum8 value 1",
+		"Enum8('a' = 1, 'a' = 2)":       "duplicate Enum8 name 'a'",
+		"Enum8('a' = 200)":              "Enum8 value 200 out of range [-128, 127]",
+		"Array(Decimal(0, 0))":          "Decimal precision 0 out of range [1, 76]",
+	} {
+		_, err := Parse(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), expected, input)
+	}
+
+	_, err := Parse("Array(")
+	require.Error(t, err)
+	_, err = Parse("String String")
+	require.Error(t, err)
+}
+
+func TestEqual(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"INT", "Int32"},
+		{"Decimal32(2)", "Decimal(9, 2)"},
+		{"Enum('a', 'b')", "Enum8('a' = 1, 'b' = 2)"},
+		{"Array(TEXT)", "Array(String)"},
+	} {
+		a, err := Parse(pair[0])
+		require.NoError(t, err)
+		b, err := Parse(pair[1])
+		require.NoError(t, err)
+		require.True(t, a.Equal(b), pair)
+	}
+
+	a, _ := Parse("DateTime('UTC')")
+	b, _ := Parse("DateTime")
+	require.False(t, a.Equal(b))
+}
+
+func TestSupertype(t *testing.T) {
+	for _, test := range []struct {
+		types    []string
+		expected string
+	}{
+		{[]string{"UInt8", "UInt32"}, "UInt32"},
+		{[]string{"Int8", "Int64"}, "Int64"},
+		{[]string{"UInt8", "Int8"}, "Int16"},
+		{[]string{"UInt32", "Int16"}, "Int64"},
+		{[]string{"UInt128", "Int8"}, "Int256"},
+		{[]string{"Int32", "Float32"}, "Float64"},
+		{[]string{"Int16", "Float32"}, "Float32"},
+		{[]string{"Float32", "Float64"}, "Float64"},
+		{[]string{"Decimal(9, 2)", "Decimal(18, 4)"}, "Decimal(18, 4)"},
+		{[]string{"Decimal(9, 2)", "Int32"}, "Decimal(12, 2)"},
+		{[]string{"String", "FixedString(2)"}, "String"},
+		{[]string{"String", "Nullable(FixedString(2))"}, "Nullable(String)"},
+		{[]string{"LowCardinality(String)", "String"}, "String"},
+		{[]string{"Nothing", "Int8"}, "Int8"},
+		{[]string{"Array(Nothing)", "Array(Int8)"}, "Array(Int8)"},
+		{[]string{"Nullable(Nothing)", "Int8"}, "Nullable(Int8)"},
+		{[]string{"Array(Nullable(Nothing))", "Array(Int8)"}, "Array(Nullable(Int8))"},
+		{[]string{"Nullable(Nothing)", "Array(Int8)"}, "Array(Int8)"},
+		{[]string{"Date", "DateTime"}, "DateTime"},
+		{[]string{"Date", "Date32"}, "Date32"},
+		{[]string{"Date32", "DateTime"}, "DateTime64(0)"},
+		{[]string{"DateTime", "DateTime64(3)", "DateTime64(6)"}, "DateTime64(6)"},
+		{[]string{"Array(UInt8)", "Array(Int8)"}, "Array(Int16)"},
+		{[]string{"Map(String, UInt8)", "Map(String, Float64)"}, "Map(String, Float64)"},
+		{[]string{"Tuple(a UInt8, b String)", "Tuple(a Int8, b String)"}, "Tuple(a Int16, b String)"},
+		{[]string{"Tuple(a UInt8)", "Tuple(b UInt8)"}, "Tuple(UInt8)"},
+	} {
+		types := make([]*Type, 0, len(test.types))
+		for _, input := range test.types {
+			typ, err := Parse(input)
+			require.NoError(t, err, input)
+			types = append(types, typ)
+		}
+		result, err := Supertype(types...)
+		require.NoError(t, err, test.types)
+		require.Equal(t, test.expected, result.String(), test.types)
+	}
+}
+
+func TestSupertypeErrors(t *testing.T) {
+	for _, test := range [][]string{
+		{"String", "UInt8"},
+		{"UInt256", "Int8"},
+		{"Decimal(9, 2)", "Float64"},
+		{"Array(String)", "Array(UInt8)"},
+		{"Tuple(UInt8)", "Tuple(UInt8, UInt8)"},
+		{"DateTime('UTC')", "DateTime('Asia/Shanghai')"},
+	} {
+		types := make([]*Type, 0, len(test))
+		for _, input := range test {
+			typ, err := Parse(input)
+			require.NoError(t, err, input)
+			types = append(types, typ)
+		}
+		_, err := Supertype(types...)
+		require.Error(t, err, test)
+	}
+
+	a, _ := Parse("String")
+	b, _ := Parse("UInt8")
+	_, err := Supertype(a, b)
+	require.EqualError(t, err, "no supertype for types String, UInt8")
+}